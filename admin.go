@@ -0,0 +1,157 @@
+package elogging
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// AdminHandler return an http.Handler exposing the package administration endpoints,
+// mount it with http.StripPrefix, e.g. mux.Handle("/debug/elog/", http.StripPrefix("/debug/elog", elogging.AdminHandler())):
+//  GET  /state                       the package state as JSON (see StateJSON)
+//  POST /level?level=trace[&scope=db] change the global level or the level of a scope (pattern or alias)
+//  GET  /tail?scope=db&level=trace   stream the records of the matching scopes live as server-sent events
+//  GET  /query?scope=db&level=warning&since=2006-01-02T15:04:05Z&q=timeout
+//                                    the records retained by the ring buffer (see Query) as JSON lines
+func AdminHandler() http.Handler {
+	return _defaultRegistry.AdminHandler()
+}
+
+// AdminHandler return an http.Handler exposing the administration endpoints of the registry, see the package AdminHandler
+func (reg *Registry) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", reg._adminState)
+	mux.HandleFunc("/query", reg._adminQuery)
+	mux.HandleFunc("/level", reg._adminLevel)
+	mux.HandleFunc("/tail", reg._adminTail)
+	return mux
+}
+
+func (reg *Registry) _adminState(w http.ResponseWriter, r *http.Request) {
+	data, err := reg.StateJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (reg *Registry) _adminLevel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		http.Error(w, "use POST to change a level", http.StatusMethodNotAllowed)
+		return
+	}
+	level, scope := r.FormValue("level"), r.FormValue("scope")
+	if _, err := _parseLevel(level); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	t := reg._frozen()
+	if t != nil && !t._check(r.Header.Get("X-Elog-Token")) {
+		http.Error(w, "configuration frozen", http.StatusForbidden)
+		return
+	}
+	if scope == "" {
+		reg._setGlobalLogLevel(t, level)
+	} else {
+		reg._setScopeLogLevel(t, scope, level)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (reg *Registry) _adminTail(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	scope, level := reg.ResolveScope(r.FormValue("scope")), r.FormValue("level")
+	if level == "" {
+		level = LEVEL_Trace
+	} else if _, err := _parseLevel(level); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records := make(chan []byte, TailBufferSize)
+	var tails []*Tail
+	for _, e := range reg.ListScopedLogs() {
+		if scope != "" && !_matchScope(scope, e._scope()) {
+			continue
+		}
+		t := e.TailReader(level)
+		tails = append(tails, t)
+		go func() {
+			for buf := range t.ch {
+				select {
+				case records <- buf:
+				default: // the client is too slow, drop the record
+				}
+			}
+		}()
+	}
+	defer func() {
+		for _, t := range tails {
+			t.Close()
+		}
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprintf(w, ": tailing %d logs\n\n", len(tails))
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case buf := <-records:
+			w.Write(_sseEvent(buf))
+			flusher.Flush()
+		}
+	}
+}
+
+func (reg *Registry) _adminQuery(w http.ResponseWriter, r *http.Request) {
+	if reg._ring == nil {
+		http.Error(w, "ring buffer not enabled", http.StatusNotFound)
+		return
+	}
+	level := r.FormValue("level")
+	if level != "" {
+		if _, err := _parseLevel(level); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	var since time.Time
+	if s := r.FormValue("since"); s != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, s); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	records := reg.Query(r.FormValue("scope"), level, since, r.FormValue("q"))
+	buf := make([]byte, 0, 256)
+	for i := range records {
+		buf = _formatJSON(buf[:0], log.LUTC|log.Lmicroseconds|log.Llongfile, &records[i])
+		w.Write(buf)
+	}
+}
+
+// _sseEvent render a record as a single server-sent event, a multi-line record is sent as
+// consecutive data lines so it is delivered whole
+func _sseEvent(rec []byte) []byte {
+	rec = bytes.TrimRight(rec, "\n")
+	buf := make([]byte, 0, len(rec)+16)
+	for _, line := range bytes.Split(rec, []byte("\n")) {
+		buf = append(buf, "data: "...)
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+	return append(buf, '\n')
+}
@@ -0,0 +1,73 @@
+package elogging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdminHandler(t *testing.T) {
+	elog := NewElog("TestAdmin.db", "info", &bytes.Buffer{})
+	defer elog.Clear()
+	elog.SetFlags(0)
+	srv := httptest.NewServer(http.StripPrefix("/elog", AdminHandler()))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/elog/level?scope=TestAdmin.*&level=trace", "", nil)
+	if err != nil || resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("level change failed %v %v", resp, err)
+	}
+	if elog.GetLevel() != "Trace" {
+		t.Errorf("expected trace level, got %s", elog.GetLevel())
+	}
+
+	resp, err = http.Get(srv.URL + "/elog/state")
+	if err != nil || !strings.Contains(readAll(resp), `"scope":"TestAdmin.db"`) {
+		t.Errorf("unexpected state response %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/elog/tail?scope=TestAdmin.db&level=info", nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	r := bufio.NewReader(resp.Body)
+	if line, _ := r.ReadString('\n'); !strings.HasPrefix(line, ": tailing 1 logs") {
+		t.Fatalf("unexpected tail preamble %q", line)
+	}
+	elog.Trace("filtered")
+	elog.Info("streamed")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(line, "data: ") {
+			if line != "data: TestAdmin.db (INFO) streamed\n" {
+				t.Errorf("unexpected tail event %q", line)
+			}
+			break
+		}
+	}
+}
+
+func readAll(resp *http.Response) string {
+	defer resp.Body.Close()
+	b := &bytes.Buffer{}
+	b.ReadFrom(resp.Body)
+	return b.String()
+}
+
+func TestSSEEvent(t *testing.T) {
+	if got := string(_sseEvent([]byte("a (ERROR) x err=2 errors:\n    - a\n    - b\n"))); got != "data: a (ERROR) x err=2 errors:\ndata:     - a\ndata:     - b\n\n" {
+		t.Errorf("unexpected event %q", got)
+	}
+}
@@ -0,0 +1,89 @@
+package elogging
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// SetAudit turn the auditing of the configuration changes of the registry on or off, see the package SetAudit
+func (r *Registry) SetAudit(on bool) {
+	if !on {
+		r._auditLog = nil
+	} else if r._auditLog == nil {
+		r._auditLog = r._newElog("elogging.audit", LEVEL_Info, os.Stderr)
+	}
+}
+
+// SetAudit make every configuration change (levels, global level and mode, logs on/off, flags, formats, outputs,
+// defaults and level overrides) emit an AUDIT record on a dedicated Elog (scope "elogging.audit", writing to stderr,
+// not registered) with the setting, its previous and new values and the caller which changed it, e.g.
+//  elogging.audit (AUDIT) level changed scope=db from=Info to=Trace caller="main.debugDB (main.go:42)"
+func SetAudit(on bool) {
+	_defaultRegistry.SetAudit(on)
+}
+
+// AuditLog return the Elog of the audit records of the registry, nil when auditing is off
+func (r *Registry) AuditLog() *Elog {
+	return r._auditLog
+}
+
+// AuditLog return the Elog of the audit records (see SetAudit) to change its output or flags, nil when auditing is off
+func AuditLog() *Elog {
+	return _defaultRegistry._auditLog
+}
+
+// _audit record the change of a setting of the registry (empty scope) or of an Elog when auditing is on
+func (r *Registry) _audit(scope, setting, from, to string) {
+	a := r._auditLog
+	if a == nil || from == to {
+		return
+	}
+	fields := make([]FieldT, 0, 4)
+	if scope != "" {
+		fields = append(fields, String("scope", scope))
+	}
+	fields = append(fields, String("from", from), String("to", to), String("caller", _externalCaller()))
+	a._emit(1, lInfo, "AUDIT", setting+" changed", fields)
+}
+
+// _audit record the change of a setting of the Elog when auditing is on, the internal Elogs are not audited
+func (e *Elog) _audit(setting, from, to string) {
+	if r := e._reg; r._auditLog != nil && e != r._auditLog && e != r._debug {
+		r._audit(e._scope(), setting, from, to)
+	}
+}
+
+// _pkgDir is the directory of the package sources
+var _pkgDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return path.Dir(file)
+}()
+
+// _externalCaller describe the first caller outside of the package (tests included),
+// or the outermost function of the package for changes made by the package itself
+func _externalCaller() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	var last runtime.Frame
+	for {
+		f, more := frames.Next()
+		if path.Dir(f.File) == _pkgDir && !strings.HasSuffix(f.File, "_test.go") {
+			last = f
+		} else if !strings.HasPrefix(f.Function, "runtime.") {
+			last = f
+			break
+		}
+		if !more {
+			break
+		}
+	}
+	return fmt.Sprintf("%s (%s:%d)", last.Function[strings.LastIndexByte(last.Function, '/')+1:], path.Base(last.File), last.Line)
+}
+
+// _flagsString render flags for the audit records
+func _flagsString(flags int) string {
+	return fmt.Sprintf("%#x", flags)
+}
@@ -0,0 +1,54 @@
+package elogging
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetAudit(t *testing.T) {
+	r := NewRegistry()
+	db := r.NewElog("TestAudit.db", "info", io.Discard)
+	db.SetLevel("trace")
+	if r.AuditLog() != nil {
+		t.Fatal("audit log while off")
+	}
+
+	r.SetAudit(true)
+	audit := &bytes.Buffer{}
+	r.AuditLog().ModifyParams("", "", audit)
+	r.AuditLog().SetFlags(0)
+	db.SetLevel("verbose")
+	db.SetLevel("verbose")
+	r.SetScopeLogLevel("TestAudit.*", "error")
+	r.SetGlobalLogLevel("warning")
+	r.LogsOff()
+	db.SetFlags(ELJSONLog)
+	r.SetScopeLevelOverride("TestAudit.db", "trace")
+	db.SetCompat(FamilyPrint, CompatStdlib)
+	db.SetPrintGate(PrintAtLevel, "verbose")
+
+	lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+	for i, expected := range []string{
+		`elogging.audit (AUDIT) level changed scope=TestAudit.db from=Trace to=Verbose caller="elogging.TestSetAudit (audit_test.go:22)"`,
+		`level changed scope=TestAudit.db from=Verbose to=Error caller="elogging.TestSetAudit (audit_test.go:24)"`,
+		`global_level changed from=Disabled to=Warning caller="elogging.TestSetAudit (audit_test.go:25)"`,
+		`logs_active changed from=true to=false`,
+		`flags changed scope=TestAudit.db from=0x`,
+		`level_override changed scope=TestAudit.db from="" to=Trace`,
+		`compat changed scope=TestAudit.db from="print elogging" to="print stdlib"`,
+		`print_gate changed scope=TestAudit.db from="bypass Info" to="level Verbose"`,
+	} {
+		if i >= len(lines) || !strings.Contains(lines[i], expected) {
+			t.Errorf("expected %q in %q", expected, lines)
+		}
+	}
+	if len(lines) != 8 {
+		t.Errorf("expected 8 audit records, got %d", len(lines))
+	}
+	r.SetAudit(false)
+	if r.AuditLog() != nil {
+		t.Error("audit log still on")
+	}
+}
@@ -0,0 +1,132 @@
+package elogging
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// BenchmarkSink is a null destination counting the records and bytes it receives, use it as the output of an Elog
+// (io.Writer, one record per Write) or as a sink to measure the emit path without any I/O
+type BenchmarkSink struct {
+	enc     Encoder
+	records uint64
+	bytes   uint64
+}
+
+// NewBenchmarkSink create a null sink, records received as a sink are rendered by enc (nil to skip the rendering)
+func NewBenchmarkSink(enc Encoder) *BenchmarkSink {
+	return &BenchmarkSink{enc: enc}
+}
+
+// Write count p as a rendered record and discard it
+func (s *BenchmarkSink) Write(p []byte) (int, error) {
+	atomic.AddUint64(&s.records, 1)
+	atomic.AddUint64(&s.bytes, uint64(len(p)))
+	return len(p), nil
+}
+
+// WriteRecord render the record with the sink encoder, count it and discard it
+func (s *BenchmarkSink) WriteRecord(rec *Record) error {
+	if s.enc == nil {
+		atomic.AddUint64(&s.records, 1)
+		return nil
+	}
+	_, err := s.Write(s.enc.Encode(nil, rec))
+	return err
+}
+
+// Records return the number of records received
+func (s *BenchmarkSink) Records() uint64 {
+	return atomic.LoadUint64(&s.records)
+}
+
+// Bytes return the number of rendered bytes received
+func (s *BenchmarkSink) Bytes() uint64 {
+	return atomic.LoadUint64(&s.bytes)
+}
+
+// Reset zero the counters
+func (s *BenchmarkSink) Reset() {
+	atomic.StoreUint64(&s.records, 0)
+	atomic.StoreUint64(&s.bytes, 0)
+}
+
+// BenchmarkPath is an emit path measured by MeasureEmit and elogbench.BenchmarkEmit
+type BenchmarkPath int
+
+const (
+	BenchText       BenchmarkPath = iota // Info with a formatted message, default text format
+	BenchJSON                            // Info with a formatted message, JSON format
+	BenchStructured                      // InfoKV with typed fields, JSON format
+)
+
+// BenchmarkPaths is the list of the measured emit paths
+var BenchmarkPaths = []BenchmarkPath{BenchText, BenchJSON, BenchStructured}
+
+func (p BenchmarkPath) String() string {
+	switch p {
+	case BenchText:
+		return "text"
+	case BenchJSON:
+		return "json"
+	case BenchStructured:
+		return "structured"
+	}
+	return "unknown"
+}
+
+// BenchmarkResult is the measure of an emit path
+type BenchmarkResult struct {
+	Path                BenchmarkPath
+	Records             int
+	Duration            time.Duration
+	RecordsPerSec       float64
+	AllocsPerRecord     float64
+	BytesPerRecord      float64 // rendered bytes
+	AllocBytesPerRecord float64 // allocated bytes
+}
+
+var _benchErr = errors.New("connection reset")
+
+// BenchmarkEmitter return a function emitting the i-th record of the path to sink with an Elog of an isolated
+// registry, the loop body of MeasureEmit and of the benchmarks of the elogbench package
+func BenchmarkEmitter(path BenchmarkPath, sink *BenchmarkSink) func(i int) {
+	e := NewRegistry().NewElog("bench", LEVEL_Info, sink)
+	if path != BenchText {
+		e.SetFlags(e.GetFlags() | ELJSONLog)
+	}
+	if path == BenchStructured {
+		return func(i int) {
+			e.InfoKV("request served", String("method", "GET"), Int("status", 200), Duration("elapsed", time.Millisecond),
+				Err("err", _benchErr))
+		}
+	}
+	return func(i int) { e.Infof("request %d served in %v", i, time.Millisecond) }
+}
+
+// MeasureEmit emit n records on the path to a null output and measure the throughput and the allocations,
+// use it to compare builds or to size asynchronous buffers from the records rate of a service
+func MeasureEmit(path BenchmarkPath, n int) BenchmarkResult {
+	sink := &BenchmarkSink{}
+	emit := BenchmarkEmitter(path, sink)
+	emit(0) // warm up
+	sink.Reset()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		emit(i)
+	}
+	d := time.Since(start)
+	runtime.ReadMemStats(&after)
+	res := BenchmarkResult{Path: path, Records: n, Duration: d}
+	if n > 0 {
+		res.RecordsPerSec = float64(n) / d.Seconds()
+		res.AllocsPerRecord = float64(after.Mallocs-before.Mallocs) / float64(n)
+		res.BytesPerRecord = float64(sink.Bytes()) / float64(n)
+		res.AllocBytesPerRecord = float64(after.TotalAlloc-before.TotalAlloc) / float64(n)
+	}
+	return res
+}
@@ -0,0 +1,31 @@
+package elogging
+
+import (
+	"testing"
+)
+
+func TestBenchmarkSink(t *testing.T) {
+	s := NewBenchmarkSink(FlagsEncoder(ELJSONLog))
+	elog := NewEphemeralElog("TestBenchmarkSink", "info", s)
+	elog.AddSink(s)
+	elog.Info("msg")
+	if s.Records() != 2 || s.Bytes() == 0 {
+		t.Errorf("unexpected counts %d records, %d bytes", s.Records(), s.Bytes())
+	}
+	s.Reset()
+	if s.Records() != 0 || s.Bytes() != 0 {
+		t.Error("counters not reset")
+	}
+}
+
+func TestMeasureEmit(t *testing.T) {
+	for _, p := range BenchmarkPaths {
+		res := MeasureEmit(p, 100)
+		if res.Path != p || res.Records != 100 || res.RecordsPerSec <= 0 || res.BytesPerRecord < 20 {
+			t.Errorf("unexpected %s result %+v", p, res)
+		}
+	}
+	if BenchmarkPath(9).String() != "unknown" {
+		t.Error("unexpected unknown path name")
+	}
+}
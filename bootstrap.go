@@ -0,0 +1,118 @@
+package elogging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BootstrapReceiver is implemented by the sinks which are log destinations (WriterSink, FileSink, ForwardSink):
+// added to an Elog, the first of them receives the records held by the bootstrap buffer when ReceivesBootstrap
+// returns true (see EnableBootstrapBuffer)
+type BootstrapReceiver interface {
+	Sink
+	ReceivesBootstrap() bool
+}
+
+// _bootstrapBuffer hold the records emitted during the startup until the first log destination sink is added
+type _bootstrapBuffer struct {
+	mu      sync.Mutex
+	records []*Record
+	max     int
+	dropped int
+	done    bool
+}
+
+// EnableBootstrapBuffer hold the first n records emitted by the Elogs of the registry until the first log destination
+// sink is added, see the package EnableBootstrapBuffer
+func (r *Registry) EnableBootstrapBuffer(n int) {
+	if n <= 0 {
+		r._setBootstrap(nil)
+		return
+	}
+	r._setBootstrap(&_bootstrapBuffer{max: n})
+}
+
+// EnableBootstrapBuffer hold the first n records emitted by any Elog (still written to their outputs, usually
+// stderr) until the real destination of the logs is configured, so the earliest startup lines reach it too: the
+// first log destination sink added to an Elog (see BootstrapReceiver and AddSink) receives the held records, oldest
+// first, and the buffer is released; tails and the other sinks don't take them. Records beyond n are not held, the
+// sink then receives a warning record giving their count. Call it first thing in main, n <= 0 disable it;
+// FlushBootstrapBuffer flush the records to any sink explicitly.
+func EnableBootstrapBuffer(n int) {
+	_defaultRegistry.EnableBootstrapBuffer(n)
+}
+
+// FlushBootstrapBuffer write the records held by the bootstrap buffer of the registry to the sink and release it,
+// see the package FlushBootstrapBuffer
+func (r *Registry) FlushBootstrapBuffer(s Sink) error {
+	b := r._getBootstrap()
+	if b == nil {
+		return nil
+	}
+	records, dropped, done := b.release(r, nil)
+	if done {
+		return nil
+	}
+	if err := _deliver(s, records, dropped); err != nil {
+		return fmt.Errorf("elogging: %w", err)
+	}
+	return nil
+}
+
+// FlushBootstrapBuffer write the records held by the bootstrap buffer (see EnableBootstrapBuffer) to the sink and
+// release it, later records are no longer held; nothing is written once the buffer was released
+func FlushBootstrapBuffer(s Sink) error {
+	return _defaultRegistry.FlushBootstrapBuffer(s)
+}
+
+// add hold a record until the buffer is released and return the sinks of the Elog, read under the lock of the
+// buffer: a record is either held or written to the sinks added when the buffer was released
+func (b *_bootstrapBuffer) add(e *Elog, rec *Record) []Sink {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case b.done:
+	case len(b.records) < b.max:
+		b.records = append(b.records, rec.Clone())
+	default:
+		b.dropped++
+	}
+	return e._getSinks()
+}
+
+// release take the held records and release the buffer, once, detaching it from the registry so later records no
+// longer go through it; then (e.g. adding a sink) is called before any later record reads the sinks, done report
+// whether the buffer was already released
+func (b *_bootstrapBuffer) release(r *Registry, then func()) (records []*Record, dropped int, done bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	records, dropped, done = b.records, b.dropped, b.done
+	b.records, b.done = nil, true
+	r._detachBootstrap(b)
+	if then != nil {
+		then()
+	}
+	return records, dropped, done
+}
+
+// _deliver write the released records to the sink, followed by a warning giving the count of the records not kept
+func _deliver(s Sink, records []*Record, dropped int) error {
+	var errs []error
+	for _, rec := range records {
+		if err := s.WriteRecord(rec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if dropped > 0 {
+		rec := Record{Time: time.Now(), Scope: "elogging", Tag: _valid(LEVEL_Warning), level: lWarn,
+			Msg: fmt.Sprintf("bootstrap buffer full, %d startup records not kept", dropped)}
+		if err := s.WriteRecord(&rec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("bootstrap buffer flush: %d records failed, first: %w", len(errs), errs[0])
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package elogging
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// _recordsSink keep the records it receives
+type _recordsSink struct {
+	records []Record
+}
+
+func (s *_recordsSink) WriteRecord(rec *Record) error {
+	s.records = append(s.records, *rec.Clone())
+	return nil
+}
+
+func (s *_recordsSink) ReceivesBootstrap() bool {
+	return true
+}
+
+func TestBootstrapBuffer(t *testing.T) {
+	r := NewRegistry()
+	r.EnableBootstrapBuffer(2)
+	early := r.NewElog("TestBootstrapBuffer.early", "info", io.Discard)
+	early.Info("starting")
+	early.Info("config loaded")
+	early.Info("listening")
+
+	s := &_recordsSink{}
+	main := r.NewElog("TestBootstrapBuffer", "info", io.Discard)
+	main.AddSink(s)
+	main.Info("ready")
+	got := s.records
+	if len(got) != 4 || got[0].Msg != "starting" || got[1].Msg != "config loaded" || got[3].Msg != "ready" {
+		t.Fatalf("unexpected records %v", got)
+	}
+	if got[2].level != lWarn {
+		t.Errorf("expected a warning for the records not kept, got %v", got[2])
+	}
+	if r._getBootstrap() != nil {
+		t.Error("expected the released buffer detached from the registry")
+	}
+	other := &_recordsSink{}
+	main.AddSink(other)
+	if len(other.records) != 0 {
+		t.Errorf("expected the buffer flushed once, got %v", other.records)
+	}
+}
+
+// _countSink count the records it receives, safe for concurrent use
+type _countSink struct {
+	n int64
+}
+
+func (s *_countSink) ReceivesBootstrap() bool {
+	return true
+}
+
+func (s *_countSink) WriteRecord(rec *Record) error {
+	atomic.AddInt64(&s.n, 1)
+	time.Sleep(10 * time.Microsecond)
+	return nil
+}
+
+func TestBootstrapBufferConcurrentAddSink(t *testing.T) {
+	const writers, records = 8, 200
+	r := NewRegistry()
+	r.EnableBootstrapBuffer(writers * records)
+	elog := r.NewElog("TestBootstrapBufferConcurrentAddSink", "info", io.Discard)
+	s := &_countSink{}
+	var wg sync.WaitGroup
+	started := make(chan struct{}, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < records; j++ {
+				if j == records/4 {
+					started <- struct{}{}
+				}
+				elog.Info("record")
+			}
+		}()
+	}
+	<-started
+	elog.AddSink(s)
+	wg.Wait()
+	if n := atomic.LoadInt64(&s.n); n != writers*records {
+		t.Errorf("expected every record once, the sink received %d of %d", n, writers*records)
+	}
+}
+
+func TestBootstrapBufferTailFirst(t *testing.T) {
+	r := NewRegistry()
+	r.EnableBootstrapBuffer(10)
+	elog := r.NewElog("TestBootstrapBufferTailFirst", "info", io.Discard)
+	elog.Info("starting")
+	tail := elog.TailReader("info")
+	defer tail.Close()
+	counter := &BenchmarkSink{}
+	elog.AddSink(counter)
+	b := &bytes.Buffer{}
+	elog.AddSink(NewWriterSink(b, FlagsEncoder(0), ""))
+	if !strings.Contains(b.String(), "starting") || counter.Records() != 0 {
+		t.Errorf("expected the startup records in the writer sink only, got %q and %d records", b.String(), counter.Records())
+	}
+}
@@ -0,0 +1,130 @@
+package elogging
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// BreakerPolicy select what a BreakerSink does with the records while its sink is suspended
+type BreakerPolicy int
+
+const (
+	BreakerDrop   BreakerPolicy = iota // the records are dropped, WriteRecord returns ErrSinkSuspended
+	BreakerBuffer                      // the records are kept (up to the buffer limit) and written once the sink heals
+)
+
+// ErrSinkSuspended is returned by a BreakerSink for the records dropped while its sink is suspended
+var ErrSinkSuspended = errors.New("elogging: sink suspended by its circuit breaker")
+
+// _breakerBufferLimit is the default count of records a BreakerSink buffers while its sink is suspended
+const _breakerBufferLimit = 1024
+
+// BreakerSink is a circuit breaker around a sink, usually a remote one: after a number of consecutive failures the
+// writes to the sink are suspended for a cool-down period rather than attempted (and timing out) on every record
+// while its collector is down. The first record after the cool-down probes the sink, on success the buffered records
+// are written followed by a recovery record (msg "sink recovered", the down duration and the count of dropped
+// records), on failure the sink is suspended again.
+//  elog.AddSink(elogging.NewBreakerSink(remote, 5, 30*time.Second, elogging.BreakerBuffer))
+type BreakerSink struct {
+	sink      Sink
+	failures  int
+	cooldown  time.Duration
+	policy    BreakerPolicy
+	bufferMax int
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failed   int       // consecutive failures
+	openedAt time.Time // zero while the sink is not suspended
+	downAt   time.Time // first suspension of the current outage
+	buffer   []*Record
+	dropped  uint64
+}
+
+// NewBreakerSink create a circuit breaker suspending the writes to sink for cooldown after failures consecutive
+// failures (at least 1), the records received meanwhile are dropped or buffered according to policy
+func NewBreakerSink(sink Sink, failures int, cooldown time.Duration, policy BreakerPolicy) *BreakerSink {
+	if failures < 1 {
+		failures = 1
+	}
+	return &BreakerSink{sink: sink, failures: failures, cooldown: cooldown, policy: policy,
+		bufferMax: _breakerBufferLimit, now: time.Now}
+}
+
+// SetBufferLimit set the count of records buffered while the sink is suspended (BreakerBuffer policy), the oldest
+// records are dropped beyond it
+func (s *BreakerSink) SetBufferLimit(n int) {
+	s.mu.Lock()
+	s.bufferMax = n
+	s.mu.Unlock()
+}
+
+// Suspended report whether the writes to the sink are currently suspended
+func (s *BreakerSink) Suspended() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.openedAt.IsZero() && s.now().Sub(s.openedAt) < s.cooldown
+}
+
+// WriteRecord write the record to the sink, or drop or buffer it while the sink is suspended
+func (s *BreakerSink) WriteRecord(rec *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if !s.openedAt.IsZero() {
+		if now.Sub(s.openedAt) < s.cooldown {
+			return s._hold(rec)
+		}
+		return s._probe(now, rec)
+	}
+	err := s.sink.WriteRecord(rec)
+	if err == nil {
+		s.failed = 0
+		return nil
+	}
+	if s.failed++; s.failed >= s.failures {
+		s.openedAt, s.downAt = now, now
+		_internalf("sink %T suspended for %s after %d failures: %v", s.sink, s.cooldown, s.failed, err)
+	}
+	return err
+}
+
+// _hold drop or buffer a record while the sink is suspended
+func (s *BreakerSink) _hold(rec *Record) error {
+	if s.policy != BreakerBuffer {
+		s.dropped++
+		return ErrSinkSuspended
+	}
+	if s.bufferMax <= 0 {
+		s.dropped++
+		return nil
+	}
+	if len(s.buffer) >= s.bufferMax {
+		s.buffer = append(s.buffer[:0], s.buffer[1:]...)
+		s.dropped++
+	}
+	s.buffer = append(s.buffer, rec.Clone())
+	return nil
+}
+
+// _probe try the sink after the cool-down with the buffered records then rec, it stays suspended on failure
+func (s *BreakerSink) _probe(now time.Time, rec *Record) error {
+	for len(s.buffer) > 0 {
+		if err := s.sink.WriteRecord(s.buffer[0]); err != nil {
+			s.openedAt = now
+			return s._hold(rec)
+		}
+		s.buffer[0] = nil
+		s.buffer = s.buffer[1:]
+	}
+	if err := s.sink.WriteRecord(rec); err != nil {
+		s.openedAt = now
+		return err
+	}
+	recovery := Record{Time: now, Scope: rec.Scope, Tag: _valid(LEVEL_Info), Msg: "sink recovered", level: lInfo,
+		Fields: []FieldT{Duration("down", now.Sub(s.downAt)), Uint64("dropped", s.dropped)}}
+	s.sink.WriteRecord(&recovery)
+	s.openedAt, s.downAt, s.failed, s.dropped, s.buffer = time.Time{}, time.Time{}, 0, 0, nil
+	return nil
+}
@@ -0,0 +1,57 @@
+package elogging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// _flakySink record the messages it writes, failing while down
+type _flakySink struct {
+	down     bool
+	attempts int
+	msgs     []string
+}
+
+func (s *_flakySink) WriteRecord(rec *Record) error {
+	s.attempts++
+	if s.down {
+		return errors.New("collector unreachable")
+	}
+	s.msgs = append(s.msgs, rec.Msg)
+	return nil
+}
+
+func TestBreakerSink(t *testing.T) {
+	for _, policy := range []BreakerPolicy{BreakerDrop, BreakerBuffer} {
+		sink := &_flakySink{down: true}
+		s := NewBreakerSink(sink, 2, time.Minute, policy)
+		now := time.Now()
+		s.now = func() time.Time { return now }
+
+		s.WriteRecord(&Record{Msg: "1"})
+		s.WriteRecord(&Record{Msg: "2"})
+		err := s.WriteRecord(&Record{Msg: "3"})
+		if !s.Suspended() || sink.attempts != 2 {
+			t.Fatalf("expected the sink suspended after 2 attempts, got %d attempts", sink.attempts)
+		}
+		if (policy == BreakerDrop) != errors.Is(err, ErrSinkSuspended) {
+			t.Errorf("policy %d: unexpected error %v", policy, err)
+		}
+
+		now = now.Add(2 * time.Minute)
+		sink.down = false
+		s.WriteRecord(&Record{Msg: "4"})
+		if s.Suspended() {
+			t.Error("expected the sink healed")
+		}
+		expected := "4,sink recovered"
+		if policy == BreakerBuffer {
+			expected = "3,4,sink recovered"
+		}
+		if got := strings.Join(sink.msgs, ","); got != expected {
+			t.Errorf("policy %d: expected %s, got %s", policy, expected, got)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package elogging
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// BundleOptions select the identifying details of the process written to a support bundle, all left out by default
+type BundleOptions struct {
+	Host bool // the hostname and pid
+	Args bool // the command line arguments, the values of secret looking arguments (password, token, ...) redacted
+}
+
+// _bundleInfo is the description of the process in a support bundle
+type _bundleInfo struct {
+	Time      time.Time `json:"time"`
+	Hostname  string    `json:"hostname,omitempty"`
+	PID       int       `json:"pid,omitempty"`
+	Args      []string  `json:"args,omitempty"`
+	GoVersion string    `json:"go_version"`
+	Platform  string    `json:"platform"`
+}
+
+// _secretArg match the command line arguments holding a secret: the value of a secret looking flag (given after
+// = or as the next argument) and the password of a URL
+var (
+	_secretFlag = regexp.MustCompile(`(?i)^(-{1,2}[\w.-]*(?:pass|secret|token|key|auth|cred|dsn)[\w.-]*)(=.*)?$`)
+	_urlSecret  = regexp.MustCompile(`(://[^:/@\s]*):[^@/\s]*@`)
+)
+
+// _redactArgs return the command line arguments with their secrets replaced by ***
+func _redactArgs(args []string) []string {
+	out := make([]string, len(args))
+	for i := 0; i < len(args); i++ {
+		out[i] = _urlSecret.ReplaceAllString(args[i], "$1:***@")
+		m := _secretFlag.FindStringSubmatch(args[i])
+		switch {
+		case m == nil:
+		case m[2] != "":
+			out[i] = m[1] + "=***"
+		case i+1 < len(args) && !strings.HasPrefix(args[i+1], "-"):
+			i++
+			out[i] = "***"
+		}
+	}
+	return out
+}
+
+// WriteSupportBundle write a zip archive of the logging state of the registry to w, see the package WriteSupportBundle
+func (r *Registry) WriteSupportBundle(w io.Writer) error {
+	return r.WriteSupportBundleWith(w, BundleOptions{})
+}
+
+// WriteSupportBundleWith write a zip archive of the logging state of the registry to w with the details of the
+// process selected by opts, see the package WriteSupportBundle
+func (r *Registry) WriteSupportBundleWith(w io.Writer, opts BundleOptions) error {
+	zw := zip.NewWriter(w)
+	now := time.Now()
+	add := func(name string, data []byte) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+	addJSON := func(name string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return add(name, append(data, '\n'))
+	}
+
+	info := _bundleInfo{Time: now, GoVersion: runtime.Version(), Platform: runtime.GOOS + "/" + runtime.GOARCH}
+	if opts.Host {
+		info.Hostname, _ = os.Hostname()
+		info.PID = os.Getpid()
+	}
+	if opts.Args {
+		info.Args = _redactArgs(os.Args)
+	}
+	state, err := r.StateJSON()
+	if err != nil {
+		return fmt.Errorf("elogging: support bundle: %w", err)
+	}
+	var dump bytes.Buffer
+	r.DumpState(&dump)
+	stats := map[string]Stats{}
+	for _, e := range r.ListScopedLogs() {
+		stats[e._scope()+" "+e._id[:8]] = e.Stats()
+	}
+	var records []byte
+	if r._ring != nil {
+		recent := r.Query("", "", time.Time{}, "")
+		for i := range recent {
+			records = _formatJSON(records, log.LUTC|log.Lmicroseconds|log.Llongfile, &recent[i])
+		}
+	}
+
+	for _, err := range []error{
+		addJSON("info.json", info),
+		add("state.json", state),
+		add("state.txt", dump.Bytes()),
+		addJSON("config.json", r.Snapshot()),
+		addJSON("stats.json", stats),
+		add("records.jsonl", records),
+	} {
+		if err != nil {
+			return fmt.Errorf("elogging: support bundle: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("elogging: support bundle: %w", err)
+	}
+	return nil
+}
+
+// WriteSupportBundle write a zip archive of the logging state to w, a single artifact to attach to a support request:
+//  info.json      time, Go version and platform of the process (host, pid and arguments with WriteSupportBundleWith)
+//  state.json     the package state (see StateJSON)
+//  state.txt      the human readable state (see DumpState)
+//  config.json    the configuration of the package and of the Elogs (see Snapshot)
+//  stats.json     the stats of each Elog keyed by scope and id (see Elog.Stats)
+//  records.jsonl  the records retained by the ring buffer as JSON lines, empty when not enabled (see EnableRingBuffer)
+// e.g. from an admin endpoint:
+//  w.Header().Set("Content-Type", "application/zip")
+//  elogging.WriteSupportBundle(w)
+// The bundle is meant to be sent out, it holds no identifying details of the process unless selected with
+// WriteSupportBundleWith; the recent records are included as logged.
+func WriteSupportBundle(w io.Writer) error {
+	return _defaultRegistry.WriteSupportBundle(w)
+}
+
+// WriteSupportBundleWith write a support bundle (see WriteSupportBundle) with the details of the process selected
+// by opts, e.g. the hostname, pid and redacted arguments:
+//  elogging.WriteSupportBundleWith(w, elogging.BundleOptions{Host: true, Args: true})
+func WriteSupportBundleWith(w io.Writer, opts BundleOptions) error {
+	return _defaultRegistry.WriteSupportBundleWith(w, opts)
+}
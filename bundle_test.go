@@ -0,0 +1,82 @@
+package elogging
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWriteSupportBundle(t *testing.T) {
+	r := NewRegistry()
+	r.EnableRingBuffer(10)
+	elog := r.NewElog("TestWriteSupportBundle", "info", io.Discard)
+	elog.Warn("disk almost full")
+
+	files := _readBundle(t, r, BundleOptions{})
+	for name, expected := range map[string]string{
+		"info.json":     `"go_version"`,
+		"state.json":    `"scope":"TestWriteSupportBundle"`,
+		"state.txt":     "TestWriteSupportBundle",
+		"config.json":   `"Scope": "TestWriteSupportBundle"`,
+		"stats.json":    "TestWriteSupportBundle ",
+		"records.jsonl": `"msg":"disk almost full"`,
+	} {
+		if !strings.Contains(files[name], expected) {
+			t.Errorf("expected %s in %s, got %q", expected, name, files[name])
+		}
+	}
+}
+
+// _readBundle return the files of the support bundle of the registry
+func _readBundle(t *testing.T, r *Registry, opts BundleOptions) map[string]string {
+	var b bytes.Buffer
+	if err := r.WriteSupportBundleWith(&b, opts); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+	return files
+}
+
+func TestSupportBundleSecrets(t *testing.T) {
+	saved := os.Args
+	defer func() { os.Args = saved }()
+	os.Args = []string{"app", "--password=hunter2", "-api-token", "tok123", "--db", "postgres://u:pw42@db/x", "-v", "run"}
+	r := NewRegistry()
+	hostname, _ := os.Hostname()
+
+	files := _readBundle(t, r, BundleOptions{})
+	info := files["info.json"]
+	if strings.Contains(info, `"args"`) || strings.Contains(info, `"pid"`) || hostname != "" && strings.Contains(info, hostname) {
+		t.Errorf("unexpected process details %q", info)
+	}
+
+	files = _readBundle(t, r, BundleOptions{Host: true, Args: true})
+	for name, data := range files {
+		for _, secret := range []string{"hunter2", "tok123", "pw42"} {
+			if strings.Contains(data, secret) {
+				t.Errorf("secret %q in %s: %q", secret, name, data)
+			}
+		}
+	}
+	for _, expected := range []string{`"--password=***"`, `"-api-token",`, `"postgres://u:***@db/x"`, `"run"`, `"pid"`} {
+		if !strings.Contains(files["info.json"], expected) {
+			t.Errorf("expected %s in %q", expected, files["info.json"])
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package elogging
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// BytesRendering is the rendering of []byte values in fields and in the arguments of the logging calls
+type BytesRendering int
+
+const (
+	BytesHex     BytesRendering = iota // hexadecimal (default)
+	BytesBase64                        // standard base64
+	BytesSummary                       // <bytes len=4096 sha1=...>
+	BytesRaw                           // the golang default rendering, raw bytes may corrupt text logs
+)
+
+// _defaultBytesMax is the default maximum number of bytes rendered by BytesHex and BytesBase64
+const _defaultBytesMax = 64
+
+// SetBytesRendering change the rendering of []byte values of the Elogs of the registry, see the package SetBytesRendering
+func (r *Registry) SetBytesRendering(mode BytesRendering, maxLen int) {
+	r._bytesMode, r._bytesMax = mode, maxLen
+}
+
+// SetBytesRendering change the rendering of []byte values in fields and arguments, maxLen is the maximum number
+// of bytes rendered in hex or base64 (0 for no limit), longer values are truncated and suffixed with ...(+n bytes).
+// The arguments of the f calls are only rendered for %v, the other verbs (%s, %x, %q, ...) format them as usual.
+func SetBytesRendering(mode BytesRendering, maxLen int) {
+	_defaultRegistry.SetBytesRendering(mode, maxLen)
+}
+
+// _renderBytes return the rendering of b
+func (r *Registry) _renderBytes(b []byte) string {
+	if r._bytesMode == BytesSummary {
+		return "<bytes len=" + strconv.Itoa(len(b)) + " sha1=" + hex.EncodeToString(_sha1(b)) + ">"
+	}
+	var rest int
+	if r._bytesMax > 0 && len(b) > r._bytesMax {
+		b, rest = b[:r._bytesMax], len(b)-r._bytesMax
+	}
+	var s string
+	if r._bytesMode == BytesBase64 {
+		s = base64.StdEncoding.EncodeToString(b)
+	} else {
+		s = hex.EncodeToString(b)
+	}
+	if rest > 0 {
+		s += "...(+" + strconv.Itoa(rest) + " bytes)"
+	}
+	return s
+}
+
+// bytesArg is a []byte argument of a logging call, rendered according to SetBytesRendering with %v (the non f
+// calls) and as the golang default with the other verbs, e.g. %s or %x keep their meaning in the f calls
+type bytesArg struct {
+	b        []byte
+	rendered string
+}
+
+func (a bytesArg) Format(f fmt.State, verb rune) {
+	if verb == 'v' && !f.Flag('#') {
+		io.WriteString(f, a.rendered)
+		return
+	}
+	fmt.Fprintf(f, _directive(f, verb), a.b)
+}
+
+// _directive return the formatting directive of the verb with the flags, width and precision of f
+func _directive(f fmt.State, verb rune) string {
+	d := []byte{'%'}
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			d = append(d, byte(flag))
+		}
+	}
+	if w, ok := f.Width(); ok {
+		d = strconv.AppendInt(d, int64(w), 10)
+	}
+	if p, ok := f.Precision(); ok {
+		d = append(d, '.')
+		d = strconv.AppendInt(d, int64(p), 10)
+	}
+	return string(append(d, string(verb)...))
+}
+
+func _sha1(b []byte) []byte {
+	h := sha1.Sum(b)
+	return h[:]
+}
+
+// _renderBytesFields return the fields with their []byte values replaced by their rendering, fields itself when
+// it has none, fields is not modified
+func (r *Registry) _renderBytesFields(fields []FieldT) []FieldT {
+	if r._bytesMode == BytesRaw {
+		return fields
+	}
+	var out []FieldT
+	for i := range fields {
+		b, ok := fields[i].any.([]byte)
+		if !ok || fields[i].kind != kindAny {
+			continue
+		}
+		if out == nil {
+			out = append([]FieldT(nil), fields...)
+		}
+		out[i] = String(fields[i].Key, r._renderBytes(b))
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
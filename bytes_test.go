@@ -0,0 +1,36 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBytesRendering(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestBytesRendering", "info", b)
+	elog.SetFlags(0)
+	data := []byte{0xde, 0xad, 0xbe, 0xef, 0x00}
+
+	elog.Info("data ", data)
+	elog.InfoKV("data", Field("payload", data))
+	r.SetBytesRendering(BytesBase64, 3)
+	elog.Infof("data %v", data)
+	r.SetBytesRendering(BytesSummary, 0)
+	elog.InfoKV("data", Any("payload", data))
+
+	elog.Infof("body %s %x %q %5.2s|", []byte("hello"), []byte("hi"), []byte("a"), []byte("hello"))
+
+	for _, expected := range []string{
+		"(INFO) body hello 6869 \"a\"    he|\n",
+		"(INFO) data deadbeef00\n",
+		"(INFO) data payload=deadbeef00\n",
+		"(INFO) data 3q2+...(+2 bytes)\n",
+		"(INFO) data payload=\"<bytes len=5 sha1=",
+	} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("expected %q in %q", expected, b.String())
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package elogging
+
+import "sync/atomic"
+
+// CanarySink is a sink routing every record to a primary sink and a percentage of them to a canary sink, e.g. to try
+// a new format or backend on a share of the real traffic before migrating the log pipeline to it:
+//  s := elogging.NewCanarySink(current, elogging.NewWriterSink(conn, newEncoder, ""), 5)
+//  elog.AddSink(s)
+// The records are spread evenly: with 5 percent, 1 record of 20 goes to the canary. The errors of the canary are
+// counted (see CanaryErrors) but not returned, a failing experiment does not affect the primary pipeline.
+type CanarySink struct {
+	primary   Sink
+	canary    Sink
+	permyriad uint64 // share of the records routed to the canary, in hundredths of percent
+
+	seen   uint64
+	routed uint64
+	errors uint64
+}
+
+// NewCanarySink create a sink writing every record to primary and percent (0 to 100) of the records to canary
+func NewCanarySink(primary, canary Sink, percent float64) *CanarySink {
+	s := &CanarySink{primary: primary, canary: canary}
+	s.SetPercent(percent)
+	return s
+}
+
+// SetPercent change the percentage of the records routed to the canary, clamped to 0..100, e.g. to ramp up a migration
+func (s *CanarySink) SetPercent(percent float64) {
+	switch {
+	case percent < 0:
+		percent = 0
+	case percent > 100:
+		percent = 100
+	}
+	atomic.StoreUint64(&s.permyriad, uint64(percent*100+0.5))
+}
+
+// Percent return the percentage of the records routed to the canary
+func (s *CanarySink) Percent() float64 {
+	return float64(atomic.LoadUint64(&s.permyriad)) / 100
+}
+
+// WriteRecord write the record to the primary sink and, for its share of the records, to the canary sink
+func (s *CanarySink) WriteRecord(rec *Record) error {
+	err := s.primary.WriteRecord(rec)
+	n := atomic.AddUint64(&s.seen, 1)
+	if p := atomic.LoadUint64(&s.permyriad); n*p/10000 != (n-1)*p/10000 {
+		atomic.AddUint64(&s.routed, 1)
+		if cerr := s.canary.WriteRecord(rec); cerr != nil {
+			atomic.AddUint64(&s.errors, 1)
+		}
+	}
+	return err
+}
+
+// CanaryRecords return the number of records routed to the canary sink
+func (s *CanarySink) CanaryRecords() uint64 {
+	return atomic.LoadUint64(&s.routed)
+}
+
+// CanaryErrors return the number of records the canary sink failed to write
+func (s *CanarySink) CanaryErrors() uint64 {
+	return atomic.LoadUint64(&s.errors)
+}
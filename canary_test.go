@@ -0,0 +1,34 @@
+package elogging
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+type _failingSink struct{}
+
+func (_failingSink) WriteRecord(*Record) error { return errors.New("unreachable backend") }
+
+func TestCanarySink(t *testing.T) {
+	primary, canary := NewBenchmarkSink(nil), NewBenchmarkSink(nil)
+	s := NewCanarySink(primary, canary, 10)
+	elog := NewEphemeralElog("TestCanarySink", "info", io.Discard)
+	elog.AddSink(s)
+	for i := 0; i < 100; i++ {
+		elog.Info("record")
+	}
+	if primary.Records() != 100 || canary.Records() != 10 || s.CanaryRecords() != 10 {
+		t.Errorf("expected 100 primary and 10 canary records, got %d and %d", primary.Records(), canary.Records())
+	}
+	s.SetPercent(250)
+	elog.Info("record")
+	if s.Percent() != 100 || canary.Records() != 11 {
+		t.Errorf("expected every record routed at %g%%, got %d canary records", s.Percent(), canary.Records())
+	}
+
+	failing := NewCanarySink(primary, _failingSink{}, 100)
+	if err := failing.WriteRecord(&Record{Msg: "record"}); err != nil || failing.CanaryErrors() != 1 {
+		t.Errorf("expected the canary error counted, not returned: %v, %d", err, failing.CanaryErrors())
+	}
+}
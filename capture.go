@@ -0,0 +1,193 @@
+package elogging
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// CaptureMaxSize is the maximum number of bytes kept by a Capture, the following records are dropped
+var CaptureMaxSize = 1 << 20
+
+// Capture collect the records emitted by a set of goroutines (the one which started it and the ones started
+// with Go), whatever the levels of the Elogs, e.g. to return the full trace of a single request in a debug response
+type Capture struct {
+	minLevel llevel
+
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	dropped int
+	gids    []uint64
+}
+
+// _captureSet is the stack of the captures of a goroutine, the innermost last
+type _captureSet []*Capture
+
+var (
+	_captureLevels [lTrace + 1]int32 // number of attached captures by minimum level, checked before looking up the goroutine id
+	_capturesMu    sync.RWMutex
+	_captures      = map[uint64]_captureSet{} // replaced, never modified in place
+)
+
+// StartCapture start capturing the records at or above level emitted by the calling goroutine,
+// including the records the levels of the Elogs filter out. Stop the capture once done.
+// Captures nest: a record goes to every capture of the goroutine whose level it reaches, e.g. a capture of a
+// request and the capture of one of its steps.
+func StartCapture(level string) *Capture {
+	c := &Capture{minLevel: _value(_valid(level))}
+	c._attach(_goid())
+	return c
+}
+
+// Go run fn in a new goroutine whose records are captured as well
+func (c *Capture) Go(fn func()) {
+	go func() {
+		gid := _goid()
+		c._attach(gid)
+		defer c._detach(gid)
+		fn()
+	}()
+}
+
+// Stop stop capturing, the captured records remain available
+func (c *Capture) Stop() {
+	c.mu.Lock()
+	gids := c.gids
+	c.gids = nil
+	c.mu.Unlock()
+	for _, gid := range gids {
+		c._detach(gid)
+	}
+}
+
+// Bytes return the captured records
+func (c *Capture) Bytes() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]byte(nil), c.buf.Bytes()...)
+}
+
+// String return the captured records
+func (c *Capture) String() string {
+	return string(c.Bytes())
+}
+
+// Dropped return the number of records dropped because the capture reached CaptureMaxSize
+func (c *Capture) Dropped() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dropped
+}
+
+func (c *Capture) _attach(gid uint64) {
+	c.mu.Lock()
+	c.gids = append(c.gids, gid)
+	c.mu.Unlock()
+	_capturesMu.Lock()
+	defer _capturesMu.Unlock()
+	stack := _captures[gid]
+	_captures[gid] = append(stack[:len(stack):len(stack)], c)
+	atomic.AddInt32(&_captureLevels[c.minLevel], 1)
+}
+
+func (c *Capture) _detach(gid uint64) {
+	_capturesMu.Lock()
+	defer _capturesMu.Unlock()
+	stack := _captures[gid]
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] != c {
+			continue
+		}
+		if len(stack) == 1 {
+			delete(_captures, gid)
+		} else {
+			_captures[gid] = append(stack[:i:i], stack[i+1:]...)
+		}
+		atomic.AddInt32(&_captureLevels[c.minLevel], -1)
+		return
+	}
+}
+
+func (c *Capture) _write(buf []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.buf.Len()+len(buf) > CaptureMaxSize {
+		c.dropped++
+		return
+	}
+	c.buf.Write(buf)
+}
+
+func (s _captureSet) _write(buf []byte) {
+	for _, c := range s {
+		c._write(buf)
+	}
+}
+
+// _captureWanted report whether a capture of any goroutine captures records at level
+func _captureWanted(level llevel) bool {
+	if level == lPrint {
+		level = lInfo
+	}
+	if level < lDisabled {
+		level = lDisabled
+	}
+	for l := level; l <= lTrace; l++ {
+		if atomic.LoadInt32(&_captureLevels[l]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// _captureFor return the captures of the calling goroutine capturing records at level, the goroutine id is only
+// looked up when a capture of some goroutine captures the level
+func _captureFor(level llevel) _captureSet {
+	if !_captureWanted(level) {
+		return nil
+	}
+	gid := _goid()
+	_capturesMu.RLock()
+	stack := _captures[gid]
+	_capturesMu.RUnlock()
+	var set _captureSet
+	for i, c := range stack {
+		if _passes(level, c.minLevel) && !_containsCapture(stack[:i], c) {
+			set = append(set, c)
+		}
+	}
+	return set
+}
+
+// _containsCapture report whether the capture is in the set
+func _containsCapture(set _captureSet, c *Capture) bool {
+	for _, x := range set {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
+
+// _capture write a record filtered out by the levels to the captures only, the hooks don't run on it (it is not
+// emitted) but it is prepared as an emitted record
+func (e *Elog) _capture(calldepth int, captures _captureSet, level llevel, msg string, fields []FieldT) {
+	rec := e._newRecord(calldepth+1, level, _valid(level.String()), msg, fields)
+	e._prepare(&rec)
+	captures._write(e._format(&rec))
+	e._debugf("%s record kept by the capture of the goroutine", level)
+}
+
+// _goid return the id of the calling goroutine
+func _goid() uint64 {
+	var b [64]byte
+	s := b[:runtime.Stack(b[:], false)]
+	s = bytes.TrimPrefix(s, []byte("goroutine "))
+	if i := bytes.IndexByte(s, ' '); i > 0 {
+		s = s[:i]
+	}
+	id, _ := strconv.ParseUint(string(s), 10, 64)
+	return id
+}
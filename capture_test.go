@@ -0,0 +1,102 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestCapture", "info", b)
+
+	c := StartCapture("trace")
+	elog.Info("shown")
+	elog.Trace("captured only")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	c.Go(func() {
+		defer wg.Done()
+		elog.Verbosef("from child %d", 1)
+	})
+	wg.Wait()
+	other := make(chan struct{})
+	go func() {
+		elog.Trace("not captured")
+		close(other)
+	}()
+	<-other
+	c.Stop()
+	elog.Trace("after stop")
+
+	captured := c.String()
+	if !strings.Contains(captured, "shown") || !strings.Contains(captured, "captured only") ||
+		!strings.Contains(captured, "from child 1") || strings.Contains(captured, "not captured") ||
+		strings.Contains(captured, "after stop") {
+		t.Errorf("unexpected capture %q", captured)
+	}
+	if strings.Contains(b.String(), "captured only") || !strings.Contains(b.String(), "shown") {
+		t.Errorf("unexpected output %q", b.String())
+	}
+	if _capturesLeft() {
+		t.Error("expected no captured goroutine left")
+	}
+}
+
+func TestCaptureNested(t *testing.T) {
+	elog := NewEphemeralElog("TestCaptureNested", "error", &bytes.Buffer{})
+	request := StartCapture("verbose")
+	elog.Verbose("before")
+	step := StartCapture("trace")
+	elog.Trace("step detail")
+	elog.Verbose("step")
+	step.Stop()
+	elog.Verbose("after")
+	elog.Trace("not captured")
+	request.Stop()
+
+	if captured := request.String(); !strings.Contains(captured, "before") || !strings.Contains(captured, "step") ||
+		!strings.Contains(captured, "after") || strings.Contains(captured, "detail") || strings.Contains(captured, "not captured") {
+		t.Errorf("unexpected outer capture %q", captured)
+	}
+	if captured := step.String(); !strings.Contains(captured, "step detail") || strings.Contains(captured, "before") ||
+		strings.Contains(captured, "after") {
+		t.Errorf("unexpected inner capture %q", captured)
+	}
+	if _capturesLeft() {
+		t.Error("expected no captured goroutine left")
+	}
+}
+
+func TestCaptureFiltered(t *testing.T) {
+	r := NewRegistry()
+	r.SetVersionField("build", "1.2.3")
+	elog := r.NewElog("TestCaptureFiltered", "info", &bytes.Buffer{})
+	hooked := 0
+	elog.AddHook(func(rec *Record) { hooked++ })
+
+	c := StartCapture("trace")
+	elog.Trace("filtered")
+	c.Stop()
+
+	if captured := c.String(); !strings.Contains(captured, "filtered") || !strings.Contains(captured, "build=1.2.3") {
+		t.Errorf("unexpected capture %q", captured)
+	}
+	if hooked != 0 {
+		t.Errorf("hooks ran %d times on a filtered record", hooked)
+	}
+}
+
+// _capturesLeft report whether a goroutine is still captured
+func _capturesLeft() bool {
+	_capturesMu.RLock()
+	defer _capturesMu.RUnlock()
+	for l := range _captureLevels {
+		if atomic.LoadInt32(&_captureLevels[l]) != 0 {
+			return true
+		}
+	}
+	return len(_captures) != 0
+}
@@ -0,0 +1,135 @@
+package elogging
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrChaos is the failure of a ChaosSink told to fail without a specific error
+var ErrChaos = errors.New("elogging: chaos sink failure")
+
+// ChaosSink is a destination failing, blocking or slowing down its writes on demand, to test applications (and the
+// sink wrappers such as BreakerSink) against the failure modes of a logging pipeline. Use it as a sink or as the
+// output of an Elog (io.Writer, one record per Write), the writes which do not fail are passed to the next sink,
+// e.g.
+//  chaos := elogging.NewChaosSink(nil)
+//  elog.AddSink(elogging.NewBreakerSink(chaos, 3, time.Second, elogging.BreakerDrop))
+//  chaos.FailWith(io.ErrClosedPipe) // the collector goes down
+//  chaos.Delay(time.Second)         // the collector is slow
+//  chaos.Block()                    // the collector hangs, until chaos.Unblock()
+type ChaosSink struct {
+	next Sink
+
+	mu        sync.Mutex
+	err       error
+	failEvery int
+	delay     time.Duration
+	blocked   chan struct{}
+
+	writes   uint64
+	failures uint64
+}
+
+// NewChaosSink create a chaos sink passing the records it does not fail to next (nil to discard them)
+func NewChaosSink(next Sink) *ChaosSink {
+	return &ChaosSink{next: next}
+}
+
+// FailWith make every write fail with err (ErrChaos when nil), see Heal
+func (s *ChaosSink) FailWith(err error) {
+	s.FailEvery(1, err)
+}
+
+// FailEvery make one write of n fail with err (ErrChaos when nil), n <= 0 stop the failures
+func (s *ChaosSink) FailEvery(n int, err error) {
+	if err == nil {
+		err = ErrChaos
+	}
+	s.mu.Lock()
+	s.err, s.failEvery = err, n
+	s.mu.Unlock()
+}
+
+// Delay slow down every write by d, 0 stop the delay
+func (s *ChaosSink) Delay(d time.Duration) {
+	s.mu.Lock()
+	s.delay = d
+	s.mu.Unlock()
+}
+
+// Block make the writes block until Unblock or Heal
+func (s *ChaosSink) Block() {
+	s.mu.Lock()
+	if s.blocked == nil {
+		s.blocked = make(chan struct{})
+	}
+	s.mu.Unlock()
+}
+
+// Unblock release the blocked writes
+func (s *ChaosSink) Unblock() {
+	s.mu.Lock()
+	if s.blocked != nil {
+		close(s.blocked)
+		s.blocked = nil
+	}
+	s.mu.Unlock()
+}
+
+// Heal stop the failures, the delay and the blocking
+func (s *ChaosSink) Heal() {
+	s.Unblock()
+	s.mu.Lock()
+	s.err, s.failEvery, s.delay = nil, 0, 0
+	s.mu.Unlock()
+}
+
+// _chaos apply the configured failure modes to a write, returning its error
+func (s *ChaosSink) _chaos() error {
+	n := atomic.AddUint64(&s.writes, 1)
+	s.mu.Lock()
+	err, every, delay, blocked := s.err, s.failEvery, s.delay, s.blocked
+	s.mu.Unlock()
+	if blocked != nil {
+		<-blocked
+	}
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if every > 0 && n%uint64(every) == 0 {
+		atomic.AddUint64(&s.failures, 1)
+		return err
+	}
+	return nil
+}
+
+// WriteRecord apply the failure modes and pass the record to the next sink
+func (s *ChaosSink) WriteRecord(rec *Record) error {
+	if err := s._chaos(); err != nil {
+		return err
+	}
+	if s.next != nil {
+		return s.next.WriteRecord(rec)
+	}
+	return nil
+}
+
+// Write apply the failure modes to a rendered record and discard it
+func (s *ChaosSink) Write(p []byte) (int, error) {
+	if err := s._chaos(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Writes return the number of writes attempted
+func (s *ChaosSink) Writes() uint64 {
+	return atomic.LoadUint64(&s.writes)
+}
+
+// Failures return the number of writes failed
+func (s *ChaosSink) Failures() uint64 {
+	return atomic.LoadUint64(&s.failures)
+}
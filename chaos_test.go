@@ -0,0 +1,49 @@
+package elogging
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestChaosSink(t *testing.T) {
+	next := NewBenchmarkSink(nil)
+	s := NewChaosSink(next)
+	rec := &Record{Msg: "record"}
+	s.FailEvery(2, io.ErrClosedPipe)
+	for i := 0; i < 4; i++ {
+		err := s.WriteRecord(rec)
+		if (i%2 == 1) != errors.Is(err, io.ErrClosedPipe) {
+			t.Errorf("write %d: unexpected error %v", i, err)
+		}
+	}
+	if s.Failures() != 2 || next.Records() != 2 {
+		t.Errorf("expected 2 failures and 2 records passed, got %d and %d", s.Failures(), next.Records())
+	}
+	s.FailWith(nil)
+	if _, err := s.Write([]byte("line\n")); err != ErrChaos {
+		t.Errorf("expected the chaos error, got %v", err)
+	}
+
+	s.Heal()
+	s.Block()
+	done := make(chan error)
+	go func() { done <- s.WriteRecord(rec) }()
+	select {
+	case <-done:
+		t.Fatal("expected the write blocked")
+	case <-time.After(20 * time.Millisecond):
+	}
+	s.Unblock()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	s.Delay(20 * time.Millisecond)
+	start := time.Now()
+	s.WriteRecord(rec)
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("expected the write delayed, took %s", d)
+	}
+}
@@ -0,0 +1,106 @@
+package elogging
+
+import (
+	"flag"
+	"strconv"
+)
+
+// ApplyCLIVerbosity map a command line verbosity count onto the package levels:
+//  n < 0 (-q)  error
+//  n == 0      warning
+//  n == 1 (-v)   info
+//  n == 2 (-vv)  verbose
+//  n >= 3 (-vvv) trace
+// the level becomes the default level for new Elogs and is set on all the existing Elogs and the default log,
+// the global level is reset so the command line choice is the one in effect.
+func ApplyCLIVerbosity(n int) {
+	_defaultRegistry.ApplyCLIVerbosity(n)
+}
+
+// ApplyCLIVerbosity map a command line verbosity count onto the levels of the registry, see the package ApplyCLIVerbosity
+func (r *Registry) ApplyCLIVerbosity(n int) {
+	var level llevel
+	switch {
+	case n < 0:
+		level = lError
+	case n == 0:
+		level = lWarn
+	case n == 1:
+		level = lInfo
+	case n == 2:
+		level = lVerbose
+	default:
+		level = lTrace
+	}
+	if !r._allow("", "verbosity") {
+		return
+	}
+	r._audit("", "default_level", r._defaultLevel.String(), level.String())
+	r._defaultLevel = level
+	for k := range r._registered() {
+		k._setLevel(level)
+	}
+	if r._stdLog != nil {
+		r._stdLog._setLevel(level)
+	}
+	r._audit("", "global_level", r._getGlobalLevel().String(), lDisabled.String())
+	r._setGlobalLevel(lDisabled)
+}
+
+// VerbosityValue is a boolean like flag value adding a delta to a counter each time it is set,
+// it implements flag.Value and pflag.Value (register it with NoOptDefVal "true" for pflag)
+type VerbosityValue struct {
+	n     *int
+	delta int
+}
+
+// NewVerbosityValue return a VerbosityValue adding delta to *n each time the flag is given
+func NewVerbosityValue(n *int, delta int) *VerbosityValue {
+	return &VerbosityValue{n: n, delta: delta}
+}
+
+// String return the current counter value
+func (v *VerbosityValue) String() string {
+	if v == nil || v.n == nil {
+		return "0"
+	}
+	return strconv.Itoa(*v.n)
+}
+
+// Set add the delta to the counter when s is a true boolean value
+func (v *VerbosityValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	if b {
+		*v.n += v.delta
+	}
+	return nil
+}
+
+// IsBoolFlag allow the flag to be given without a value
+func (v *VerbosityValue) IsBoolFlag() bool {
+	return true
+}
+
+// Type return the pflag type name of the value
+func (v *VerbosityValue) Type() string {
+	return "count"
+}
+
+// RegisterVerbosityFlags register -v, -vv, -vvv, -verbose, -q and -quiet on fs (flag.CommandLine when nil),
+// the returned counter is meant to be passed to ApplyCLIVerbosity once the flags are parsed
+func RegisterVerbosityFlags(fs *flag.FlagSet) *int {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	n := new(int)
+	fs.Var(NewVerbosityValue(n, 1), "v", "increase log verbosity (can be repeated)")
+	fs.Var(NewVerbosityValue(n, 1), "verbose", "increase log verbosity (can be repeated)")
+	fs.Var(NewVerbosityValue(n, 2), "vv", "increase log verbosity by two levels")
+	fs.Var(NewVerbosityValue(n, 3), "vvv", "increase log verbosity by three levels")
+	fs.Var(NewVerbosityValue(n, -1), "q", "quiet, log errors only")
+	fs.Var(NewVerbosityValue(n, -1), "quiet", "quiet, log errors only")
+	return n
+}
@@ -0,0 +1,44 @@
+package elogging
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestCLIVerbosity(t *testing.T) {
+	defer SetDefaultLevel(DefaultLevel())
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	n := RegisterVerbosityFlags(fs)
+	if err := fs.Parse([]string{"-v", "-vv"}); err != nil {
+		t.Fatal(err)
+	}
+	if *n != 3 {
+		t.Fatalf("expected verbosity 3, got %d", *n)
+	}
+
+	elog := NewElog("TestCLIVerbosity", "info", nil)
+	defer elog.Clear()
+	ApplyCLIVerbosity(*n)
+	if elog.GetLevel() != "Trace" || DefaultLevel() != "Trace" {
+		t.Errorf("expected trace level, got %s (default %s)", elog.GetLevel(), DefaultLevel())
+	}
+	ApplyCLIVerbosity(-1)
+	if elog.GetLevel() != "Error" {
+		t.Errorf("expected error level for quiet, got %s", elog.GetLevel())
+	}
+}
+
+func TestCLIVerbosityStdLog(t *testing.T) {
+	r := NewRegistry()
+	r.ApplyCLIVerbosity(2)
+	if r.StdLog().GetLevel() != "Verbose" {
+		t.Errorf("expected verbose default log, got %s", r.StdLog().GetLevel())
+	}
+	if err := r.ApplyConfig(Config{Scopes: []ScopeLevel{{"*", "trace"}, {"db.*", "error"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if r.StdLog().GetLevel() != "Trace" {
+		t.Errorf("expected the catch-all rule on the default log, got %s", r.StdLog().GetLevel())
+	}
+}
@@ -0,0 +1,166 @@
+package elogging
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CollectionRendering is the rendering of map, slice and array values of the record fields
+type CollectionRendering int
+
+const (
+	CollectionsDefault CollectionRendering = iota // the %v rendering (default)
+	CollectionsJSON                               // inline JSON, a nested value in JSON records
+	CollectionsPreview                            // the first MaxItems items and the length: [1 2 3 ...] (len=100)
+	CollectionsElided                             // the kind and length only: <slice len=100>
+)
+
+// _defaultPreviewItems is the default number of items of a collection preview
+const _defaultPreviewItems = 10
+
+// CollectionOptions control the rendering of map, slice and array values of an encoder or an Elog output
+// (see WithCollections and SetCollectionRendering), a rendering longer than MaxBytes (0 for no limit) is
+// truncated and suffixed with ...(+n bytes) whatever the mode. Maps are previewed in key order.
+type CollectionOptions struct {
+	Mode     CollectionRendering
+	MaxItems int // items of a preview, 10 when 0
+	MaxBytes int
+}
+
+// WithCollections return an encoder rendering the map, slice and array values of the record fields according to
+// the options before encoding the record with enc, e.g.
+//  elog.AddSink(elogging.NewWriterSink(w, elogging.WithCollections(elogging.FlagsEncoder(elogging.ELJSONLog),
+//  	elogging.CollectionOptions{Mode: elogging.CollectionsPreview, MaxItems: 5}), ""))
+func WithCollections(enc Encoder, opts CollectionOptions) Encoder {
+	return EncoderFunc(func(buf []byte, rec *Record) []byte {
+		if len(rec.Fields) == 0 {
+			return enc.Encode(buf, rec)
+		}
+		rendered := *rec
+		rendered.Fields = opts._renderFields(rec.Fields)
+		return enc.Encode(buf, &rendered)
+	})
+}
+
+// SetCollectionRendering change the rendering of the map, slice and array values written to the Elog output,
+// the sinks render them with their own encoder (see WithCollections)
+func (e *Elog) SetCollectionRendering(opts CollectionOptions) {
+	e._conf.Lock()
+	defer e._conf.Unlock()
+	if opts == (CollectionOptions{}) {
+		e._collections = nil
+		return
+	}
+	e._collections = &opts
+}
+
+// _renderFields return the fields with their collection values replaced by their rendering, fields itself when
+// it has none, fields is not modified
+func (o *CollectionOptions) _renderFields(fields []FieldT) []FieldT {
+	var out []FieldT
+	for i := range fields {
+		f := &fields[i]
+		var rendered FieldT
+		switch {
+		case f.kind == kindGroup:
+			members := f.any.([]FieldT)
+			if m := o._renderFields(members); len(m) > 0 && &m[0] != &members[0] { // members rendered
+				rendered = FieldT{Key: f.Key, kind: kindGroup, any: m}
+				break
+			}
+			continue
+		case f.kind != kindAny || f.any == nil:
+			continue
+		default:
+			if _, ok := f._redacted(); ok {
+				continue
+			}
+			v := reflect.ValueOf(f.any)
+			if k := v.Kind(); k != reflect.Map && k != reflect.Slice && k != reflect.Array {
+				continue
+			}
+			rendered = o._render(f.Key, v)
+		}
+		if out == nil {
+			out = append([]FieldT(nil), fields...)
+		}
+		out[i] = rendered
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// _render return the field of a collection value rendered according to the options
+func (o *CollectionOptions) _render(key string, v reflect.Value) FieldT {
+	var s string
+	switch o.Mode {
+	case CollectionsJSON:
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			s = fmt.Sprint(v.Interface())
+		} else if o.MaxBytes > 0 && len(b) > o.MaxBytes {
+			s = string(b) // truncated below, no longer valid JSON
+		} else {
+			return FieldT{Key: key, kind: kindJSON, str: string(b), any: json.RawMessage(b)}
+		}
+	case CollectionsPreview:
+		s = o._preview(v)
+	case CollectionsElided:
+		s = "<" + v.Kind().String() + " len=" + strconv.Itoa(v.Len()) + ">"
+	default:
+		s = fmt.Sprint(v.Interface())
+	}
+	if o.MaxBytes > 0 && len(s) > o.MaxBytes {
+		cut := o.MaxBytes
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut] + "...(+" + strconv.Itoa(len(s)-cut) + " bytes)"
+	}
+	return String(key, s)
+}
+
+// _preview return the first items of the collection followed by its length when some are left out
+func (o *CollectionOptions) _preview(v reflect.Value) string {
+	max := o.MaxItems
+	if max <= 0 {
+		max = _defaultPreviewItems
+	}
+	n := v.Len()
+	var b strings.Builder
+	if v.Kind() == reflect.Map {
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface()) })
+		b.WriteString("map[")
+		for i, k := range keys {
+			if i == max {
+				break
+			}
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			fmt.Fprintf(&b, "%v:%v", k.Interface(), v.MapIndex(k).Interface())
+		}
+	} else {
+		b.WriteByte('[')
+		for i := 0; i < n && i < max; i++ {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			fmt.Fprint(&b, v.Index(i).Interface())
+		}
+	}
+	if n > max {
+		b.WriteString(" ...] (len=" + strconv.Itoa(n) + ")")
+	} else {
+		b.WriteByte(']')
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package elogging
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestCollectionRendering(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+	rec := &Record{Scope: "s", Tag: "INFO", Msg: "m", Fields: []FieldT{
+		Any("items", items), Any("m", map[string]int{"b": 2, "a": 1}), Int("n", 1),
+	}}
+	for _, c := range []struct {
+		opts     CollectionOptions
+		flags    int
+		expected string
+	}{
+		{CollectionOptions{Mode: CollectionsPreview, MaxItems: 3}, 0,
+			`s (INFO) m items="[0 1 2 ...] (len=100)" m="map[a:1 b:2]" n=1`},
+		{CollectionOptions{Mode: CollectionsElided}, 0,
+			`s (INFO) m items="<slice len=100>" m="<map len=2>" n=1`},
+		{CollectionOptions{Mode: CollectionsJSON, MaxBytes: 32}, ELJSONLog,
+			`{"scope":"s","level":"INFO","msg":"m","items":"[0,1,2,3,4,5,6,7,8,9,10,11,12,13...(+259 bytes)","m":{"a":1,"b":2},"n":1}`},
+		{CollectionOptions{Mode: CollectionsJSON}, 0,
+			`s (INFO) m items=[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32,33,34,35,36,37,38,39,40,41,42,43,44,45,46,47,48,49,50,51,52,53,54,55,56,57,58,59,60,61,62,63,64,65,66,67,68,69,70,71,72,73,74,75,76,77,78,79,80,81,82,83,84,85,86,87,88,89,90,91,92,93,94,95,96,97,98,99] m="{\"a\":1,\"b\":2}" n=1`},
+	} {
+		got := WithCollections(FlagsEncoder(c.flags|log.Lmsgprefix), c.opts).Encode(nil, rec)
+		if string(got) != c.expected+"\n" {
+			t.Errorf("expected %s, got %s", c.expected, got)
+		}
+	}
+	if _, ok := rec.Fields[0].any.([]int); !ok {
+		t.Error("record fields modified")
+	}
+}
+
+func TestSetCollectionRendering(t *testing.T) {
+	b := &bytes.Buffer{}
+	sink := &_recordsSink{}
+	elog := NewEphemeralElog("TestSetCollectionRendering", "info", b)
+	elog.SetFlags(0)
+	elog.AddSink(sink)
+	elog.SetCollectionRendering(CollectionOptions{Mode: CollectionsElided})
+	elog.InfoKV("batch", Any("ids", []string{"a", "b"}), Group("req", Any("tags", [2]string{"x", "y"})))
+	elog.SetCollectionRendering(CollectionOptions{})
+	elog.InfoKV("batch", Any("ids", []string{"a", "b"}))
+
+	lines := strings.Split(b.String(), "\n")
+	if !strings.HasSuffix(lines[0], `ids="<slice len=2>" req.tags="<array len=2>"`) || !strings.HasSuffix(lines[1], `ids="[a b]"`) {
+		t.Errorf("unexpected output %q", b.String())
+	}
+	if _, ok := sink.records[0].Fields[0].any.([]string); !ok {
+		t.Error("expected the sinks to receive the original values")
+	}
+}
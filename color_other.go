@@ -0,0 +1,11 @@
+//go:build !windows
+
+package elogging
+
+import "io"
+
+// _enableColor report whether colors can be written to the terminal w is attached to,
+// ANSI escape sequences are always supported outside of Windows
+func _enableColor(w io.Writer) bool {
+	return true
+}
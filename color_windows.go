@@ -0,0 +1,32 @@
+//go:build windows
+
+package elogging
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+const _enableVirtualTerminalProcessing = 0x0004
+
+var _setConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// _enableColor enable the ANSI escape sequences processing of the console w is attached to,
+// it report false when the console does not support them (Windows before 10) and colors must not be used
+func _enableColor(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	h := syscall.Handle(f.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(h, &mode); err != nil {
+		return false
+	}
+	if mode&_enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	ret, _, _ := _setConsoleMode.Call(uintptr(h), uintptr(mode|_enableVirtualTerminalProcessing))
+	return ret != 0
+}
@@ -0,0 +1,139 @@
+package elogging
+
+import "sync/atomic"
+
+// Compat is a compatibility profile deciding how a family of calls of an Elog behaves
+type Compat int
+
+const (
+	// CompatElogging is the full elogging semantics: records are tagged with their level (or call),
+	// leveled calls are filtered by level, Print calls are not
+	CompatElogging Compat = iota
+	// CompatStdlib is a pure golang log package passthrough: records are not tagged and never filtered by level
+	CompatStdlib
+	// CompatStdlibLeveled writes records like the golang log package (no tag) but filters them by level,
+	// Print calls are filtered as info records
+	CompatStdlibLeveled
+)
+
+func (c Compat) String() string {
+	switch c {
+	case CompatStdlib:
+		return "stdlib"
+	case CompatStdlibLeveled:
+		return "stdlib-leveled"
+	}
+	return "elogging"
+}
+
+// CallFamily identify a group of logging calls sharing a compatibility profile
+type CallFamily int
+
+const (
+	FamilyPrint   CallFamily = iota // Print, Printf, Println
+	FamilyLeveled                   // Error, Warn, Info, Verbose, Trace and their formatted variants
+	FamilyFatal                     // Fatal and Panic calls of the package default log
+	_numFamilies
+)
+
+func (f CallFamily) String() string {
+	switch f {
+	case FamilyPrint:
+		return "print"
+	case FamilyFatal:
+		return "fatal"
+	}
+	return "leveled"
+}
+
+// _family return the call family of records with the given level
+func _family(level llevel) CallFamily {
+	switch level {
+	case lPrint:
+		return FamilyPrint
+	case lFatal:
+		return FamilyFatal
+	}
+	return FamilyLeveled
+}
+
+// SetCompat change the compatibility profile of a call family of the Elog
+func (e *Elog) SetCompat(family CallFamily, profile Compat) {
+	if family < 0 || family >= _numFamilies || !e._allow("compat") {
+		return
+	}
+	e._audit("compat", family.String()+" "+e._getCompat(family).String(), family.String()+" "+profile.String())
+	atomic.StoreInt32(&e._compat[family], int32(profile))
+}
+
+// Compat retrieve the compatibility profile of a call family of the Elog
+func (e *Elog) Compat(family CallFamily) Compat {
+	if family >= 0 && family < _numFamilies {
+		return e._getCompat(family)
+	}
+	return CompatElogging
+}
+
+// SetCompat change the compatibility profile of a call family of the package default log,
+// e.g. SetCompat(FamilyPrint, CompatStdlib) makes Print, Printf and Println behave exactly like the golang log package
+func SetCompat(family CallFamily, profile Compat) {
+	_defaultRegistry._stdLog.SetCompat(family, profile)
+}
+
+// PrintGate select how the Print, Printf and Println calls of an Elog are gated
+type PrintGate int
+
+const (
+	PrintBypass  PrintGate = iota // Print records bypass the levels, only LogsOff stops them (default)
+	PrintAsInfo                   // Print records are emitted when info records are
+	PrintAtLevel                  // Print records are emitted when records at the level given to SetPrintGate are
+)
+
+func (g PrintGate) String() string {
+	switch g {
+	case PrintAsInfo:
+		return "info"
+	case PrintAtLevel:
+		return "level"
+	}
+	return "bypass"
+}
+
+// SetPrintGate select how the Print family of the Elog is gated, level is only used by PrintAtLevel,
+// e.g. SetPrintGate(PrintAtLevel, "verbose") silences the Print calls of a chatty dependency unless verbose.
+// The CompatStdlibLeveled profile of the Print family gates it as info records whatever the gate.
+func (e *Elog) SetPrintGate(gate PrintGate, level string) {
+	if !e._allow("print_gate") {
+		return
+	}
+	l := lInfo
+	if gate == PrintAtLevel {
+		l = _value(_valid(level))
+	}
+	prev, prevLevel := e._getPrintGate()
+	e._audit("print_gate", prev.String()+" "+prevLevel.String(), gate.String()+" "+l.String())
+	atomic.StoreInt32(&e._printGate, _packPrintGate(gate, l))
+}
+
+// GetPrintGate retrieve the Print family gate of the Elog and its level
+func (e *Elog) GetPrintGate() (PrintGate, string) {
+	gate, level := e._getPrintGate()
+	return gate, level.String()
+}
+
+// SetPrintGate select how the Print family of the package default log is gated, see Elog.SetPrintGate
+func SetPrintGate(gate PrintGate, level string) {
+	_defaultRegistry._stdLog.SetPrintGate(gate, level)
+}
+
+// _printGateLevel return the level gating the Print records of the Elog, false when they bypass the levels
+func (e *Elog) _printGateLevel() (llevel, bool) {
+	gate, level := e._getPrintGate()
+	if e._getCompat(FamilyPrint) == CompatStdlibLeveled || gate == PrintAsInfo {
+		return lInfo, true
+	}
+	if gate == PrintAtLevel {
+		return level, true
+	}
+	return lDisabled, false
+}
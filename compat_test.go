@@ -0,0 +1,66 @@
+package elogging
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompatProfiles(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestCompat", "warning", b)
+	defer elog.Clear()
+	elog.SetFlags(0)
+
+	elog.SetCompat(FamilyPrint, CompatStdlib)
+	elog.Print("plain")
+	if b.String() != "TestCompatplain\n" {
+		t.Errorf("expected untagged print, got %q", b.String())
+	}
+
+	b.Reset()
+	elog.SetCompat(FamilyPrint, CompatStdlibLeveled)
+	elog.Print("gated")
+	if b.Len() != 0 {
+		t.Errorf("expected print to be gated at info, got %q", b.String())
+	}
+
+	b.Reset()
+	elog.SetCompat(FamilyLeveled, CompatStdlib)
+	elog.Trace("always")
+	if b.String() != "TestCompatalways\n" {
+		t.Errorf("expected ungated untagged trace, got %q", b.String())
+	}
+}
+
+func TestPrintGate(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestPrintGate", "warning", b)
+	elog.SetFlags(0)
+	for _, c := range []struct {
+		gate     PrintGate
+		level    string
+		expected bool
+	}{
+		{PrintBypass, "", true},
+		{PrintAsInfo, "", false},
+		{PrintAtLevel, "error", true},
+		{PrintAtLevel, "verbose", false},
+	} {
+		b.Reset()
+		elog.SetPrintGate(c.gate, c.level)
+		elog.Print("msg")
+		if (b.Len() > 0) != c.expected {
+			t.Errorf("%s gate (%s): expected emitted %v", c.gate, c.level, c.expected)
+		}
+	}
+	if gate, level := elog.GetPrintGate(); gate != PrintAtLevel || level != "Verbose" {
+		t.Errorf("unexpected gate %s %s", gate, level)
+	}
+	elog.SetPrintGate(PrintBypass, "")
+	elog.SetCompat(FamilyPrint, CompatStdlibLeveled)
+	b.Reset()
+	elog.Print("msg")
+	if b.Len() > 0 {
+		t.Error("stdlib leveled profile should gate Print as info")
+	}
+}
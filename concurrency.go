@@ -0,0 +1,202 @@
+package elogging
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// the level, flags, compatibility profiles and Print gate of an Elog are read atomically, its outputs are swapped
+// under its write lock (_mu) and its scope, hooks, sinks and labels under its configuration lock (_conf), the
+// registered Elogs under the lock of their registry (_logsMu), the bootstrap buffer in an atomic value, the registry
+// switches are atomic (see the package Concurrency section)
+
+// _noOverride is the override of an Elog not matched by the override table
+const _noOverride llevel = -128
+
+// _scopeLevel return the level of the Elog, ignoring its override
+func (e *Elog) _scopeLevel() llevel {
+	return llevel(atomic.LoadInt32((*int32)(&e.level)))
+}
+
+// _storeLevel set the level of the Elog
+func (e *Elog) _storeLevel(level llevel) {
+	atomic.StoreInt32((*int32)(&e.level), int32(level))
+}
+
+// _overrideLevel return the level set by the override table, _noOverride when none
+func (e *Elog) _overrideLevel() llevel {
+	return llevel(atomic.LoadInt32((*int32)(&e._override)))
+}
+
+// _storeOverride set the level of the override table, _noOverride for none
+func (e *Elog) _storeOverride(level llevel) {
+	atomic.StoreInt32((*int32)(&e._override), int32(level))
+}
+
+// _getFlags return the flags of the Elog
+func (e *Elog) _getFlags() int {
+	return int(atomic.LoadInt32(&e._flags))
+}
+
+// _storeFlags set the flags of the Elog
+func (e *Elog) _storeFlags(flags int) {
+	atomic.StoreInt32(&e._flags, int32(flags))
+}
+
+// _getCompat return the compatibility profile of a call family of the Elog
+func (e *Elog) _getCompat(family CallFamily) Compat {
+	return Compat(atomic.LoadInt32(&e._compat[family]))
+}
+
+// _getPrintGate return the Print family gate of the Elog and its level
+func (e *Elog) _getPrintGate() (PrintGate, llevel) {
+	v := atomic.LoadInt32(&e._printGate)
+	return PrintGate(v >> 8), llevel(int8(v))
+}
+
+// _packPrintGate pack a Print family gate and its level in the stored value of _printGate
+func _packPrintGate(gate PrintGate, level llevel) int32 {
+	return int32(gate)<<8 | int32(uint8(level))
+}
+
+// _scope return the scope of the Elog
+func (e *Elog) _scope() string {
+	e._conf.RLock()
+	defer e._conf.RUnlock()
+	return e.scope
+}
+
+// _getOutput return the output of the Elog
+func (e *Elog) _getOutput() io.Writer {
+	e._mu.Lock()
+	defer e._mu.Unlock()
+	return e._out
+}
+
+// _setOutput change the output of the Elog
+func (e *Elog) _setOutput(out io.Writer) {
+	e._mu.Lock()
+	e._out = out
+	e._mu.Unlock()
+}
+
+// _getLevelOutput return the level output of the Elog and its minimum level
+func (e *Elog) _getLevelOutput() (io.Writer, llevel) {
+	e._mu.Lock()
+	defer e._mu.Unlock()
+	return e._levelOut, e._levelOutLevel
+}
+
+// _getSinks return the sinks of the Elog, the slice is never modified in place
+func (e *Elog) _getSinks() []Sink {
+	e._conf.RLock()
+	defer e._conf.RUnlock()
+	return e._sinks
+}
+
+// _getHooks return the hooks of the Elog, the slice is never modified in place
+func (e *Elog) _getHooks() []Hook {
+	e._conf.RLock()
+	defer e._conf.RUnlock()
+	return e._hooks
+}
+
+// _getCollections return the collection rendering of the Elog output, nil for the default rendering
+func (e *Elog) _getCollections() *CollectionOptions {
+	e._conf.RLock()
+	defer e._conf.RUnlock()
+	return e._collections
+}
+
+// _isCleared report whether the Elog was removed from its registry by Clear
+func (e *Elog) _isCleared() bool {
+	return atomic.LoadInt32(&e._cleared) != 0
+}
+
+// _getLabels return the labels of the Elog, the slice is never modified in place
+func (e *Elog) _getLabels() []FieldT {
+	e._conf.RLock()
+	defer e._conf.RUnlock()
+	return e._labels
+}
+
+// _setScope rename the Elog, in its registry too
+func (e *Elog) _setScope(scope string) {
+	e._conf.Lock()
+	e.scope = scope
+	e._conf.Unlock()
+	r := e._reg
+	r._logsMu.Lock()
+	if _, ok := r._logs[e]; ok {
+		r._logs[e] = scope
+	}
+	r._logsMu.Unlock()
+}
+
+// _registered return a copy of the registered Elogs and their scopes, safe to range over while Elogs are
+// created and cleared
+func (r *Registry) _registered() map[*Elog]string {
+	r._logsMu.RLock()
+	defer r._logsMu.RUnlock()
+	logs := make(map[*Elog]string, len(r._logs))
+	for e, scope := range r._logs {
+		logs[e] = scope
+	}
+	return logs
+}
+
+// _getBootstrap return the bootstrap buffer of the registry, nil when disabled or released
+func (r *Registry) _getBootstrap() *_bootstrapBuffer {
+	b, _ := r._bootstrap.Load().(*_bootstrapBuffer)
+	return b
+}
+
+// _setBootstrap change the bootstrap buffer of the registry, nil to disable it
+func (r *Registry) _setBootstrap(b *_bootstrapBuffer) {
+	r._bootstrap.Store(b)
+}
+
+// _detachBootstrap remove the bootstrap buffer from the registry unless it was replaced meanwhile
+func (r *Registry) _detachBootstrap(b *_bootstrapBuffer) {
+	r._bootstrap.CompareAndSwap(b, (*_bootstrapBuffer)(nil))
+}
+
+// _active report whether the logs of the registry are on (see LogsOff)
+func (r *Registry) _active() bool {
+	return atomic.LoadInt32(&r._logsOff) == 0
+}
+
+// _setActive turn the logs of the registry on or off, records at or above floor are still emitted while off
+func (r *Registry) _setActive(active bool, floor llevel) {
+	atomic.StoreInt32((*int32)(&r._logsOffFloor), int32(floor))
+	if active {
+		atomic.StoreInt32(&r._logsOff, 0)
+	} else {
+		atomic.StoreInt32(&r._logsOff, 1)
+	}
+}
+
+// _floor return the level kept while the logs are off (see LogsOffExcept)
+func (r *Registry) _floor() llevel {
+	return llevel(atomic.LoadInt32((*int32)(&r._logsOffFloor)))
+}
+
+// _getGlobalLevel return the global level of the registry
+func (r *Registry) _getGlobalLevel() llevel {
+	return llevel(atomic.LoadInt32((*int32)(&r._globalLevel)))
+}
+
+// _getGlobalMode return the global level mode of the registry
+func (r *Registry) _getGlobalMode() GlobalLevelMode {
+	return GlobalLevelMode(atomic.LoadInt32((*int32)(&r._globalMode)))
+}
+
+// _setGlobalLevel set the global level of the registry
+func (r *Registry) _setGlobalLevel(level llevel) {
+	atomic.StoreInt32((*int32)(&r._globalLevel), int32(level))
+}
+
+// _setGlobalMode set the global level mode of the registry
+func (r *Registry) _setGlobalMode(mode GlobalLevelMode) {
+	atomic.StoreInt32((*int32)(&r._globalMode), int32(mode))
+}
@@ -0,0 +1,78 @@
+package elogging
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentUse(t *testing.T) {
+	r := NewRegistry()
+	shared := r.NewElog("shared", "info", io.Discard)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			sink := NewBenchmarkSink(nil)
+			for i := 0; i < 200; i++ {
+				shared.Infof("msg %d", i)
+				shared.InfoKV("kv", Int("i", i))
+				shared.Verbose("v")
+				shared.Println("p")
+				e := r.NewElog("worker", "info", io.Discard)
+				e.Info("hello")
+				r.GetOrCreateElog("x").Warn("w")
+				_ = r.Size()
+				_ = r.ListScopedLogs()
+				r.ListScopesAndLevels()
+				_ = r.Snapshot()
+				_, _ = r.StateJSON()
+				_ = shared.String()
+				_ = shared.Explain("info")
+				switch g {
+				case 0:
+					shared.SetLevel("trace")
+					shared.SetFlags(shared.GetFlags() ^ ELJSONLog)
+					r.SetScopeLogLevel("worker", "verbose")
+				case 1:
+					shared.SetLabels(map[string]string{"i": "x"})
+					shared.AddSink(sink)
+					shared.RemoveSink(sink)
+				case 2:
+					shared.AddHook(func(rec *Record) {})
+					shared.ClearHooks()
+					shared.ModifyParams("shared", "", &bytes.Buffer{})
+					shared.ModifyLevelOutput(io.Discard, "error")
+				case 3:
+					r.LogsOff()
+					r.LogsOn()
+					r.SetGlobalLogLevel("error")
+					r.SetGlobalLogLevel("disabled")
+				case 4:
+					shared.PushLevel("trace")
+					shared.PopLevel()
+					shared.SetCollectionRendering(CollectionOptions{Mode: CollectionsPreview})
+					shared.SetCollectionRendering(CollectionOptions{})
+					shared.SetCompat(FamilyPrint, CompatStdlibLeveled)
+					shared.SetCompat(FamilyPrint, CompatElogging)
+					shared.SetPrintGate(PrintAtLevel, "verbose")
+					shared.SetPrintGate(PrintBypass, "")
+				case 5:
+					r.EnableBootstrapBuffer(10)
+					r.EnableBootstrapBuffer(0)
+					r.SetLimit(100)
+				case 6:
+					shared.InfoKV("slice", Any("s", []int{1, 2, 3}))
+					e.Info("after")
+				case 7:
+					r.GetOrCreateElog("x").Clear()
+				}
+				e.Clear()
+				e.Info("cleared")
+			}
+		}(g)
+	}
+	wg.Wait()
+}
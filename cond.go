@@ -0,0 +1,48 @@
+package elogging
+
+// _condLevel return the level of a conditional record, disabled when the record must be skipped
+func _condLevel(cond bool, trueLevel, falseLevel string) llevel {
+	if cond {
+		return _value(_valid(trueLevel))
+	}
+	return _value(_valid(falseLevel))
+}
+
+// Cond log args at trueLevel when cond is true and at falseLevel otherwise,
+// a "disabled" (or empty) level skip the record entirely, e.g. e.Cond(retry, "warning", "disabled", "retrying")
+func (e *Elog) Cond(cond bool, trueLevel, falseLevel string, args ...interface{}) {
+	if level := _condLevel(cond, trueLevel, falseLevel); level != lDisabled {
+		e._log(2, level, args...)
+	}
+}
+
+// Condf log the formatted message at trueLevel when cond is true and at falseLevel otherwise,
+// a "disabled" (or empty) level skip the record entirely
+func (e *Elog) Condf(cond bool, trueLevel, falseLevel string, format string, args ...interface{}) {
+	if level := _condLevel(cond, trueLevel, falseLevel); level != lDisabled {
+		e._logf(2, level, format, args...)
+	}
+}
+
+// CondErr log args followed by the error at error level when err is not nil, and args alone at okLevel otherwise
+// ("disabled" to log failures only), e.g. e.CondErr(err, "verbose", "flushing cache")
+func (e *Elog) CondErr(err error, okLevel string, args ...interface{}) {
+	if err != nil {
+		e._log(2, lError, append(args[:len(args):len(args)], ": ", err)...)
+	} else if level := _value(_valid(okLevel)); level != lDisabled {
+		e._log(2, level, args...)
+	}
+}
+
+// CondErrf log the formatted message followed by the error at error level when err is not nil,
+// and the formatted message alone at okLevel otherwise ("disabled" to log failures only),
+// with ELStructuredLog the error is an "error" field
+func (e *Elog) CondErrf(err error, okLevel string, format string, args ...interface{}) {
+	if err != nil && e._getFlags()&ELStructuredLog != 0 {
+		e._logf(2, lError, format, append(args[:len(args):len(args)], "error", err)...)
+	} else if err != nil {
+		e._logf(2, lError, format+": %v", append(args[:len(args):len(args)], err)...)
+	} else if level := _value(_valid(okLevel)); level != lDisabled {
+		e._logf(2, level, format, args...)
+	}
+}
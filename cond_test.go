@@ -0,0 +1,30 @@
+package elogging
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCond(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestCond", "trace", b)
+	elog.SetFlags(0)
+
+	elog.Cond(true, "warning", "disabled", "retrying")
+	elog.Cond(false, "warning", "disabled", "skipped")
+	elog.Condf(false, "warning", "trace", "attempt %d", 2)
+	elog.CondErr(errors.New("disk full"), "verbose", "flushing cache")
+	elog.CondErr(nil, "verbose", "flushing cache")
+	elog.CondErr(nil, "disabled", "not logged")
+	elog.CondErrf(errors.New("timeout"), "info", "request %d", 7)
+
+	expected := "TestCond (WARN) retrying\n" +
+		"TestCond (TRACE) attempt 2\n" +
+		"TestCond (ERROR) flushing cache: disk full\n" +
+		"TestCond (VERBOSE) flushing cache\n" +
+		"TestCond (ERROR) request 7: timeout\n"
+	if b.String() != expected {
+		t.Errorf("unexpected output %q", b.String())
+	}
+}
@@ -0,0 +1,169 @@
+package elogging
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ScopeLevel associate a level with a scope
+type ScopeLevel struct {
+	Scope string
+	Level string
+}
+
+// Config describe a package configuration, empty fields keep the current settings
+type Config struct {
+	Format string       // text, color, json or auto
+	Level  string       // global log level
+	Output string       // stdout, stderr, a file path or file:path[?rotate=size]
+	Scopes []ScopeLevel // per scope levels, scopes can be patterns (path.Match syntax, * matches the default log too), applied in order
+}
+
+// ParseConfig parse a compact configuration string made of comma separated items:
+//  json                         a bare format name (text, color, json, auto)
+//  level=info                   the global level
+//  format=json                  the output format
+//  out=file:/var/log/app.log?rotate=100MB
+//                               the output (stdout, stderr, a file path, optionally rotated by size)
+//  db=trace                     any other key is a scope (a scope pattern, e.g. db.*, or a scope alias) and its level
+func ParseConfig(dsn string) (cfg Config, err error) {
+	for _, item := range strings.Split(dsn, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := strings.IndexByte(item, '=')
+		if i < 0 {
+			if _, _, err = _parseFormat(item); err != nil {
+				return Config{}, fmt.Errorf("elogging: invalid config item %q", item)
+			}
+			cfg.Format = item
+			continue
+		}
+		key, value := strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		switch strings.ToLower(key) {
+		case "level":
+			cfg.Level = value
+		case "format":
+			cfg.Format = value
+		case "out", "output":
+			cfg.Output = value
+		case "":
+			return Config{}, fmt.Errorf("elogging: invalid config item %q", item)
+		default:
+			cfg.Scopes = append(cfg.Scopes, ScopeLevel{Scope: key, Level: value})
+		}
+	}
+	return cfg, nil
+}
+
+// Apply configure the package according to the config, nothing is applied when the config is invalid or its output
+// can't be opened, use ValidateConfig to check it beforehand
+func (cfg Config) Apply() error {
+	return _defaultRegistry.ApplyConfig(cfg)
+}
+
+// ApplyConfig configure the registry according to the config, see Config.Apply
+func (r *Registry) ApplyConfig(cfg Config) error {
+	if errs := ValidateConfig(cfg); len(errs) > 0 {
+		return errs[0]
+	}
+	var out io.Writer
+	if cfg.Output != "" { // opened first, nothing is applied when it fails
+		var err error
+		if out, err = r._openOutput(cfg.Output); err != nil {
+			return err
+		}
+	}
+	if cfg.Format != "" {
+		if err := r.SetGlobalFormat(cfg.Format); err != nil {
+			return err
+		}
+	}
+	if out != nil {
+		r.SetGlobalOutput(out)
+	}
+	if cfg.Level != "" {
+		r.SetGlobalLogLevel(cfg.Level)
+	}
+	for _, sl := range cfg.Scopes {
+		r.SetScopeLogLevel(sl.Scope, sl.Level)
+	}
+	return nil
+}
+
+// Configure parse the configuration string (see ParseConfig) and apply it,
+// e.g. Configure("json,level=info,db=trace,out=file:/var/log/app.log?rotate=100MB")
+func Configure(dsn string) error {
+	return _defaultRegistry.Configure(dsn)
+}
+
+// Configure parse the configuration string (see ParseConfig) and apply it to the registry
+func (r *Registry) Configure(dsn string) error {
+	cfg, err := ParseConfig(dsn)
+	if err != nil {
+		return err
+	}
+	return r.ApplyConfig(cfg)
+}
+
+// ValidateConfig check the config without applying it and return all the problems found
+func ValidateConfig(cfg Config) (errs []error) {
+	if cfg.Format != "" {
+		if _, _, err := _parseFormat(cfg.Format); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cfg.Output != "" {
+		if _, err := _parseOutput(cfg.Output); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cfg.Level != "" {
+		if _, err := _parseLevel(cfg.Level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, sl := range cfg.Scopes {
+		if sl.Scope == "" {
+			errs = append(errs, fmt.Errorf("elogging: empty scope for level %q", sl.Level))
+		} else if _, err := path.Match(sl.Scope, ""); err != nil {
+			errs = append(errs, fmt.Errorf("elogging: invalid scope pattern %q: %w", sl.Scope, err))
+		}
+		if _, err := _parseLevel(sl.Level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return
+}
+
+// RuleMatch is the dry-run result of a scope rule
+type RuleMatch struct {
+	Rule   ScopeLevel
+	Scopes []string // the existing scopes the rule applies to, sorted
+}
+
+// DryRun report, without applying anything, which existing scopes each scope rule of the config would match
+func (cfg Config) DryRun() []RuleMatch {
+	return _defaultRegistry.DryRun(cfg)
+}
+
+// DryRun report which Elogs scopes of the registry each scope rule of the config would match, see Config.DryRun
+func (r *Registry) DryRun(cfg Config) (matches []RuleMatch) {
+	for _, sl := range cfg.Scopes {
+		m := RuleMatch{Rule: sl}
+		seen := map[string]bool{}
+		for _, scope := range r._registered() {
+			if !seen[scope] && _matchScope(r.ResolveScope(sl.Scope), scope) {
+				seen[scope] = true
+				m.Scopes = append(m.Scopes, scope)
+			}
+		}
+		sort.Strings(m.Scopes)
+		matches = append(matches, m)
+	}
+	return
+}
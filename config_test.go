@@ -0,0 +1,96 @@
+package elogging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig("json,level=info,db=trace,out=file:/var/log/app.log?rotate=100MB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Format != "json" || cfg.Level != "info" || cfg.Output != "file:/var/log/app.log?rotate=100MB" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+	if len(cfg.Scopes) != 1 || cfg.Scopes[0] != (ScopeLevel{Scope: "db", Level: "trace"}) {
+		t.Errorf("unexpected scopes %+v", cfg.Scopes)
+	}
+	if _, err := ParseConfig("yaml"); err == nil {
+		t.Error("expected an error for an unknown bare item")
+	}
+	if cfg, err := ParseConfig("auto"); err != nil || cfg.Format != "auto" || len(ValidateConfig(cfg)) > 0 {
+		t.Errorf("unexpected auto config %+v %v", cfg, err)
+	}
+	if ff, auto, err := _parseFormat("Auto"); ff != 0 || !auto || err != nil {
+		t.Errorf("unexpected auto format lookup %#x %v %v", ff, auto, err)
+	}
+}
+
+func TestConfigureRotatingOutput(t *testing.T) {
+	defer SetGlobalOutput(nil)
+	defer SetDefaultFlags(DefaultFlags())
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := Configure("text,out=file:" + path + "?rotate=1KB"); err != nil {
+		t.Fatal(err)
+	}
+	elog := NewElogDefaults("TestConfigureRotatingOutput")
+	defer elog.Clear()
+	for i := 0; i < 20; i++ {
+		elog.Info(strings.Repeat("x", 100))
+	}
+	elog._out.(*RotatingFile).Close()
+
+	matches, _ := filepath.Glob(path + ".*")
+	if len(matches) == 0 {
+		t.Error("expected rotated files")
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Size() > 1024 {
+		t.Errorf("unexpected active file state %v %v", fi, err)
+	}
+}
+
+func TestApplyConfigOutputFailure(t *testing.T) {
+	r := NewRegistry()
+	flags := r._defaultFlags
+	path := filepath.Join(t.TempDir(), "missing", "app.log")
+	if err := r.ApplyConfig(Config{Format: "json", Level: "trace", Output: "file:" + path}); err == nil {
+		t.Fatal("expected an error opening the output")
+	}
+	if r._defaultFlags != flags || r._getGlobalLevel() != lDisabled {
+		t.Errorf("config partially applied: flags %#x, global level %s", r._defaultFlags, r._getGlobalLevel())
+	}
+}
+
+func TestValidateConfigAndDryRun(t *testing.T) {
+	db := NewElog("TestDryRun.db", "info", nil)
+	defer db.Clear()
+	net := NewElog("TestDryRun.net", "info", nil)
+	defer net.Clear()
+
+	errs := ValidateConfig(Config{Format: "yaml", Level: "loud", Scopes: []ScopeLevel{{"[db", "trace"}}})
+	if len(errs) != 3 {
+		t.Errorf("expected 3 validation errors, got %v", errs)
+	}
+
+	cfg, err := ParseConfig("TestDryRun.*=trace,TestDryRun.db=error,missing=info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := cfg.DryRun()
+	if len(matches) != 3 || len(matches[0].Scopes) != 2 || len(matches[1].Scopes) != 1 || len(matches[2].Scopes) != 0 {
+		t.Errorf("unexpected dry run result %+v", matches)
+	}
+	if db.GetLevel() != "Info" {
+		t.Error("dry run must not change levels")
+	}
+	if err = cfg.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if db.GetLevel() != "Error" || net.GetLevel() != "Trace" {
+		t.Errorf("unexpected levels after apply %s %s", db.GetLevel(), net.GetLevel())
+	}
+}
@@ -0,0 +1,259 @@
+// Package cobracmd integrate elogging with cobra command line applications: persistent flags configuring the
+// logging of the application and a "log" subcommand controlling a running instance through its admin endpoints
+// (see elogging.AdminHandler), served over HTTP or a unix control socket (see ListenControlSocket).
+//
+// A single call sets everything up:
+//  root := &cobra.Command{Use: "app"}
+//  cobracmd.Register(root)
+// then e.g.
+//  app serve --log-level=info --log-scope-level=db=trace --log-format=json --log-output=file:/var/log/app.log
+//  app log level trace --scope db --addr unix:/run/app/elog.sock
+package cobracmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gilwo/elogging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Register add the persistent log flags (see AddFlags) configuring the package default registry to root,
+// and the log control subcommand (see NewLogCommand)
+func Register(root *cobra.Command) {
+	AddFlags(root.PersistentFlags(), nil)
+	root.AddCommand(NewLogCommand())
+}
+
+// AddFlags register the log flags on fs, each flag configures reg (the package default registry when nil)
+// as soon as it is parsed:
+//  --log-level        global log level (error, warning, info, verbose, trace)
+//  --log-format       output format: text, color, json or auto
+//  --log-output       output destination: stdout, stderr, a file path or file:path?rotate=size
+//  --log-scope-level  scope=level, e.g. db=trace, repeatable or comma separated
+func AddFlags(fs *pflag.FlagSet, reg *elogging.Registry) {
+	if reg == nil {
+		reg = elogging.DefaultRegistry()
+	}
+	apply := func(cfg elogging.Config) error {
+		return reg.ApplyConfig(cfg)
+	}
+	fs.Var(&_configValue{typ: "level", apply: func(s string) error { return apply(elogging.Config{Level: s}) }},
+		"log-level", "global log level (error, warning, info, verbose, trace)")
+	fs.Var(&_configValue{typ: "format", apply: func(s string) error { return apply(elogging.Config{Format: s}) }},
+		"log-format", "log output format (text, color, json, auto)")
+	fs.Var(&_configValue{typ: "output", apply: func(s string) error { return apply(elogging.Config{Output: s}) }},
+		"log-output", "log output (stdout, stderr, a file path or file:path?rotate=size)")
+	fs.Var(&_configValue{typ: "scope=level", apply: func(s string) error {
+		var cfg elogging.Config
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			i := strings.IndexByte(item, '=')
+			if i <= 0 {
+				return fmt.Errorf("elogging: invalid scope level %q, expected scope=level", item)
+			}
+			cfg.Scopes = append(cfg.Scopes, elogging.ScopeLevel{Scope: item[:i], Level: item[i+1:]})
+		}
+		return apply(cfg)
+	}}, "log-scope-level", "scope=level, e.g. db=trace (repeatable, comma separated)")
+}
+
+// _configValue is a pflag value applying each value given to the flag
+type _configValue struct {
+	typ    string
+	values []string
+	apply  func(string) error
+}
+
+func (v *_configValue) String() string {
+	return strings.Join(v.values, ",")
+}
+
+func (v *_configValue) Set(s string) error {
+	if err := v.apply(s); err != nil {
+		return err
+	}
+	v.values = append(v.values, s)
+	return nil
+}
+
+func (v *_configValue) Type() string {
+	return v.typ
+}
+
+// ListenControlSocket serve the admin endpoints of reg (the package default registry when nil) on a unix socket
+// at path, replacing a stale socket file, the log subcommand reaches it with --addr unix:<path>
+func ListenControlSocket(path string, reg *elogging.Registry) (io.Closer, error) {
+	if reg == nil {
+		reg = elogging.DefaultRegistry()
+	}
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("elogging: control socket: %w", err)
+	}
+	srv := &http.Server{Handler: reg.AdminHandler()}
+	go srv.Serve(l)
+	return srv, nil
+}
+
+// NewLogCommand return the "log" subcommand controlling the logging of a running instance through its admin
+// endpoints, located with --addr (or $ELOG_ADDR): an http URL of the mounted handler or unix:<socket path>;
+// --token (or $ELOG_TOKEN) gives the freeze token of a frozen configuration (see elogging.Freeze):
+//  log state                                  print the state of the instance as JSON
+//  log level LEVEL [--scope SCOPE]            change the global level or the level of a scope
+//  log tail [--scope SCOPE] [--level LEVEL]   stream the records of the instance
+//  log query [--scope S] [--level L] [--since T] [--q TEXT]
+//                                             print the records retained by the ring buffer
+func NewLogCommand() *cobra.Command {
+	c := &_control{}
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Control the logging of a running instance",
+	}
+	cmd.PersistentFlags().StringVar(&c.addr, "addr", os.Getenv("ELOG_ADDR"), "admin endpoints address: http URL or unix:<socket path>")
+	cmd.PersistentFlags().StringVar(&c.token, "token", os.Getenv("ELOG_TOKEN"), "freeze token of a frozen configuration")
+
+	state := &cobra.Command{
+		Use:   "state",
+		Short: "Print the logging state as JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.copy(cmd, http.MethodGet, "/state", nil)
+		},
+	}
+
+	var scope, level, since, text string
+	levelCmd := &cobra.Command{
+		Use:   "level LEVEL",
+		Short: "Change the global level or the level of a scope",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.copy(cmd, http.MethodPost, "/level", url.Values{"level": {args[0]}, "scope": {scope}})
+		},
+	}
+	levelCmd.Flags().StringVar(&scope, "scope", "", "scope, scope pattern or alias (the global level when empty)")
+
+	tail := &cobra.Command{
+		Use:   "tail",
+		Short: "Stream the records of the instance",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.tail(cmd, url.Values{"scope": {scope}, "level": {level}})
+		},
+	}
+	tail.Flags().StringVar(&scope, "scope", "", "scope, scope pattern or alias (all when empty)")
+	tail.Flags().StringVar(&level, "level", "", "level of the records (trace when empty)")
+
+	query := &cobra.Command{
+		Use:   "query",
+		Short: "Print the records retained by the ring buffer as JSON lines",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.copy(cmd, http.MethodGet, "/query", url.Values{"scope": {scope}, "level": {level}, "since": {since}, "q": {text}})
+		},
+	}
+	query.Flags().StringVar(&scope, "scope", "", "scope")
+	query.Flags().StringVar(&level, "level", "", "minimum level")
+	query.Flags().StringVar(&since, "since", "", "records since a time (RFC3339)")
+	query.Flags().StringVar(&text, "q", "", "records containing a text")
+
+	cmd.AddCommand(state, levelCmd, tail, query)
+	return cmd
+}
+
+// _control is the client of the admin endpoints used by the log subcommand
+type _control struct {
+	addr  string
+	token string
+}
+
+// request send a request to the admin endpoints and return the response, an error for a failed request
+func (c *_control) request(ctx context.Context, method, path string, params url.Values) (*http.Response, error) {
+	if c.addr == "" {
+		return nil, fmt.Errorf("elogging: no admin address, use --addr or $ELOG_ADDR")
+	}
+	client, base := http.DefaultClient, strings.TrimSuffix(c.addr, "/")
+	if socket := strings.TrimPrefix(c.addr, "unix:"); socket != c.addr {
+		client = &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		}}
+		base = "http://elogging"
+	}
+	for k, v := range params {
+		if len(v) == 0 || v[0] == "" {
+			delete(params, k)
+		}
+	}
+	u := base + path
+	var body io.Reader
+	if method == http.MethodPost {
+		body = strings.NewReader(params.Encode())
+	} else if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if c.token != "" {
+		req.Header.Set("X-Elog-Token", c.token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("elogging: %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// copy send a request and copy the response to the command output
+func (c *_control) copy(cmd *cobra.Command, method, path string, params url.Values) error {
+	resp, err := c.request(cmd.Context(), method, path, params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(cmd.OutOrStdout(), resp.Body)
+	return err
+}
+
+// tail stream the records sent as server-sent events to the command output
+func (c *_control) tail(cmd *cobra.Command, params url.Values) error {
+	resp, err := c.request(cmd.Context(), http.MethodGet, "/tail", params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	out, sc := cmd.OutOrStdout(), bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		if line := sc.Text(); strings.HasPrefix(line, "data: ") {
+			fmt.Fprintln(out, line[len("data: "):])
+		}
+	}
+	if err := sc.Err(); err != nil && cmd.Context().Err() == nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package cobracmd
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gilwo/elogging"
+	"github.com/spf13/cobra"
+)
+
+func TestAddFlags(t *testing.T) {
+	reg := elogging.NewRegistry()
+	db := reg.NewElog("db", "info", io.Discard)
+	net := reg.NewElog("net", "info", io.Discard)
+	root := &cobra.Command{Use: "app", RunE: func(*cobra.Command, []string) error { return nil }}
+	AddFlags(root.PersistentFlags(), reg)
+	root.SetArgs([]string{"--log-level=warning", "--log-scope-level=db=trace", "--log-scope-level", "net=error"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if db.GetLevel() != "Trace" || net.GetLevel() != "Error" {
+		t.Errorf("unexpected levels %s %s", db.GetLevel(), net.GetLevel())
+	}
+	if state, _ := reg.StateJSON(); !bytes.Contains(state, []byte(`"global_level":"Warning"`)) {
+		t.Errorf("unexpected state %s", state)
+	}
+	root.SetArgs([]string{"--log-level=loud"})
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	if err := root.Execute(); err == nil {
+		t.Error("expected an error for an invalid level")
+	}
+}
+
+func run(t *testing.T, args ...string) (string, error) {
+	root := &cobra.Command{Use: "app"}
+	root.AddCommand(NewLogCommand())
+	out := &bytes.Buffer{}
+	root.SetOut(out)
+	root.SetErr(io.Discard)
+	root.SetArgs(args)
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestLogCommand(t *testing.T) {
+	reg := elogging.NewRegistry()
+	db := reg.NewElog("db", "info", io.Discard)
+	srv := httptest.NewServer(reg.AdminHandler())
+	defer srv.Close()
+
+	if _, err := run(t, "log", "level", "verbose", "--scope", "db", "--addr", srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if db.GetLevel() != "Verbose" {
+		t.Errorf("unexpected level %s", db.GetLevel())
+	}
+	out, err := run(t, "log", "state", "--addr", srv.URL)
+	if err != nil || !strings.Contains(out, `"db"`) {
+		t.Errorf("unexpected state %q: %v", out, err)
+	}
+	if _, err := run(t, "log", "level", "loud", "--addr", srv.URL); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("expected a bad request error, got %v", err)
+	}
+}
+
+func TestControlSocket(t *testing.T) {
+	reg := elogging.NewRegistry()
+	db := reg.NewElog("db", "info", io.Discard)
+	path := filepath.Join(t.TempDir(), "elog.sock")
+	c, err := ListenControlSocket(path, reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err := run(t, "log", "level", "trace", "--scope", "db", "--addr", "unix:"+path); err != nil {
+		t.Fatal(err)
+	}
+	if db.GetLevel() != "Trace" {
+		t.Errorf("unexpected level %s", db.GetLevel())
+	}
+}
@@ -0,0 +1,11 @@
+module github.com/gilwo/elogging/contrib/cobracmd
+
+go 1.18
+
+require (
+	github.com/gilwo/elogging v0.1.0
+	github.com/spf13/cobra v1.8.1
+	github.com/spf13/pflag v1.0.5
+)
+
+require github.com/inconshreveable/mousetrap v1.1.0 // indirect
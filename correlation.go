@@ -0,0 +1,84 @@
+package elogging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCorrelationHeader is the HTTP header carrying the correlation id when no other header name is given
+const DefaultCorrelationHeader = "X-Correlation-Id"
+
+// KeyCorrelationID is the key of the correlation id field added by the Ctx calls
+const KeyCorrelationID = "correlation_id"
+
+// _maxCorrelationID is the longest correlation id accepted from an incoming request, longer ones are replaced
+const _maxCorrelationID = 128
+
+// _correlationSeq distinguish the ids generated when the random source fails
+var _correlationSeq uint64
+
+type _correlationKey struct{}
+
+// NewCorrelationID return a new random correlation id, 32 hexadecimal characters
+func NewCorrelationID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16) + "-" + strconv.FormatUint(atomic.AddUint64(&_correlationSeq, 1), 16)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ContextWithCorrelationID return a context carrying the correlation id, added as a correlation_id field by the
+// Ctx calls (InfoCtx, TraceCtx, ...) before the trace fields
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, _correlationKey{}, id)
+}
+
+// CorrelationID return the correlation id carried by a context, false when there is none
+func CorrelationID(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	id, ok := ctx.Value(_correlationKey{}).(string)
+	return id, ok && id != ""
+}
+
+// CorrelationMiddleware return a handler serving the requests with next, their context carrying the correlation id
+// of the header (DefaultCorrelationHeader when empty) or a new one when the request has none (or one longer than 128
+// bytes), the id is sent back in the same response header, e.g.
+//  http.ListenAndServe(":8080", elogging.CorrelationMiddleware("", mux))
+//  ...
+//  elog.InfoCtx(r.Context(), "order created") // ... order created correlation_id=9f2c...
+func CorrelationMiddleware(header string, next http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultCorrelationHeader
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(header)
+		if id == "" || len(id) > _maxCorrelationID {
+			id = NewCorrelationID()
+		}
+		w.Header().Set(header, id)
+		next.ServeHTTP(w, r.WithContext(ContextWithCorrelationID(r.Context(), id)))
+	})
+}
+
+// InjectCorrelationID set the header (DefaultCorrelationHeader when empty) of an outgoing request to the correlation
+// id of ctx, the request is left unchanged when ctx has none, e.g.
+//  req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//  elogging.InjectCorrelationID(ctx, req, "")
+func InjectCorrelationID(ctx context.Context, req *http.Request, header string) {
+	id, ok := CorrelationID(ctx)
+	if !ok {
+		return
+	}
+	if header == "" {
+		header = DefaultCorrelationHeader
+	}
+	req.Header.Set(header, id)
+}
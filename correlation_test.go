@@ -0,0 +1,57 @@
+package elogging
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCorrelationID(t *testing.T) {
+	a, b := NewCorrelationID(), NewCorrelationID()
+	if len(a) != 32 || a == b {
+		t.Errorf("unexpected correlation ids %q %q", a, b)
+	}
+	if _, ok := CorrelationID(context.Background()); ok {
+		t.Error("unexpected correlation id in an empty context")
+	}
+
+	r := NewRegistry()
+	out := &bytes.Buffer{}
+	elog := r.NewElog("TestCorrelationID", "info", out)
+	elog.SetFlags(0)
+	ctx := ContextWithTrace(ContextWithCorrelationID(context.Background(), "c1"), TraceInfo{TraceID: "t1"})
+	elog.InfoCtx(ctx, "request", Int("n", 1))
+	if expected := "TestCorrelationID (INFO) request n=1 correlation_id=c1 trace_id=t1\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestCorrelationMiddleware(t *testing.T) {
+	var got string
+	var outgoing http.Header
+	h := CorrelationMiddleware("X-Request-Id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = CorrelationID(r.Context())
+		req := httptest.NewRequest("GET", "http://backend/", nil)
+		InjectCorrelationID(r.Context(), req, "")
+		outgoing = req.Header
+	}))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-Id", "abc")
+	h.ServeHTTP(rec, req)
+	if got != "abc" || rec.Header().Get("X-Request-Id") != "abc" || outgoing.Get(DefaultCorrelationHeader) != "abc" {
+		t.Errorf("correlation id not propagated: %q %v %v", got, rec.Header(), outgoing)
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-Id", strings.Repeat("x", 200))
+	h.ServeHTTP(rec, req)
+	if len(got) != 32 || rec.Header().Get("X-Request-Id") != got {
+		t.Errorf("expected a new correlation id, got %q %v", got, rec.Header())
+	}
+}
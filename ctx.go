@@ -0,0 +1,159 @@
+package elogging
+
+import (
+	"context"
+	"fmt"
+)
+
+// TraceInfo is the distributed trace a record belongs to
+type TraceInfo struct {
+	TraceID string
+	SpanID  string
+	Sampled bool // the trace is recorded in detail by the tracing backend
+}
+
+// TraceExtractor return the trace carried by a context, false when there is none
+type TraceExtractor func(ctx context.Context) (TraceInfo, bool)
+
+type _traceKey struct{}
+
+// ContextWithTrace return a context carrying the trace, read by the default trace extractor,
+// for applications propagating the trace themselves
+func ContextWithTrace(ctx context.Context, t TraceInfo) context.Context {
+	return context.WithValue(ctx, _traceKey{}, t)
+}
+
+// _contextTrace is the default trace extractor, reading the trace set by ContextWithTrace
+func _contextTrace(ctx context.Context) (TraceInfo, bool) {
+	t, ok := ctx.Value(_traceKey{}).(TraceInfo)
+	return t, ok
+}
+
+// SetTraceExtractor set how the Ctx calls of the Elogs of the registry find the trace of a context,
+// see the package SetTraceExtractor
+func (r *Registry) SetTraceExtractor(fn TraceExtractor) {
+	r._traceExtractor = fn
+}
+
+// SetTraceExtractor set how the Ctx calls (InfoCtx, TraceCtx, ...) find the trace of a context, e.g. for OpenTelemetry:
+//  elogging.SetTraceExtractor(func(ctx context.Context) (elogging.TraceInfo, bool) {
+//  	sc := trace.SpanContextFromContext(ctx)
+//  	return elogging.TraceInfo{TraceID: sc.TraceID().String(), SpanID: sc.SpanID().String(), Sampled: sc.IsSampled()}, sc.IsValid()
+//  })
+// nil restore the default extractor, reading the trace set by ContextWithTrace
+func SetTraceExtractor(fn TraceExtractor) {
+	_defaultRegistry.SetTraceExtractor(fn)
+}
+
+// TenantExtractor return the fields identifying the tenant of a request (tenant, org, ...) carried by a context
+type TenantExtractor func(ctx context.Context) []FieldT
+
+// SetTenantExtractor set the fields the Ctx calls of the Elogs of the registry add from their context,
+// see the package SetTenantExtractor
+func (r *Registry) SetTenantExtractor(fn TenantExtractor) {
+	r._tenantExtractor = fn
+}
+
+// SetTenantExtractor set the fields the Ctx calls (InfoCtx, TraceCtx, ...) add from their context, configured once so
+// the tenant or organization identifiers appear on every record of a request, e.g.
+//  elogging.SetTenantExtractor(func(ctx context.Context) []elogging.FieldT {
+//  	if t, ok := ctx.Value(tenantKey{}).(string); ok {
+//  		return []elogging.FieldT{elogging.String("tenant", t)}
+//  	}
+//  	return nil
+//  })
+// the fields follow the fields of the call and precede the trace fields, nil remove the extractor
+func SetTenantExtractor(fn TenantExtractor) {
+	_defaultRegistry.SetTenantExtractor(fn)
+}
+
+// SetTraceSampling make the verbose and trace records of the Ctx calls follow the sampling decision of their trace,
+// see the package SetTraceSampling
+func (r *Registry) SetTraceSampling(on bool) {
+	if !r._allow("", "trace_sampling") {
+		return
+	}
+	r._audit("", "trace_sampling", fmt.Sprint(r._traceSampling), fmt.Sprint(on))
+	r._traceSampling = on
+}
+
+// SetTraceSampling make the verbose and trace records of the Ctx calls (VerboseCtx, TraceCtx) follow the sampling
+// decision of the trace of their context rather than the levels: they are emitted for the sampled traces and dropped
+// for the others, so detailed logs exist exactly for the requests having detailed traces. Records without a trace,
+// records at other levels and LogsOff are not affected.
+func SetTraceSampling(on bool) {
+	_defaultRegistry.SetTraceSampling(on)
+}
+
+// _trace return the trace of a context according to the extractor of the registry
+func (r *Registry) _trace(ctx context.Context) (TraceInfo, bool) {
+	if ctx == nil {
+		return TraceInfo{}, false
+	}
+	if r._traceExtractor != nil {
+		return r._traceExtractor(ctx)
+	}
+	return _contextTrace(ctx)
+}
+
+// ErrorCtx print prefixed (Error) log lines with level Error, the given fields and the trace of ctx
+func (e *Elog) ErrorCtx(ctx context.Context, msg string, fields ...FieldT) {
+	e._logCtx(2, ctx, lError, msg, fields)
+}
+
+// WarnCtx print prefixed (Warning) log lines with level Warning, the given fields and the trace of ctx
+func (e *Elog) WarnCtx(ctx context.Context, msg string, fields ...FieldT) {
+	e._logCtx(2, ctx, lWarn, msg, fields)
+}
+
+// InfoCtx print prefixed (Info) log lines with level Info, the given fields and the trace of ctx
+func (e *Elog) InfoCtx(ctx context.Context, msg string, fields ...FieldT) {
+	e._logCtx(2, ctx, lInfo, msg, fields)
+}
+
+// VerboseCtx print prefixed (Verbose) log lines with level Verbose, the given fields and the trace of ctx,
+// sampled with the trace when trace sampling is on (see SetTraceSampling)
+func (e *Elog) VerboseCtx(ctx context.Context, msg string, fields ...FieldT) {
+	e._logCtx(2, ctx, lVerbose, msg, fields)
+}
+
+// TraceCtx print prefixed (Trace) log lines with level Trace, the given fields and the trace of ctx,
+// sampled with the trace when trace sampling is on (see SetTraceSampling)
+func (e *Elog) TraceCtx(ctx context.Context, msg string, fields ...FieldT) {
+	e._logCtx(2, ctx, lTrace, msg, fields)
+}
+
+// _logCtx emit a leveled record with fields, the tenant fields, the correlation id and the trace_id and span_id of
+// the trace of ctx, calldepth is the same as for _log
+func (e *Elog) _logCtx(calldepth int, ctx context.Context, level llevel, msg string, fields []FieldT) {
+	if fn := e._reg._tenantExtractor; fn != nil && ctx != nil {
+		if tenant := fn(ctx); len(tenant) > 0 {
+			fields = append(fields[:len(fields):len(fields)], tenant...)
+		}
+	}
+	if id, ok := CorrelationID(ctx); ok {
+		fields = append(fields[:len(fields):len(fields)], String(KeyCorrelationID, id))
+	}
+	t, traced := e._reg._trace(ctx)
+	if !traced {
+		e._logKV(calldepth+1, level, msg, fields)
+		return
+	}
+	fields = append(fields[:len(fields):len(fields)], String("trace_id", t.TraceID))
+	if t.SpanID != "" {
+		fields = append(fields, String("span_id", t.SpanID))
+	}
+	if !e._reg._traceSampling || level < lVerbose {
+		e._logKV(calldepth+1, level, msg, fields)
+		return
+	}
+	if e._reg._muted(level) {
+		e._debugDropped(level)
+		return
+	}
+	if !t.Sampled {
+		e._debugf("%s record dropped: trace %s not sampled", level, t.TraceID)
+		return
+	}
+	e._emit(calldepth+1, level, _valid(level.String()), msg, fields)
+}
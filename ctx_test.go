@@ -0,0 +1,78 @@
+package elogging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCtxTrace(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestCtxTrace", "info", b)
+	elog.SetFlags(0)
+	sampled := ContextWithTrace(context.Background(), TraceInfo{TraceID: "t1", SpanID: "s1", Sampled: true})
+	unsampled := ContextWithTrace(context.Background(), TraceInfo{TraceID: "t2"})
+
+	elog.InfoCtx(sampled, "request")
+	elog.TraceCtx(sampled, "details")
+	if expected := "TestCtxTrace (INFO) request trace_id=t1 span_id=s1\n"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	r.SetTraceSampling(true)
+	elog.TraceCtx(sampled, "details")
+	elog.TraceCtx(unsampled, "hidden")
+	elog.TraceCtx(context.Background(), "no trace")
+	elog.InfoCtx(unsampled, "kept")
+	if expected := "TestCtxTrace (TRACE) details trace_id=t1 span_id=s1\nTestCtxTrace (INFO) kept trace_id=t2\n"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	elog.SetLevel("trace")
+	elog.VerboseCtx(unsampled, "hidden")
+	r.LogsOff()
+	elog.VerboseCtx(sampled, "hidden")
+	if b.Len() > 0 {
+		t.Errorf("unexpected output %q", b.String())
+	}
+}
+
+func TestTraceExtractor(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestTraceExtractor", "info", b)
+	type key struct{}
+	r.SetTraceExtractor(func(ctx context.Context) (TraceInfo, bool) {
+		id, ok := ctx.Value(key{}).(string)
+		return TraceInfo{TraceID: id}, ok
+	})
+	elog.WarnCtx(context.WithValue(context.Background(), key{}, "abc"), "slow")
+	if !strings.Contains(b.String(), "slow trace_id=abc") {
+		t.Errorf("unexpected output %q", b.String())
+	}
+}
+
+func TestTenantExtractor(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestTenantExtractor", "info", b)
+	elog.SetFlags(0)
+	type key struct{}
+	r.SetTenantExtractor(func(ctx context.Context) []FieldT {
+		if id, ok := ctx.Value(key{}).(string); ok {
+			return []FieldT{String("tenant", id)}
+		}
+		return nil
+	})
+	ctx := ContextWithTrace(context.WithValue(context.Background(), key{}, "acme"), TraceInfo{TraceID: "t1"})
+	elog.InfoCtx(ctx, "export", Int("rows", 3))
+	elog.InfoCtx(context.Background(), "no tenant")
+	expected := "TestTenantExtractor (INFO) export rows=3 tenant=acme trace_id=t1\nTestTenantExtractor (INFO) no tenant\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+}
@@ -0,0 +1,92 @@
+package elogging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// SetDebug turn the diagnostics of the registry on or off, see the package SetDebug
+func (r *Registry) SetDebug(on bool) {
+	if !on {
+		r._debug = nil
+	} else if r._debug == nil {
+		r._debug = r._newElog("elogging", LEVEL_Trace, os.Stderr)
+	}
+}
+
+// SetDebug make the package emit its own diagnostics on a dedicated Elog (scope "elogging", writing to stderr,
+// not registered): the records dropped by a gate and which gate dropped them (logs off, levels, trace filter),
+// the records kept by a capture, the output and sinks each record is written to and the write failures
+func SetDebug(on bool) {
+	_defaultRegistry.SetDebug(on)
+}
+
+// DebugLog return the Elog of the diagnostics of the registry, nil when debugging is off
+func (r *Registry) DebugLog() *Elog {
+	return r._debug
+}
+
+// DebugLog return the Elog of the package diagnostics (see SetDebug) to change its output or flags, nil when debugging is off
+func DebugLog() *Elog {
+	return _defaultRegistry._debug
+}
+
+// _debugf emit a diagnostic about the Elog when debugging is on, whatever the gates
+func (e *Elog) _debugf(format string, args ...interface{}) {
+	d := e._reg._debug
+	if d == nil || d == e {
+		return
+	}
+	d._emit(1, lTrace, "DEBUG", fmt.Sprintf(format, args...), []FieldT{String("scope", e._scope())})
+}
+
+// _debugDropped report a record dropped by the gates when debugging is on
+func (e *Elog) _debugDropped(level llevel) {
+	if e._reg._debug == nil || atomic.LoadInt32(&e._reg._silence) > 0 {
+		return
+	}
+	var reason string
+	switch {
+	case e._reg._muted(level):
+		reason = "logs are off"
+	case level == lPrint:
+		gate, _ := e._printGateLevel()
+		reason = fmt.Sprintf("print records are gated as %s records: %s", gate, e._levelReason(gate))
+	case e._enabled(level):
+		reason = "call site not matching the trace filter"
+	default:
+		reason = e._levelReason(level)
+	}
+	e._debugf("%s record dropped: %s", level, reason)
+}
+
+// _levelReason describe why the levels disable a record at level
+func (e *Elog) _levelReason(level llevel) string {
+	if g := e._reg._getGlobalLevel(); g > lDisabled {
+		switch e._reg._getGlobalMode() {
+		case GlobalCeiling:
+			return fmt.Sprintf("%s is above the scope level %s or the global level %s (ceiling mode)", level, e._level(), g)
+		case GlobalOverride:
+			return fmt.Sprintf("%s is above the global level %s (override mode)", level, g)
+		}
+		return fmt.Sprintf("%s is above the scope level %s and the global level %s", level, e._level(), g)
+	}
+	return fmt.Sprintf("%s is above the scope level %s", level, e._level())
+}
+
+// _debugEmitted report the destinations of an emitted record when debugging is on
+func (e *Elog) _debugEmitted(level llevel) {
+	if e._reg._debug == nil || e._reg._debug == e {
+		return
+	}
+	dest := []string{_describeOutput(e._getOutput())}
+	if levelOut, min := e._getLevelOutput(); levelOut != nil && level != lPrint && level <= min {
+		dest = append(dest, "level output "+_describeOutput(levelOut))
+	}
+	for _, s := range e._getSinks() {
+		dest = append(dest, fmt.Sprintf("sink %T", s))
+	}
+	e._debugf("%s record sent to %s", level, strings.Join(dest, ", "))
+}
@@ -0,0 +1,57 @@
+package elogging
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type failingSink struct{}
+
+func (failingSink) WriteRecord(rec *Record) error { return errors.New("sink down") }
+
+func TestSetDebug(t *testing.T) {
+	r := NewRegistry()
+	elog := r.NewElog("TestSetDebug", "info", &bytes.Buffer{})
+	elog.Trace("before")
+	if r.DebugLog() != nil {
+		t.Fatal("debug log while off")
+	}
+
+	r.SetDebug(true)
+	diag := &bytes.Buffer{}
+	r.DebugLog().ModifyParams("", "", diag)
+	r.DebugLog().SetFlags(0)
+	elog.Trace("hidden")
+	r.SetGlobalLogLevel("verbose")
+	elog.Trace("hidden")
+	r.SetTraceFilter("nomatch.go")
+	r.SetGlobalLogLevel("trace")
+	elog.Trace("hidden")
+	r.SetTraceFilter()
+	elog.AddSink(failingSink{})
+	elog.Info("shown")
+	r.LogsOff()
+	elog.Print("hidden")
+	r.LogsOn()
+
+	for _, expected := range []string{
+		"elogging (DEBUG) Trace record dropped: Trace is above the scope level Info scope=TestSetDebug\n",
+		"Trace record dropped: Trace is above the scope level Info and the global level Verbose",
+		"Trace record dropped: call site not matching the trace filter",
+		"Info record sent to *bytes.Buffer, sink elogging.failingSink",
+		"Info record sink elogging.failingSink failed: sink down",
+		"Print record dropped: logs are off",
+	} {
+		if !strings.Contains(diag.String(), expected) {
+			t.Errorf("missing %q in %q", expected, diag.String())
+		}
+	}
+	r.SetDebug(false)
+	diag.Reset()
+	elog.Trace("hidden")
+	if r.DebugLog() != nil || diag.Len() != 0 {
+		t.Error("diagnostics while off")
+	}
+}
@@ -0,0 +1,33 @@
+// Package elogbench run the emit path benchmarks of elogging from the benchmarks of an application, kept apart
+// from elogging so the testing package is not linked into the programs using it.
+package elogbench
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gilwo/elogging"
+)
+
+// BenchmarkEmit run a benchmark of the path, reporting allocations and records/s, e.g.
+//  func BenchmarkLogging(b *testing.B) {
+//  	for _, p := range elogging.BenchmarkPaths {
+//  		b.Run(p.String(), func(b *testing.B) { elogbench.BenchmarkEmit(b, p) })
+//  	}
+//  }
+func BenchmarkEmit(b *testing.B, path elogging.BenchmarkPath) {
+	sink := elogging.NewBenchmarkSink(nil)
+	emit := elogging.BenchmarkEmitter(path, sink)
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		emit(i)
+	}
+	d := time.Since(start)
+	b.StopTimer()
+	if d > 0 {
+		b.ReportMetric(float64(b.N)/d.Seconds(), "records/s")
+	}
+	b.SetBytes(int64(sink.Bytes()) / int64(b.N))
+}
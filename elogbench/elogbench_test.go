@@ -0,0 +1,22 @@
+package elogbench
+
+import (
+	"testing"
+
+	"github.com/gilwo/elogging"
+)
+
+func TestBenchmarkEmit(t *testing.T) {
+	for _, p := range elogging.BenchmarkPaths {
+		res := testing.Benchmark(func(b *testing.B) { BenchmarkEmit(b, p) })
+		if res.N == 0 || res.Bytes == 0 || res.Extra["records/s"] <= 0 {
+			t.Errorf("unexpected %s result %+v", p, res)
+		}
+	}
+}
+
+func BenchmarkEmitPaths(b *testing.B) {
+	for _, p := range elogging.BenchmarkPaths {
+		b.Run(p.String(), func(b *testing.B) { BenchmarkEmit(b, p) })
+	}
+}
@@ -16,6 +16,25 @@
 //
 // when creating log objects, global defaults paramaters are set to each created log object.
 // it is possible to change the log object paramters on the fly.
+//
+// Lazy Evaluation
+//
+// arguments and field values (including fmt.Stringer and error values) are only formatted once a record passed
+// the level filtering and is about to be written, use Lazy() for values which are expensive to compute
+//
+// Formats
+//
+// records are written as plain text (same layout as the golang log package), colored text (ELColorLog flag)
+// or JSON objects (ELJSONLog flag), the format is part of the log object flags.
+//
+// Concurrency
+//
+// log objects can be shared by goroutines: the logging methods may run concurrently with each other and with
+// the changes of the level (including PushLevel and PopLevel), flags, outputs, hooks, sinks, labels, collection
+// rendering and scope of the log object, with the creation, listing and clearing of log objects, with the registry
+// switches (LogsOff, LogsOn, SetGlobalLogLevel, SetGlobalLevelMode), the freeze of the configuration and the
+// bootstrap buffer. The other registry settings (defaults, policies, schemas, ...) are meant to be set up before
+// logging starts.
 package elogging
 
 import (
@@ -24,41 +43,130 @@ import (
 	"io"
 	"log"
 	"os"
+	"path"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
-var (
-	_defaultOut   io.Writer
-	_globalLevel  llevel
-	logsActive    bool             = true
-	_logs         map[*Elog]string = map[*Elog]string{}
-	_defaultFlags                  = log.Ldate | log.Lmicroseconds | log.Llongfile | log.LUTC | log.Lmsgprefix /* Lshortfile override Llongfile */
-)
+const _initialFlags = log.Ldate | log.Lmicroseconds | log.Llongfile | log.LUTC | log.Lmsgprefix /* Lshortfile override Llongfile */
+
+// DefaultFlags return the currently active flags for a new Elog of the registry
+func (r *Registry) DefaultFlags() int {
+	return r._defaultFlags
+}
 
 // DefaultFlags return the currently active flags for a new Elog
 func DefaultFlags() int {
-	return _defaultFlags
+	return _defaultRegistry.DefaultFlags()
+}
+
+// SetDefaultOutput replace the output of the new Elogs of the registry created without an explicit output
+func (r *Registry) SetDefaultOutput(out io.Writer) {
+	if !r._allow("", "default_output") {
+		return
+	}
+	r._audit("", "default_output", _describeOutput(r._defaultOut), _describeOutput(out))
+	r._defaultOut = out
 }
 
 // SetDefaultFlags replace the default flags with the given flags value
 func SetDefaultOutput(out io.Writer) {
-	_defaultOut = out
+	_defaultRegistry.SetDefaultOutput(out)
+}
+
+// DefaultLevel return the level used for a new Elog of the registry created without an explicit level
+func (r *Registry) DefaultLevel() string {
+	return r._defaultLevel.String()
+}
+
+// DefaultLevel return the level used for a new Elog created without an explicit level
+func DefaultLevel() string {
+	return _defaultRegistry.DefaultLevel()
+}
+
+// SetDefaultLevel replace the level used for a new Elog of the registry created without an explicit level
+func (r *Registry) SetDefaultLevel(level string) {
+	if !r._allow("", "default_level") {
+		return
+	}
+	r._checkLevelName(level)
+	l := _value(_valid(level))
+	r._audit("", "default_level", r._defaultLevel.String(), l.String())
+	r._defaultLevel = l
+}
+
+// SetDefaultLevel replace the level used for a new Elog created without an explicit level
+func SetDefaultLevel(level string) {
+	_defaultRegistry.SetDefaultLevel(level)
+}
+
+// SetDefaultFlags replace the default flags of the registry with the given flags value
+func (r *Registry) SetDefaultFlags(flags int) {
+	if !r._allow("", "default_flags") {
+		return
+	}
+	r._audit("", "default_flags", _flagsString(r._defaultFlags), _flagsString(flags))
+	r._defaultFlags = flags
 }
 
 // SetDefaultFlags replace the default flags with the given flags value
 func SetDefaultFlags(flags int) {
-	_defaultFlags = flags
+	_defaultRegistry.SetDefaultFlags(flags)
 }
 
-// LogsOff disable all output logs from logs created by the logging library
+// LogsOff disable all output logs from the Elogs of the registry
+func (r *Registry) LogsOff() {
+	if !r._allow("", "logs_active") {
+		return
+	}
+	r._audit("", "logs_active", fmt.Sprint(r._active()), "false")
+	r._setActive(false, lDisabled)
+}
+
+// LogsOff disable all output logs from logs created by the logging library, Fatal and Panic records excepted,
+// see LogsOffExcept to keep the errors
 func LogsOff() {
-	logsActive = false
+	_defaultRegistry.LogsOff()
+}
+
+// LogsOffExcept disable the output logs of the Elogs of the registry below a floor level, see the package LogsOffExcept
+func (r *Registry) LogsOffExcept(level string) error {
+	l, err := _parseLevel(level)
+	if err != nil {
+		return err
+	}
+	if !r._allow("", "logs_active") {
+		return nil
+	}
+	r._audit("", "logs_active", fmt.Sprint(r._active()), "false except "+l.String())
+	r._setActive(false, l)
+	return nil
+}
+
+// LogsOffExcept disable the output logs below a floor level rather than all of them, e.g. LogsOffExcept("error")
+// mutes everything but the error records, which still follow the levels of their Elogs. LogsOn resume all logs,
+// LogsOff mute the floor too.
+func LogsOffExcept(level string) error {
+	return _defaultRegistry.LogsOffExcept(level)
+}
+
+// LogsOn enable the output logs of the Elogs of the registry
+func (r *Registry) LogsOn() {
+	if !r._allow("", "logs_active") {
+		return
+	}
+	r._audit("", "logs_active", fmt.Sprint(r._active()), "true")
+	r._setActive(true, lDisabled)
 }
 
 // LogsOn enable logs output, all levels are resumed to their previous levels
 func LogsOn() {
-	logsActive = true
+	_defaultRegistry.LogsOn()
 }
 
 type llevel int32
@@ -70,6 +178,11 @@ const (
 	lInfo
 	lVerbose
 	lTrace
+
+	lPrint llevel = -1 // records of the Print family, not subject to leveling
+	lFatal llevel = -2 // records of the Fatal and Panic families, never filtered
+
+	_numLevels = int(lTrace - lFatal + 1)
 )
 
 const (
@@ -95,6 +208,10 @@ func (l llevel) String() string {
 		return "Verbose"
 	case lTrace:
 		return "Trace"
+	case lPrint:
+		return "Print"
+	case lFatal:
+		return "Fatal"
 	}
 	return "Disabled"
 }
@@ -132,23 +249,54 @@ func _valid(level string) string {
 	return "DISABLE"
 }
 
+// _parseLevel return the level for the given level name, unknown names are reported as an error
+func _parseLevel(level string) (llevel, error) {
+	switch strings.ToLower(level) {
+	case "disabled", "disable", "off", "none":
+		return lDisabled, nil
+	}
+	if _valid(level) == "DISABLE" {
+		return lDisabled, fmt.Errorf("elogging: unknown level %q", level)
+	}
+	return _value(level), nil
+}
+
 // Elog represent a scoped leveled log
 type Elog struct {
-	scope string
-	level llevel
-	_log  *log.Logger
-	_id   string
-	_out  io.Writer
+	scope   string
+	level   llevel // atomic, see _scopeLevel
+	_flags  int32  // atomic, see _getFlags, the flags fit in 31 bits
+	_id     string
+	_out    io.Writer
+	_mu     *sync.Mutex         // serializes the writes, guards the outputs
+	_conf   *sync.RWMutex       // guards the scope, hooks, sinks and labels
+	_compat [_numFamilies]int32 // atomic, see _getCompat
+	_reg    *Registry
+
+	_levelOut      io.Writer // additional output for records at or above _levelOutLevel
+	_levelOutLevel llevel
+	_hooks         []Hook
+	_labels        []FieldT
+	_fields        []FieldT // default fields of the registry, emitted before the record fields
+	_sinks         []Sink
+	_levelStack    []llevel           // guarded by _conf
+	_override      llevel             // level set by the override table of the registry, atomic, _noOverride when none
+	_printGate     int32              // atomic, the Print family gate and its level, see _getPrintGate
+	_cleared       int32              // removed from the registry by Clear, atomic
+	_repeat        *_repeatRun        // run of repeated records, see ELSuppressRepeated
+	_collections   *CollectionOptions // guarded by _conf
+
+	_stats *elogCounters
 }
 
 // String descrption of an Elog instance
-func (e Elog) String() string {
-	return fmt.Sprintf("[%s:%s:(%s)]", e._id, e.scope, e.level)
+func (e *Elog) String() string {
+	return fmt.Sprintf("[%s:%s:(%s)]", e._id, e._scope(), e._scopeLevel())
 }
 
 // Scope retrieve the scope of the given Elog instance
 func (e *Elog) Scope() string {
-	return e.scope
+	return e._scope()
 }
 
 // ID retrieve the id of the given Elog instance
@@ -156,14 +304,90 @@ func (e *Elog) ID() string {
 	return e._id
 }
 
+// LastActive retrieve the time of the last record emitted by the Elog (its creation time if it never emitted)
+func (e *Elog) LastActive() time.Time {
+	return e._stats.lastActive()
+}
+
+// SetGlobalLogLevel change the log level of all the Elogs of the registry
+func (r *Registry) SetGlobalLogLevel(level string) {
+	r._setGlobalLogLevel(nil, level)
+}
+
+// _setGlobalLogLevel change the global level with the changes authorized by t (nil for none)
+func (r *Registry) _setGlobalLogLevel(t *FreezeToken, level string) {
+	if !r._allowWith(t, "", "global_level") {
+		return
+	}
+	r._checkLevelName(level)
+	l := _value(_valid(level))
+	r._audit("", "global_level", r._getGlobalLevel().String(), l.String())
+	r._setGlobalLevel(l)
+}
+
 // SetGlobalLogLevel change the log level of all the Elog objects
 func SetGlobalLogLevel(level string) {
-	_globalLevel = _value(_valid(level))
+	_defaultRegistry.SetGlobalLogLevel(level)
 }
 
-// SetScopeLogLevelByID change the log level of the Elog associated with the given id
-func SetScopeLogLevelByID(id, level string) {
-	for k := range _logs {
+// GlobalLevelMode select how the global level combines with the level of each Elog
+type GlobalLevelMode int32
+
+const (
+	GlobalFloor    GlobalLevelMode = iota // a record passes the scope level or the global level, the global level raises the verbosity (default)
+	GlobalCeiling                         // a record passes the scope level and the global level, the global level caps the verbosity
+	GlobalOverride                        // a record passes the global level, the scope levels are ignored
+)
+
+func (m GlobalLevelMode) String() string {
+	switch m {
+	case GlobalCeiling:
+		return "ceiling"
+	case GlobalOverride:
+		return "override"
+	}
+	return "floor"
+}
+
+// ParseGlobalLevelMode parse a global level mode name: floor, ceiling or override
+func ParseGlobalLevelMode(s string) (GlobalLevelMode, error) {
+	for _, m := range []GlobalLevelMode{GlobalFloor, GlobalCeiling, GlobalOverride} {
+		if strings.EqualFold(s, m.String()) {
+			return m, nil
+		}
+	}
+	return GlobalFloor, fmt.Errorf("elogging: unknown global level mode %q", s)
+}
+
+// SetGlobalLevelMode select how the global level of the registry combines with the level of its Elogs
+func (r *Registry) SetGlobalLevelMode(mode GlobalLevelMode) {
+	if !r._allow("", "global_level_mode") {
+		return
+	}
+	r._audit("", "global_level_mode", r._getGlobalMode().String(), mode.String())
+	r._setGlobalMode(mode)
+}
+
+// SetGlobalLevelMode select how the global level (when set) combines with the level of each Elog:
+// GlobalFloor force verbose (e.g. trace everywhere while debugging), GlobalCeiling force quiet
+// (e.g. errors only whatever the scope levels) and GlobalOverride replace the scope levels
+func SetGlobalLevelMode(mode GlobalLevelMode) {
+	_defaultRegistry.SetGlobalLevelMode(mode)
+}
+
+// GetGlobalLevelMode retrieve the global level mode of the registry
+func (r *Registry) GetGlobalLevelMode() GlobalLevelMode {
+	return r._getGlobalMode()
+}
+
+// GetGlobalLevelMode retrieve the global level mode
+func GetGlobalLevelMode() GlobalLevelMode {
+	return _defaultRegistry._getGlobalMode()
+}
+
+// SetScopeLogLevelByID change the log level of the Elog of the registry associated with the given id
+func (r *Registry) SetScopeLogLevelByID(id, level string) {
+	for k := range r._registered() {
 		if k._id == id {
 			k.SetLevel(level)
 			return
@@ -171,24 +395,71 @@ func SetScopeLogLevelByID(id, level string) {
 	}
 }
 
+// SetScopeLogLevelByID change the log level of the Elog associated with the given id
+func SetScopeLogLevelByID(id, level string) {
+	_defaultRegistry.SetScopeLogLevelByID(id, level)
+}
+
+// SetScopeLogLevel change the log level of all the Elogs of the registry with the given scope (pattern or alias)
+func (r *Registry) SetScopeLogLevel(scope, level string) {
+	r._setScopeLogLevel(nil, scope, level)
+}
+
+// _setScopeLogLevel change the level of the Elogs with the given scope with the changes authorized by t (nil for none)
+func (r *Registry) _setScopeLogLevel(t *FreezeToken, scope, level string) {
+	scope = r.ResolveScope(scope)
+	l := _value(_valid(level))
+	for k, v := range r._registered() {
+		if _matchScope(scope, v) {
+			r._checkLevelName(level)
+			k._setLevelWith(t, l)
+		}
+	}
+	if std := r._stdLog; std != nil && _matchScope(scope, std._scope()) {
+		r._checkLevelName(level)
+		std._setLevelWith(t, l)
+	}
+}
+
+// SetScopeLogLevel change the log level of all the Elogs with the given scope,
+// scope can also be a pattern (path.Match syntax, e.g. "db.*") matching several scopes or a scope alias,
+// the package default log (unscoped) is matched by the patterns matching any scope, e.g. "*"
+func SetScopeLogLevel(scope, level string) {
+	_defaultRegistry.SetScopeLogLevel(scope, level)
+}
+
+// _matchScope report whether scope is equal to or matched by pattern
+func _matchScope(pattern, scope string) bool {
+	if pattern == scope {
+		return true
+	}
+	matched, _ := path.Match(pattern, scope)
+	return matched
+}
+
 type elogList []*Elog
 
 func (a elogList) Len() int           { return len(a) }
 func (a elogList) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a elogList) Less(i, j int) bool { return a[i].String() < a[j].String() }
 
-// ListScopedLogs return a list of all the existing Elog objects (sorted)
-func ListScopedLogs() (elogs []*Elog) {
-	for k := range _logs {
+// ListScopedLogs return a list of all the Elogs of the registry (sorted)
+func (r *Registry) ListScopedLogs() (elogs []*Elog) {
+	for k := range r._registered() {
 		elogs = append(elogs, k)
 	}
 	sort.Sort(elogList(elogs))
 	return
 }
 
-// ListScopesAndLevels return a lists of scopes, ids and levels for the existing logs
-func ListScopesAndLevels() (scopes, ids, levels []string) {
-	for k, v := range _logs {
+// ListScopedLogs return a list of all the existing Elog objects (sorted)
+func ListScopedLogs() []*Elog {
+	return _defaultRegistry.ListScopedLogs()
+}
+
+// ListScopesAndLevels return a lists of scopes, ids and levels for the Elogs of the registry
+func (r *Registry) ListScopesAndLevels() (scopes, ids, levels []string) {
+	for k, v := range r._registered() {
 		scopes = append(scopes, v)
 		levels = append(levels, k.GetLevel())
 		ids = append(ids, k._id)
@@ -196,9 +467,14 @@ func ListScopesAndLevels() (scopes, ids, levels []string) {
 	return
 }
 
-// GetScopedLogByID return the Elog object associated with the given ID
-func GetScopedLogByID(id string) (elog *Elog) {
-	for k := range _logs {
+// ListScopesAndLevels return a lists of scopes, ids and levels for the existing logs
+func ListScopesAndLevels() (scopes, ids, levels []string) {
+	return _defaultRegistry.ListScopesAndLevels()
+}
+
+// GetScopedLogByID return the Elog of the registry associated with the given ID
+func (r *Registry) GetScopedLogByID(id string) (elog *Elog) {
+	for k := range r._registered() {
 		if k._id == id {
 			return k
 		}
@@ -206,32 +482,97 @@ func GetScopedLogByID(id string) (elog *Elog) {
 	return
 }
 
+// GetScopedLogByID return the Elog object associated with the given ID
+func GetScopedLogByID(id string) *Elog {
+	return _defaultRegistry.GetScopedLogByID(id)
+}
+
+// NewElogDefaults create an Elog in the registry with its default level and output
+func (r *Registry) NewElogDefaults(scope string) *Elog {
+	return r.NewElog(scope, "", r._defaultOut)
+}
+
 // Create an Elog object
 func NewElogDefaults(scope string) *Elog {
-	return NewElog(scope, "info", _defaultOut)
+	return _defaultRegistry.NewElogDefaults(scope)
 }
 
 // NewElog create a scoped leveled logger wrapping the native golang log package.
 // it creates a new log and provide scheme to have a scope and level for the logger.
 // the newly created logger is created with the following flags:
 //  log.Ldate | log.Lmicroseconds | log.Llongfile | log.LUTC | log.Lmsgprefix
-// level is the initial level for this log, empty level default to info level.
+// level is the initial level for this log, empty level default to the default level (info unless changed).
 // out is where the log will be output, empty out default to os.stdout.
 // check golang log packge doc for additional information.
-func NewElog(scope, level string, out io.Writer) (e *Elog) {
+// when the scope is already registered the duplicate scope policy applies (see SetDuplicateScopePolicy).
+func NewElog(scope, level string, out io.Writer) *Elog {
+	return _defaultRegistry.NewElog(scope, level, out)
+}
+
+// NewElog create an Elog in the registry, see the package NewElog
+func (r *Registry) NewElog(scope, level string, out io.Writer) (e *Elog) {
+	switch r._duplicatePolicy {
+	case DuplicateReuse:
+		if e = r._findScope(scope); e != nil {
+			return
+		}
+	case DuplicateSuffix:
+		if r._findScope(scope) != nil {
+			base := scope
+			for n := 2; r._findScope(scope) != nil; n++ {
+				scope = base + "#" + strconv.Itoa(n)
+			}
+		}
+	}
+	if r._scopeDir.path != "" && (out == nil || out == r._defaultOut) {
+		if f, err := r._scopeFile(scope); err != nil {
+			_internalf("%v", err)
+		} else {
+			out = f
+		}
+	}
+	e = r._newElog(scope, level, out)
+	r._register(e)
+	return
+}
+
+// NewElogWithLevelOutput create an Elog like NewElog which additionally writes the records at or above
+// levelOutMin (e.g. "warning": warnings and errors) to levelOut, e.g. to have errors on the console as well
+func NewElogWithLevelOutput(scope, level string, out io.Writer, levelOut io.Writer, levelOutMin string) *Elog {
+	return NewElog(scope, level, out).ModifyLevelOutput(levelOut, levelOutMin)
+}
+
+// _newElog create an Elog with the registry defaults without registering it
+func (r *Registry) _newElog(scope, level string, out io.Writer) (e *Elog) {
+	if err := r.CheckScope(scope); err != nil {
+		_internalf("%v", err)
+	}
 	if out == nil {
 		out = os.Stdout
 	}
+	r._checkLevelName(level)
 	if level == "" {
-		level = "info"
+		level = r._defaultLevel.String()
 	}
-	// fmt.Printf("----- creating log with flags: [%#x]\n", _defaultFlags)
 	e = &Elog{
-		scope: scope,
-		level: _value(_valid(level)),
-		_log:  log.New(out, scope, _defaultFlags),
-		_out:  out,
+		scope:  scope,
+		level:  _value(_valid(level)),
+		_flags: int32(r._defaultFlags),
+		_out:   out,
+		_mu:    &sync.Mutex{},
+		_conf:  &sync.RWMutex{},
+		_reg:   r,
+
+		_repeat: &_repeatRun{},
+
+		_fields: r._defaultFields,
+
+		_printGate: _packPrintGate(PrintBypass, lInfo),
+
+		_stats: _newCounters(),
 	}
+	e._stats.touch(time.Now())
+	r._applyOverride(e)
 	_hash := func(s string) string {
 		h := sha1.New()
 		h.Write([]byte(s))
@@ -239,158 +580,509 @@ func NewElog(scope, level string, out io.Writer) (e *Elog) {
 	}
 
 	e._id = _hash(fmt.Sprintf("%s%p", scope, e))
-
-	_logs[e] = scope
 	return
 }
 
 // SetOutput allow to change the parameters of the log; output, level and output, previous log messages are not kept if output is changed
 func (e *Elog) ModifyParams(modScope, modLevel string, modOut io.Writer) *Elog {
-	changed := false
-	if modScope != "" && modScope != e.scope {
-		e.scope = modScope
-		changed = true
+	if scope := e._scope(); modScope != "" && modScope != scope && e._allow("scope") {
+		e._audit("scope", scope, modScope)
+		e._setScope(modScope)
+		e._reg._applyOverride(e)
 	}
-	if modOut != nil && modOut != e._out {
-		e._out = modOut
-		changed = true
+	if out := e._getOutput(); modOut != nil && modOut != out && e._allow("output") {
+		e._audit("output", _describeOutput(out), _describeOutput(modOut))
+		e._setOutput(modOut)
 	}
-	if modLevel != "" && modLevel != e.level.String() {
-		e.level = _value(_valid(modLevel))
-		changed = true
+	if modLevel != "" && modLevel != e._scopeLevel().String() {
+		e._setLevel(_value(_valid(modLevel)))
 	}
-	if changed {
-		e._log = log.New(e._out, e.scope, e.GetFlags())
+	return e
+}
+
+// ModifyLevelOutput set an additional output receiving the records at or above minLevel
+// (Print records excepted), a nil out remove the additional output
+func (e *Elog) ModifyLevelOutput(out io.Writer, minLevel string) *Elog {
+	if !e._allow("level_output") {
+		return e
 	}
+	prev, _ := e._getLevelOutput()
+	e._audit("level_output", _describeOutput(prev), _describeOutput(out)+" "+minLevel)
+	e._mu.Lock()
+	e._levelOut, e._levelOutLevel = out, _value(_valid(minLevel))
+	e._mu.Unlock()
 	return e
 }
 
 // Clear remove this Elog from the existing Elog, the Elog is unsuable following this invocation
 //
-// log is invalid following this invocation and any additional calls will create an unexpected behaviour
-//
-// TODO: check what happens if someone call any leveled log for this log
+// log is invalid following this invocation, the records of any additional logging call are dropped
+// (reported by SetMisuseWarnings)
 func (e *Elog) Clear() {
-	delete(_logs, e)
-	e.level = lDisabled
-	e._log = nil
+	e.FlushRepeated()
+	e._reg._logsMu.Lock()
+	delete(e._reg._logs, e)
+	e._reg._logsMu.Unlock()
+	atomic.StoreInt32(&e._cleared, 1)
+	e._storeLevel(lDisabled)
+	e._storeOverride(_noOverride)
+	e._setOutput(nil)
 }
 
 // SetLevel change the current level of the Elog to the given level
 func (e *Elog) SetLevel(level string) {
-	e.level = _value(_valid(level))
+	e._reg._checkLevelName(level)
+	e._setLevel(_value(_valid(level)))
+}
+
+// _setLevel change the level of the Elog, auditing the change
+func (e *Elog) _setLevel(level llevel) {
+	e._setLevelWith(nil, level)
+}
+
+// _setLevelWith change the level of the Elog with the change authorized by t (nil for none), auditing the change
+func (e *Elog) _setLevelWith(t *FreezeToken, level llevel) {
+	if !e._allowWith(t, "level") {
+		return
+	}
+	e._audit("level", e._scopeLevel().String(), level.String())
+	e._storeLevel(level)
+}
+
+// PushLevel save the current level of the Elog and change it to the given level until the matching PopLevel,
+// e.g. raise the verbosity for the duration of an operation:
+//  e.PushLevel("trace")
+//  defer e.PopLevel()
+func (e *Elog) PushLevel(level string) {
+	e._conf.Lock()
+	e._levelStack = append(e._levelStack, e._scopeLevel())
+	e._conf.Unlock()
+	e._setLevel(_value(_valid(level)))
+}
+
+// PopLevel restore the level saved by the last PushLevel, whatever the level was changed to in between,
+// it does nothing when there is no saved level
+func (e *Elog) PopLevel() {
+	e._conf.Lock()
+	n := len(e._levelStack)
+	if n == 0 {
+		e._conf.Unlock()
+		return
+	}
+	level := e._levelStack[n-1]
+	e._levelStack = e._levelStack[:n-1]
+	e._conf.Unlock()
+	e._setLevel(level)
 }
 
 // CycleLevelUp change the current level of the Elog to the next level in a cyclic manner
 func (e *Elog) CycleLevelUp() {
-	e.level = (e.level + 1) % (lTrace + 1)
+	e._setLevel((e._scopeLevel() + 1) % (lTrace + 1))
 }
 
 // CycleLevelDown change the current level of the Elog to the previous level in a cyclic manner
 func (e *Elog) CycleLevelDown() {
-	e.level = (e.level - 1) % (lTrace + 1)
+	e._setLevel((e._scopeLevel() + lTrace) % (lTrace + 1))
 }
 
 // GetLevel retrieve the current level of the Elog
 func (e *Elog) GetLevel() string {
-	return e.level.String()
+	return e._scopeLevel().String()
 }
 
 // GetFlags retrieve the current flags of the Elog
 func (e *Elog) GetFlags() int {
-	return e._log.Flags()
+	return e._getFlags()
 }
 
 // SetFlags replace the current flags of the Elog
 func (e *Elog) SetFlags(flags int) {
-	e._log.SetFlags(flags)
+	if !e._allow("flags") {
+		return
+	}
+	e._audit("flags", _flagsString(e._getFlags()), _flagsString(flags))
+	if e._getFlags()&ELSuppressRepeated != 0 && flags&ELSuppressRepeated == 0 {
+		e.FlushRepeated()
+	}
+	e._storeFlags(flags)
 }
 
 // Println print prefixed (Println) log lines ingoring the leveled logging mechanism
 func (e *Elog) Println(args ...interface{}) {
-	if !logsActive {
+	if e._dropCleared("Println") {
+		return
+	}
+	e._checkCall("Println", args)
+	if !e._printEnabled() {
+		e._debugDropped(lPrint)
 		return
 	}
-	e._log.Output(2, " (Println) "+fmt.Sprintln(args...))
+	e._output(2, lPrint, "Println", fmt.Sprintln(e._reg._renderArgs(args)...))
 }
 
 // Printf print prefixed (Printf) log lines ingoring the leveled logging mechanism
 func (e *Elog) Printf(format string, args ...interface{}) {
-	if !logsActive {
+	if e._dropCleared("Printf") {
+		return
+	}
+	if !e._printEnabled() {
+		e._debugDropped(lPrint)
 		return
 	}
-	e._log.Output(2, " (Printf) "+fmt.Sprintf(format, args...))
+	e._output(2, lPrint, "Printf", fmt.Sprintf(format, e._reg._renderArgs(args)...))
 }
 
 // Print print prefixed (Print) log lines ingoring the leveled logging mechanism
 func (e *Elog) Print(args ...interface{}) {
-	if !logsActive {
+	if e._dropCleared("Print") {
 		return
 	}
-	e._log.Output(2, " (Print) "+fmt.Sprint(args...))
+	e._checkCall("Print", args)
+	if !e._printEnabled() {
+		e._debugDropped(lPrint)
+		return
+	}
+	e._output(2, lPrint, "Print", fmt.Sprint(e._reg._renderArgs(args)...))
 }
 
 // All methods below are relate to the level logging mechanism
 
 // Errorf print prefixed (Error) formatted log lines with level Error
 func (e *Elog) Errorf(format string, args ...interface{}) {
-	e._logf(lError, format, args...)
+	e._logf(2, lError, format, args...)
 }
 
 // Warnf print prefixed (Warning) formatted log lines with level Warning
 func (e *Elog) Warnf(format string, args ...interface{}) {
-	e._logf(lWarn, format, args...)
+	e._logf(2, lWarn, format, args...)
 }
 
 // Infof print prefixed (Info) formatted log lines with level Info
 func (e *Elog) Infof(format string, args ...interface{}) {
-	e._logf(lInfo, format, args...)
+	e._logf(2, lInfo, format, args...)
 }
 
 // Verbosef print prefixed (Verbose) formatted log lines with level Verbose
 func (e *Elog) Verbosef(format string, args ...interface{}) {
-	e._logf(lVerbose, format, args...)
+	e._logf(2, lVerbose, format, args...)
 }
 
 // Tracef print prefixed (Trace) formatted log lines with level Trace
 func (e *Elog) Tracef(format string, args ...interface{}) {
-	e._logf(lTrace, format, args...)
+	e._logf(2, lTrace, format, args...)
 }
 
-// Errorf print prefixed (Error) log lines with level Error
+// Error print prefixed (Error) log lines with level Error
 func (e *Elog) Error(args ...interface{}) {
-	e._logf(lError, "", args...)
+	e._log(2, lError, args...)
 }
 
 // Warn print prefixed (Warning) log lines with level Warning
 func (e *Elog) Warn(args ...interface{}) {
-	e._logf(lWarn, "", args...)
+	e._log(2, lWarn, args...)
 }
 
 // Info print prefixed (Info) log lines with level Info
 func (e *Elog) Info(args ...interface{}) {
-	e._logf(lInfo, "", args...)
+	e._log(2, lInfo, args...)
 }
 
 // Verbose print prefixed (Verbose) log lines with level Verbose
 func (e *Elog) Verbose(args ...interface{}) {
-	e._logf(lVerbose, "", args...)
+	e._log(2, lVerbose, args...)
 }
 
 // Trace print prefixed (Trace) log lines with level Trace
 func (e *Elog) Trace(args ...interface{}) {
-	e._logf(lTrace, "", args...)
+	e._log(2, lTrace, args...)
+}
+
+// Output write a message already formatted by the caller as a record of the given level, without the level tag
+// and without further formatting, like log.Output: calldepth is the count of frames to skip when reporting the
+// caller (1 for the caller of Output). The record is gated by the levels like the leveled calls, nil is returned
+// when it is filtered out, an error for an unknown level or a failed write.
+func (e *Elog) Output(calldepth int, level string, msg string) error {
+	l, err := _parseLevel(level)
+	if err != nil || l == lDisabled {
+		return fmt.Errorf("elogging: invalid output level %q", level)
+	}
+	if e._dropCleared("Output") {
+		return nil
+	}
+	if !e._enabled(l) || !e._traceAllowed(calldepth+1, l) {
+		if c := _captureFor(l); c != nil {
+			e._capture(calldepth+1, c, l, msg, nil)
+		}
+		e._debugDropped(l)
+		return nil
+	}
+	return e._output(calldepth+1, l, "", msg)
+}
+
+// WriteRaw write a pre-formatted line (e.g. a replayed record or a line proxied from another process) as a
+// record of the given level: it is gated by the levels and goes through the pipeline of the records (suppression,
+// ring buffer, bootstrap buffer, output, level output, capture and sinks), but is neither formatted nor prefixed,
+// a newline is only appended when missing. The sinks receive it as a record without tag whose message is the line.
+func (e *Elog) WriteRaw(level string, line []byte) error {
+	l, err := _parseLevel(level)
+	if err != nil || l == lDisabled {
+		return fmt.Errorf("elogging: invalid raw level %q", level)
+	}
+	if e._dropCleared("WriteRaw") {
+		return nil
+	}
+	msg := string(line)
+	if len(msg) > 0 && msg[len(msg)-1] == '\n' {
+		msg = msg[:len(msg)-1]
+	}
+	rec := Record{Time: time.Now(), Tag: _valid(l.String()), Scope: e._scope(), Msg: msg, level: l, bare: true, raw: true}
+	if !e._enabled(l) {
+		if c := _captureFor(l); c != nil {
+			c._write(e._format(&rec))
+		}
+		e._debugDropped(l)
+		return nil
+	}
+	return e._emitRecord(2, rec)
+}
+
+func (e *Elog) _enabled(level llevel) bool {
+	r := e._reg
+	if e._getCompat(FamilyLeveled) == CompatStdlib {
+		return !r._muted(level)
+	}
+	if r._muted(level) {
+		return false
+	}
+	g := r._getGlobalLevel()
+	if g == lDisabled {
+		return level <= e._level()
+	}
+	switch r._getGlobalMode() {
+	case GlobalCeiling:
+		return level <= e._level() && level <= g
+	case GlobalOverride:
+		return level <= g
+	}
+	return level <= e._level() || level <= g
+}
+
+func (e *Elog) _printEnabled() bool {
+	if level, gated := e._printGateLevel(); gated {
+		return e._enabled(level)
+	}
+	return !e._reg._muted(lPrint)
+}
+
+// _muted report whether the records of a level are muted by LogsOff, LogsOffExcept or WithSilence
+func (r *Registry) _muted(level llevel) bool {
+	return !r._active() && (level <= lDisabled || level > r._floor()) || atomic.LoadInt32(&r._silence) > 0
 }
 
-func (e *Elog) _logf(level llevel, format string, args ...interface{}) {
-	if !(logsActive && (level <= e.level || (_globalLevel > lDisabled && level <= _globalLevel))) {
+// _log emit a leveled record, calldepth is the depth of the caller to report relative to the caller of _log
+func (e *Elog) _log(calldepth int, level llevel, args ...interface{}) {
+	if e._dropCleared(_method(level)) {
+		return
+	}
+	e._checkCall(_method(level), args)
+	if !e._enabled(level) || !e._traceAllowed(calldepth+1, level) {
+		if c := _captureFor(level); c != nil {
+			e._capture(calldepth+1, c, level, fmt.Sprint(e._reg._renderArgs(args)...), nil)
+		}
+		e._debugDropped(level)
+		return
+	}
+	e._output(calldepth+1, level, _valid(level.String()), fmt.Sprint(e._reg._renderArgs(args)...))
+}
+
+// _logf emit a leveled formatted record, or with ELStructuredLog a record of the format and the key/value fields
+// of args, calldepth is the same as for _log
+func (e *Elog) _logf(calldepth int, level llevel, format string, args ...interface{}) {
+	if e._getFlags()&ELStructuredLog != 0 {
+		e._logPairs(calldepth+1, level, _method(level)+"f", format, args)
+		return
+	}
+	if e._dropCleared(_method(level) + "f") {
+		return
+	}
+	if !e._enabled(level) || !e._traceAllowed(calldepth+1, level) {
+		if c := _captureFor(level); c != nil {
+			e._capture(calldepth+1, c, level, fmt.Sprintf(format, e._reg._renderArgs(args)...), nil)
+		}
+		e._debugDropped(level)
 		return
 	}
+	e._output(calldepth+1, level, _valid(level.String()), fmt.Sprintf(format, e._reg._renderArgs(args)...))
+}
+
+// _output write a single record to the Elog output, calldepth has the same meaning as in log.Output,
+// an empty tag write the record without its tag (see Output)
+func (e *Elog) _output(calldepth int, level llevel, tag, msg string) error {
+	return e._emit(calldepth+1, level, tag, msg, nil)
+}
+
+// _emit write a single record with its fields to the Elog output, calldepth is the same as for _output
+func (e *Elog) _emit(calldepth int, level llevel, tag, msg string, fields []FieldT) error {
+	rec := e._record(calldepth+1, level, tag, msg, fields)
+	return e._emitRecord(calldepth+1, rec)
+}
+
+// _emitRecord write a built record to the Elog output, its capture and sinks, calldepth is the same as for _output
+func (e *Elog) _emitRecord(calldepth int, rec Record) error {
+	if e._getFlags()&ELSuppressRepeated != 0 {
+		summary, repeated := e._repeat.check(&rec, e._reg._suppression)
+		if repeated {
+			return nil
+		}
+		if summary != nil {
+			e._writeRecord(calldepth+1, *summary)
+		}
+	}
+	return e._writeRecord(calldepth+1, rec)
+}
+
+// _writeRecord write a record to the outputs, the ring buffer and the sinks of the Elog, calldepth is the same as for
+// _emitRecord
+func (e *Elog) _writeRecord(calldepth int, rec Record) error {
+	level := rec.level
+	e._prepare(&rec)
+	if len(e._reg._schemas) > 0 && len(rec.Fields) > 0 {
+		e._checkSchema(calldepth+1, &rec)
+	}
+	if ring := e._reg._ring; ring != nil {
+		ring.add(rec.Clone())
+	}
+	sinks := e._getSinks()
+	if b := e._reg._getBootstrap(); b != nil {
+		sinks = b.add(e, &rec)
+	}
+	e._stats.touch(rec.Time)
+	e._stats.count(level)
+	buf := e._format(&rec)
+	if c := _captureFor(level); c != nil {
+		c._write(buf)
+	}
+	e._debugEmitted(level)
+	err := e._write(level, buf)
+	if err != nil {
+		e._debugf("%s record write failed: %v", level, err)
+	}
+	for _, s := range sinks {
+		if serr := s.WriteRecord(&rec); serr != nil {
+			e._debugf("%s record sink %T failed: %v", level, s, serr)
+			e._stats.writeError()
+			if err == nil {
+				err = serr
+			}
+		}
+	}
+	return err
+}
+
+// _prepare apply the registry version field, rendering of the bytes fields, UTF-8 repair and key normalization to
+// a record
+func (e *Elog) _prepare(rec *Record) {
+	if rec.version == nil {
+		rec.version = e._reg._getVersionField()
+	}
+	if len(rec.Fields) > 0 {
+		rec.Fields = e._reg._renderBytesFields(rec.Fields)
+	}
+	if e._reg._utf8Repair {
+		_repairUTF8(rec)
+	}
+	if r := e._reg; (r._keyCase != KeyCaseAsIs || r._keyCollision != KeysKeepAll) && len(rec.Fields) > 0 {
+		r._normalizeRecord(rec, e._getFlags()&ELNestFields != 0)
+	}
+}
+
+// _record build the record of a call and run the hooks on it, calldepth is the same as for _output
+func (e *Elog) _record(calldepth int, level llevel, tag, msg string, fields []FieldT) Record {
+	rec := e._newRecord(calldepth+1, level, tag, msg, fields)
+	e._conf.RLock()
+	hooks := e._hooks
+	e._conf.RUnlock()
+	for _, h := range hooks {
+		h(&rec)
+	}
+	return rec
+}
+
+// _newRecord build the record of a call without running the hooks, calldepth is the same as for _output
+func (e *Elog) _newRecord(calldepth int, level llevel, tag, msg string, fields []FieldT) Record {
+	if n := len(e._fields); n > 0 {
+		fields = append(e._fields[:n:n], fields...)
+	}
+	bare := e._getCompat(_family(level)) != CompatElogging
+	if tag == "" {
+		tag, bare = _valid(level.String()), true
+	}
+	e._conf.RLock()
+	scope, labels := e.scope, e._labels
+	e._conf.RUnlock()
+	rec := Record{
+		Time:   time.Now(),
+		Tag:    tag,
+		Scope:  scope,
+		Msg:    msg,
+		Fields: fields,
+		Labels: labels,
+		level:  level,
+		bare:   bare,
+	}
+	if e._getFlags()&_callerFlags != 0 {
+		pc, file, line, ok := runtime.Caller(calldepth)
+		if ok {
+			rec.File, rec.Line, rec.Func = file, line, _funcName(pc)
+			if e._getFlags()&ELTrimPath != 0 {
+				rec.File = _trimPath(file, pc)
+			}
+		} else {
+			rec.File, rec.Line, rec.Func = "???", 0, "???"
+		}
+	}
+	if (level == lError || level == lFatal) && e._getFlags()&ELErrorFingerprint != 0 {
+		fn := rec.Func
+		if fn == "" {
+			fn = "???"
+			if pc, _, _, ok := runtime.Caller(calldepth); ok {
+				fn = _funcName(pc)
+			}
+		}
+		rec.Fields = append(rec.Fields[:len(rec.Fields):len(rec.Fields)], String(FingerprintKey, Fingerprint(fn, msg)))
+	}
+	return rec
+}
+
+// _write write a rendered record to the Elog output and to the level output, the record (multi-line
+// messages and fields included) is always written with a single Write call, serialized with the writes of
+// the other loggers sharing the writer (see SetAtomicWriter) so they never interleave partial records
+func (e *Elog) _write(level llevel, buf []byte) error {
+	e._mu.Lock()
+	defer e._mu.Unlock()
+	if e._out == nil { // cleared while the record was built
+		return nil
+	}
+	err := _lockedWrite(e._out, buf)
+	if err != nil {
+		e._stats.writeError()
+	}
+	if e._levelOut != nil && level != lPrint && level <= e._levelOutLevel {
+		if lerr := _lockedWrite(e._levelOut, buf); lerr != nil {
+			e._stats.writeError()
+			if err == nil {
+				err = lerr
+			}
+		}
+	}
+	return err
+}
 
-	header := " (" + _valid(level.String()) + ") "
-	if format == "" {
-		e._log.Output(3, header+fmt.Sprint(args...))
-	} else {
-		e._log.Output(3, header+fmt.Sprintf(format, args...))
+// _writeRecord write a whole rendered record to w, a short write without error is reported as io.ErrShortWrite
+func _writeRecord(w io.Writer, buf []byte) error {
+	n, err := w.Write(buf)
+	if err == nil && n < len(buf) {
+		err = io.ErrShortWrite
 	}
+	return err
 }
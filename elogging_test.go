@@ -2,9 +2,15 @@ package elogging
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
 	"os"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestCreate(t *testing.T) {
@@ -51,3 +57,242 @@ func TestLogToBuf(t *testing.T) {
 		t.Error("expcted trace level message")
 	}
 }
+
+func TestLevelOutput(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	elog := NewElogWithLevelOutput("TestLevelOutput", "info", out, errOut, "warning")
+	defer elog.Clear()
+	elog.Error("error message")
+	elog.Warn("warning message")
+	elog.Info("info message")
+	elog.Print("print message")
+
+	if strings.Count(out.String(), "\n") != 4 {
+		t.Errorf("expected all the records on the main output, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "error message") || !strings.Contains(errOut.String(), "warning message") ||
+		strings.Contains(errOut.String(), "info message") || strings.Contains(errOut.String(), "print message") {
+		t.Errorf("unexpected records on the level output %q", errOut.String())
+	}
+}
+
+func TestPushPopLevel(t *testing.T) {
+	elog := NewElog("TestPushPopLevel", "warning", nil)
+	defer elog.Clear()
+	elog.PushLevel("verbose")
+	elog.PushLevel("trace")
+	elog.SetLevel("error")
+	elog.PopLevel()
+	if elog.GetLevel() != "Verbose" {
+		t.Errorf("expected verbose after first pop, got %s", elog.GetLevel())
+	}
+	elog.PopLevel()
+	elog.PopLevel()
+	if elog.GetLevel() != "Warning" {
+		t.Errorf("expected warning after last pop, got %s", elog.GetLevel())
+	}
+}
+
+// writesRecorder record every Write call
+type writesRecorder struct {
+	mu     sync.Mutex
+	writes []string
+}
+
+func (w *writesRecorder) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestWriteCoalescing(t *testing.T) {
+	w := &writesRecorder{}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		elog := NewElog("TestWriteCoalescing", "info", w)
+		defer elog.Clear()
+		elog.SetFlags(log.Lmsgprefix | ELJSONLog*(i%2))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				elog.InfoKV("first line\nsecond line", Err("err", testMultiError{errors.New("a"), errors.New("b")}))
+			}
+		}()
+	}
+	wg.Wait()
+	if len(w.writes) != 200 {
+		t.Fatalf("expected 200 writes, got %d", len(w.writes))
+	}
+	for _, s := range w.writes {
+		if strings.HasPrefix(s, "{") {
+			var v map[string]interface{}
+			if err := json.Unmarshal([]byte(s), &v); err != nil {
+				t.Fatalf("partial json record %q: %v", s, err)
+			}
+		} else if !strings.HasPrefix(s, "TestWriteCoalescing (INFO) first line\nsecond line err=2 errors:") || !strings.HasSuffix(s, "- b\n") {
+			t.Fatalf("partial text record %q", s)
+		}
+	}
+}
+
+type shortWriter struct{}
+
+func (shortWriter) Write(p []byte) (int, error) { return len(p) / 2, nil }
+
+func TestShortWrite(t *testing.T) {
+	elog := NewElog("TestShortWrite", "info", shortWriter{})
+	defer elog.Clear()
+	if err := elog._output(1, lInfo, "INFO", "msg"); err != io.ErrShortWrite {
+		t.Errorf("expected short write error, got %v", err)
+	}
+}
+
+func TestGlobalLevelMode(t *testing.T) {
+	r := NewRegistry()
+	quiet := r.NewElog("TestGlobalLevelMode.quiet", "error", io.Discard)
+	loud := r.NewElog("TestGlobalLevelMode.loud", "trace", io.Discard)
+	r.SetGlobalLogLevel("info")
+	for _, c := range []struct {
+		mode        GlobalLevelMode
+		quiet, loud string // expected enabled levels: e(rror) i(nfo) t(race)
+	}{
+		{GlobalFloor, "ei", "eit"},
+		{GlobalCeiling, "e", "ei"},
+		{GlobalOverride, "ei", "ei"},
+	} {
+		r.SetGlobalLevelMode(c.mode)
+		for _, x := range []struct {
+			e        *Elog
+			expected string
+		}{{quiet, c.quiet}, {loud, c.loud}} {
+			got := ""
+			for _, l := range []llevel{lError, lInfo, lTrace} {
+				if x.e._enabled(l) {
+					got += strings.ToLower(l.String()[:1])
+				}
+			}
+			if got != x.expected {
+				t.Errorf("%s mode, %s: expected %q, got %q", c.mode, x.e.scope, x.expected, got)
+			}
+		}
+	}
+	r.SetGlobalLogLevel("disabled")
+	if !loud._enabled(lTrace) || quiet._enabled(lInfo) {
+		t.Error("unset global level should leave the scope levels")
+	}
+	if m, err := ParseGlobalLevelMode("Ceiling"); m != GlobalCeiling || err != nil {
+		t.Errorf("unexpected mode %s, %v", m, err)
+	}
+	if _, err := ParseGlobalLevelMode("max"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestOutput(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestOutput", "info", b)
+	elog.SetFlags(log.Lshortfile)
+	output := func(level, msg string) error { return elog.Output(2, level, msg) }
+	if err := output("warning", "framework message"); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "TestOutputelogging_test.go:198: framework message\n"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+	b.Reset()
+	if err := output("verbose", "filtered"); err != nil || b.Len() > 0 {
+		t.Errorf("verbose record not filtered: %v %q", err, b.String())
+	}
+	if err := output("loud", "msg"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestWriteRaw(t *testing.T) {
+	b, levelOut := &bytes.Buffer{}, &bytes.Buffer{}
+	elog := NewEphemeralElog("TestWriteRaw", "info", b)
+	elog.ModifyLevelOutput(levelOut, "error")
+	if err := elog.WriteRaw("error", []byte("2021/01/01 proxy (ERROR) failed")); err != nil {
+		t.Fatal(err)
+	}
+	elog.WriteRaw("info", []byte("{\"msg\":\"replayed\"}\n"))
+	elog.WriteRaw("trace", []byte("filtered\n"))
+	if expected := "2021/01/01 proxy (ERROR) failed\n{\"msg\":\"replayed\"}\n"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+	if expected := "2021/01/01 proxy (ERROR) failed\n"; levelOut.String() != expected {
+		t.Errorf("expected %q on the level output, got %q", expected, levelOut.String())
+	}
+	if err := elog.WriteRaw("loud", nil); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestWriteRawPipeline(t *testing.T) {
+	r := NewRegistry()
+	r.EnableRingBuffer(10)
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestWriteRawPipeline", "info", b)
+	elog.SetFlags(ELSuppressRepeated | ELJSONLog)
+	for i := 0; i < 3; i++ {
+		elog.WriteRaw("warning", []byte("proxied line\n"))
+	}
+	elog.Info("next")
+	elog.Clear()
+	elog.WriteRaw("error", []byte("after clear"))
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 3 || lines[0] != "proxied line" || !strings.Contains(lines[1], "repeated 2 times") ||
+		!strings.Contains(lines[2], `"msg":"next"`) {
+		t.Errorf("unexpected output %q", lines)
+	}
+	if recent := r.Query("TestWriteRawPipeline", "", time.Time{}, "proxied"); len(recent) != 2 || recent[0].Msg != "proxied line" {
+		t.Errorf("unexpected ring buffer records %+v", recent)
+	}
+}
+
+func TestLogsOffExcept(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestLogsOffExcept", "info", b)
+	elog.SetFlags(0)
+	if err := r.LogsOffExcept("error"); err != nil {
+		t.Fatal(err)
+	}
+	elog.Info("muted")
+	elog.Print("muted")
+	elog.Error("kept")
+	if s := r.Snapshot(); s.LogsActive || s.LogsOffFloor != "Error" {
+		t.Errorf("unexpected snapshot %v %q", s.LogsActive, s.LogsOffFloor)
+	}
+	r.LogsOff()
+	elog.Error("muted")
+	r.LogsOn()
+	elog.Info("resumed")
+	if expected := "TestLogsOffExcept (ERROR) kept\nTestLogsOffExcept (INFO) resumed\n"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+	if err := r.LogsOffExcept("loud"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestCycleLevel(t *testing.T) {
+	elog := NewRegistry().NewElog("TestCycleLevel", "disabled", io.Discard)
+	elog.CycleLevelDown()
+	if elog._scopeLevel() != lTrace {
+		t.Errorf("expected trace below disabled, got %s", elog._scopeLevel())
+	}
+	elog.CycleLevelUp()
+	if elog._scopeLevel() != lDisabled {
+		t.Errorf("expected disabled above trace, got %s", elog._scopeLevel())
+	}
+	elog.CycleLevelUp()
+	elog.CycleLevelUp()
+	elog.CycleLevelDown()
+	if elog._scopeLevel() != lError {
+		t.Errorf("expected error, got %s", elog._scopeLevel())
+	}
+}
@@ -0,0 +1,149 @@
+package elogging
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Encoder render a record, appending it (newline terminated) to buf
+type Encoder interface {
+	Encode(buf []byte, rec *Record) []byte
+}
+
+// EncoderFunc adapt a function to the Encoder interface
+type EncoderFunc func(buf []byte, rec *Record) []byte
+
+// Encode call f
+func (f EncoderFunc) Encode(buf []byte, rec *Record) []byte {
+	return f(buf, rec)
+}
+
+// FlagsEncoder return an encoder rendering records the way an Elog with the given flags does (text, color or JSON),
+// caller information is only available in records of Elogs having a caller flag set
+func FlagsEncoder(flags int) Encoder {
+	return EncoderFunc(func(buf []byte, rec *Record) []byte {
+		return _encodeFlags(buf, flags, rec)
+	})
+}
+
+// DefaultSIEMFieldMap is the default mapping of record field keys to CEF extension keys
+var DefaultSIEMFieldMap = map[string]string{
+	"src_ip":   "src",
+	"src_port": "spt",
+	"dst_ip":   "dst",
+	"dst_port": "dpt",
+	"user":     "suser",
+	"host":     "dhost",
+	"method":   "requestMethod",
+	"url":      "request",
+	"status":   "outcome",
+	"proto":    "proto",
+	"action":   "act",
+}
+
+// CEFEncoder render records in ArcSight Common Event Format:
+//  CEF:0|Vendor|Product|Version|<tag>|<msg>|<severity>|rt=<ms> cs1Label=scope cs1=<scope> ... <fields>
+// record fields are renamed according to FieldMap (DefaultSIEMFieldMap when nil), unmapped keys are kept as is,
+// the severity is read from Severity (SIEMSeverity when nil)
+type CEFEncoder struct {
+	Vendor   string
+	Product  string
+	Version  string
+	FieldMap map[string]string
+	Severity *SeverityMap
+}
+
+var (
+	_cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	_cefValueEscaper  = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
+
+// Encode render the record as a CEF line
+func (c *CEFEncoder) Encode(buf []byte, rec *Record) []byte {
+	fieldMap := c.FieldMap
+	if fieldMap == nil {
+		fieldMap = DefaultSIEMFieldMap
+	}
+	buf = append(buf, "CEF:0|"...)
+	for _, h := range []string{c.Vendor, c.Product, c.Version, rec.Tag, strings.TrimSuffix(rec.Msg, "\n")} {
+		buf = append(buf, _cefHeaderEscaper.Replace(h)...)
+		buf = append(buf, '|')
+	}
+	buf = strconv.AppendInt(buf, int64(_severity(c.Severity, &SIEMSeverity)._of(rec.level)), 10)
+	buf = append(buf, "|rt="...)
+	buf = strconv.AppendInt(buf, rec.Time.UnixNano()/1e6, 10)
+	buf = append(buf, " cs1Label=scope cs1="...)
+	buf = append(buf, _cefValueEscaper.Replace(rec.Scope)...)
+	if rec.File != "" {
+		buf = append(buf, " fname="...)
+		buf = append(buf, _cefValueEscaper.Replace(rec.File)...)
+		buf = append(buf, " cn1Label=line cn1="...)
+		buf = strconv.AppendInt(buf, int64(rec.Line), 10)
+	}
+	for _, fields := range [][]FieldT{rec.Labels, _flattenFields(rec.Fields)} {
+		for i := range fields {
+			key := fields[i].Key
+			if mapped, ok := fieldMap[key]; ok {
+				key = mapped
+			}
+			buf = append(buf, ' ')
+			buf = append(buf, key...)
+			buf = append(buf, '=')
+			buf = append(buf, _cefValueEscaper.Replace(string(_appendValueRaw(nil, &fields[i])))...)
+		}
+	}
+	return append(buf, '\n')
+}
+
+// LEEFEncoder render records in IBM QRadar Log Event Extended Format 1.0:
+//  LEEF:1.0|Vendor|Product|Version|<tag>|devTime=<ms>	sev=<severity>	cat=<scope>	msg=<msg> ... <fields>
+// record fields are renamed according to FieldMap (no renaming when nil), the severity is read from Severity
+// (SIEMSeverity when nil)
+type LEEFEncoder struct {
+	Vendor   string
+	Product  string
+	Version  string
+	FieldMap map[string]string
+	Severity *SeverityMap
+}
+
+var (
+	_leefHeaderEscaper = strings.NewReplacer(`|`, `\|`, "\n", " ", "\r", " ")
+	_leefValueEscaper  = strings.NewReplacer("\t", " ", "\n", `\n`, "\r", `\r`)
+)
+
+// Encode render the record as a LEEF line
+func (l *LEEFEncoder) Encode(buf []byte, rec *Record) []byte {
+	buf = append(buf, "LEEF:1.0|"...)
+	for _, h := range []string{l.Vendor, l.Product, l.Version, rec.Tag} {
+		buf = append(buf, _leefHeaderEscaper.Replace(h)...)
+		buf = append(buf, '|')
+	}
+	buf = append(buf, "devTimeFormat=epoch\tdevTime="...)
+	buf = strconv.AppendInt(buf, rec.Time.UnixNano()/1e6, 10)
+	buf = append(buf, "\tsev="...)
+	buf = strconv.AppendInt(buf, int64(_severity(l.Severity, &SIEMSeverity)._of(rec.level)), 10)
+	buf = append(buf, "\tcat="...)
+	buf = append(buf, _leefValueEscaper.Replace(rec.Scope)...)
+	buf = append(buf, "\tmsg="...)
+	buf = append(buf, _leefValueEscaper.Replace(strings.TrimSuffix(rec.Msg, "\n"))...)
+	if rec.File != "" {
+		buf = append(buf, "\tfile="...)
+		buf = append(buf, _leefValueEscaper.Replace(rec.File)...)
+		buf = append(buf, "\tline="...)
+		buf = strconv.AppendInt(buf, int64(rec.Line), 10)
+	}
+	for _, fields := range [][]FieldT{rec.Labels, _flattenFields(rec.Fields)} {
+		for i := range fields {
+			key := fields[i].Key
+			if mapped, ok := l.FieldMap[key]; ok {
+				key = mapped
+			}
+			buf = append(buf, '\t')
+			buf = append(buf, key...)
+			buf = append(buf, '=')
+			buf = append(buf, _leefValueEscaper.Replace(string(_appendValueRaw(nil, &fields[i])))...)
+		}
+	}
+	return append(buf, '\n')
+}
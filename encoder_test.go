@@ -0,0 +1,32 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSIEMEncoders(t *testing.T) {
+	cef, leef, warn := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	elog := NewElog("TestSIEM", "info", &bytes.Buffer{})
+	defer elog.Clear()
+	elog.AddSink(NewWriterSink(cef, &CEFEncoder{Vendor: "acme", Product: "gw|x", Version: "1.0"}, ""))
+	elog.AddSink(NewWriterSink(leef, &LEEFEncoder{Vendor: "acme", Product: "gw", Version: "1.0"}, ""))
+	elog.AddSink(NewWriterSink(warn, nil, "warning"))
+
+	elog.ErrorKV("login failed", String("user", "bob"), String("src_ip", "10.0.0.1"), String("note", "a=b"))
+	elog.Info("informational")
+
+	lines := strings.Split(strings.TrimSpace(cef.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], `CEF:0|acme|gw\|x|1.0|ERROR|login failed|7|rt=`) ||
+		!strings.Contains(lines[0], "cs1=TestSIEM") || !strings.Contains(lines[0], " suser=bob src=10.0.0.1 note=a\\=b") {
+		t.Errorf("unexpected cef output %q", cef.String())
+	}
+	if !strings.HasPrefix(leef.String(), "LEEF:1.0|acme|gw|1.0|ERROR|devTimeFormat=epoch\tdevTime=") ||
+		!strings.Contains(leef.String(), "\tsev=7\tcat=TestSIEM\tmsg=login failed") || !strings.Contains(leef.String(), "\tuser=bob") {
+		t.Errorf("unexpected leef output %q", leef.String())
+	}
+	if strings.Count(warn.String(), "\n") != 1 {
+		t.Errorf("expected only the error record in the warning sink, got %q", warn.String())
+	}
+}
@@ -0,0 +1,38 @@
+package elogging
+
+import "fmt"
+
+// CheckErr log msg and the error at error level when err is not nil and report whether it was, e.g.
+//  if e.CheckErr(err, "opening config") {
+//  	return
+//  }
+func (e *Elog) CheckErr(err error, msg string) bool {
+	if err == nil {
+		return false
+	}
+	e._log(2, lError, msg, ": ", err)
+	return true
+}
+
+// WrapErr log msg and the error at error level and return the error wrapped with msg ("msg: err"),
+// a nil err is returned as is without logging, e.g. return e.WrapErr(err, "opening config")
+func (e *Elog) WrapErr(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	e._log(2, lError, msg, ": ", err)
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// LogIfError log msg and the error at error level when *errp is not nil, meant to be deferred with a named
+// return error, the record carries the caller info of the function returning the error:
+//  func save() (err error) {
+//  	defer e.LogIfError(&err, "saving snapshot")
+//  	...
+//  }
+func (e *Elog) LogIfError(errp *error, msg string) {
+	if errp == nil || *errp == nil {
+		return
+	}
+	e._log(2, lError, msg, ": ", *errp)
+}
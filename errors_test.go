@@ -0,0 +1,50 @@
+package elogging
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestCheckAndWrapErr(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestErr", "info", b)
+	elog.SetFlags(0)
+
+	if elog.CheckErr(nil, "nothing") || !elog.CheckErr(io.EOF, "reading") {
+		t.Error("unexpected CheckErr result")
+	}
+	if elog.WrapErr(nil, "nothing") != nil {
+		t.Error("expected a nil error")
+	}
+	err := elog.WrapErr(io.ErrUnexpectedEOF, "parsing")
+	if !errors.Is(err, io.ErrUnexpectedEOF) || err.Error() != "parsing: unexpected EOF" {
+		t.Errorf("unexpected wrapped error %v", err)
+	}
+	if b.String() != "TestErr (ERROR) reading: EOF\nTestErr (ERROR) parsing: unexpected EOF\n" {
+		t.Errorf("unexpected output %q", b.String())
+	}
+}
+
+func TestLogIfError(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestLogIfError", "info", b)
+	elog.SetFlags(log.Lshortfile)
+
+	save := func(fail bool) (err error) {
+		defer elog.LogIfError(&err, "saving snapshot")
+		if fail {
+			return io.ErrShortWrite
+		}
+		return nil
+	}
+	save(false)
+	save(true)
+	if !strings.HasPrefix(b.String(), "TestLogIfErrorerrors_test.go:") ||
+		!strings.HasSuffix(b.String(), "(ERROR) saving snapshot: short write\n") || strings.Count(b.String(), "\n") != 1 {
+		t.Errorf("unexpected output %q", b.String())
+	}
+}
@@ -0,0 +1,105 @@
+package elogging
+
+import (
+	"sync"
+	"time"
+)
+
+// Periodic is a periodic status task started by Every, stopped by Stop or by Shutdown
+type Periodic struct {
+	e     *Elog
+	level llevel
+	fn    func(e *Elog)
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+// _periodicSet is the set of the running periodic tasks of a registry
+type _periodicSet struct {
+	mu    sync.Mutex
+	tasks map[*Periodic]bool
+}
+
+// Every call fn with the Elog every d while records at level are enabled for it, see the package Every
+func (e *Elog) Every(d time.Duration, level string, fn func(e *Elog)) *Periodic {
+	if d <= 0 {
+		panic("elogging: non-positive interval for Every")
+	}
+	p := &Periodic{e: e, level: _value(_valid(level)), fn: fn, stop: make(chan struct{}), done: make(chan struct{})}
+	set := &e._reg._periodic
+	set.mu.Lock()
+	if set.tasks == nil {
+		set.tasks = map[*Periodic]bool{}
+	}
+	set.tasks[p] = true
+	set.mu.Unlock()
+	go p._run(d)
+	return p
+}
+
+// Every call fn with the package default log every d, for heartbeat and status lines, e.g.
+//  elogging.Every(time.Minute, "info", func(e *elogging.Elog) { e.InfoKV("status", elogging.Int("conns", pool.Len())) })
+// fn is skipped while records at level are not enabled so the status is not computed for nothing, a panic of fn
+// is reported on stderr and the task keeps running. The task runs until Stop or Shutdown, use Elog.Every
+// for status lines of a scope.
+func Every(d time.Duration, level string, fn func(e *Elog)) *Periodic {
+	return _defaultRegistry._stdLog.Every(d, level, fn)
+}
+
+// Stop stop the task, a call of fn in progress is not waited for (see Shutdown)
+func (p *Periodic) Stop() {
+	p.once.Do(func() {
+		close(p.stop)
+		set := &p.e._reg._periodic
+		set.mu.Lock()
+		delete(set.tasks, p)
+		set.mu.Unlock()
+	})
+}
+
+// Done return a channel closed once the task stopped and its last call of fn returned
+func (p *Periodic) Done() <-chan struct{} {
+	return p.done
+}
+
+// _run call fn every d until the task is stopped
+func (p *Periodic) _run(d time.Duration) {
+	defer close(p.done)
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if p.e._enabled(p.level) {
+				p._call()
+			}
+		}
+	}
+}
+
+// _call call fn, a panic is reported instead of crashing the process
+func (p *Periodic) _call() {
+	defer func() {
+		if err := recover(); err != nil {
+			_internalf("periodic task of %s panicked: %v", p.e._scope(), err)
+		}
+	}()
+	p.fn(p.e)
+}
+
+// _stopAll stop every task of the set and return them
+func (set *_periodicSet) _stopAll() []*Periodic {
+	set.mu.Lock()
+	tasks := make([]*Periodic, 0, len(set.tasks))
+	for p := range set.tasks {
+		tasks = append(tasks, p)
+	}
+	set.mu.Unlock()
+	for _, p := range tasks {
+		p.Stop()
+	}
+	return tasks
+}
@@ -0,0 +1,53 @@
+package elogging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvery(t *testing.T) {
+	r := NewRegistry()
+	b := &writesRecorder{}
+	elog := r.NewElog("TestEvery", "info", b)
+	elog.SetFlags(0)
+	var calls, skipped int32
+	p := elog.Every(time.Millisecond, "info", func(e *Elog) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			panic("first call")
+		}
+		e.Info("alive")
+	})
+	quiet := elog.Every(time.Millisecond, "trace", func(e *Elog) { atomic.AddInt32(&skipped, 1) })
+	for atomic.LoadInt32(&calls) < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	p.Stop()
+	<-p.Done()
+	if out := strings.Join(b.writes, ""); !strings.Contains(out, "TestEvery (INFO) alive") {
+		t.Errorf("unexpected output %q", out)
+	}
+	if atomic.LoadInt32(&skipped) != 0 {
+		t.Error("task called while its level is not enabled")
+	}
+	if err := r.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-quiet.Done():
+	default:
+		t.Error("task still running after Shutdown")
+	}
+}
+
+func TestEveryInterval(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a zero interval")
+		}
+	}()
+	NewRegistry().NewElog("TestEveryInterval", "info", &bytes.Buffer{}).Every(0, "info", func(*Elog) {})
+}
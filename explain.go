@@ -0,0 +1,96 @@
+package elogging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explain describe whether a record at level (error, warning, info, verbose or trace) would be emitted by the Elog
+// right now and why, gate by gate: logs on/off, compat mode, scope level, global level, trace filter and capture,
+// followed by the destinations and the format of the record. Use SetDebug to follow the actual records.
+func (e *Elog) Explain(level string) string {
+	l, err := _parseLevel(level)
+	if err != nil {
+		return err.Error()
+	}
+	if l == lDisabled {
+		return fmt.Sprintf("elogging: %q is not a record level", level)
+	}
+	r := e._reg
+	var gates []string
+	verdict := "emitted"
+	if r._active() {
+		gates = append(gates, "logs are on")
+	} else if !r._muted(l) {
+		gates = append(gates, fmt.Sprintf("logs are off except at or above %s (LogsOffExcept): %s is not muted", r._floor(), l))
+	} else {
+		gates = append(gates, "logs are off (LogsOff): no record is emitted")
+		verdict = "not emitted"
+	}
+	if e._getCompat(FamilyLeveled) == CompatStdlib {
+		gates = append(gates, "compat mode stdlib: leveled records are not filtered by level")
+	} else {
+		scopeOK := l <= e._level()
+		scopeGate := fmt.Sprintf("scope level %s: %s is %s", e._scopeLevel(), l, _within(scopeOK))
+		if o := e._overrideLevel(); o != _noOverride {
+			scopeGate = fmt.Sprintf("scope level %s overridden by the override table to %s: %s is %s", e._scopeLevel(), o, l, _within(scopeOK))
+		}
+		passed := scopeOK
+		if g := r._getGlobalLevel(); g == lDisabled {
+			gates = append(gates, scopeGate, "global level not set")
+		} else {
+			globalOK := l <= g
+			globalGate := fmt.Sprintf("global level %s: %s is %s", g, l, _within(globalOK))
+			switch r._getGlobalMode() {
+			case GlobalCeiling:
+				gates = append(gates, scopeGate, globalGate+", ceiling mode: a record must pass both levels")
+				passed = scopeOK && globalOK
+			case GlobalOverride:
+				gates = append(gates, fmt.Sprintf("scope level %s: ignored", e._scopeLevel()), globalGate+", override mode: the global level replaces the scope levels")
+				passed = globalOK
+			default:
+				gates = append(gates, scopeGate, globalGate+", floor mode: a record passes either level")
+				passed = scopeOK || globalOK
+			}
+		}
+		if !passed {
+			verdict = "not emitted"
+		}
+	}
+	if filter := r._traceFilter; l == lTrace && len(filter) > 0 {
+		gates = append(gates, fmt.Sprintf("trace filter: only emitted from call sites matching %s", strings.Join(filter, ", ")))
+		if verdict == "emitted" {
+			verdict = "emitted depending on the call site"
+		}
+	}
+	if verdict != "emitted" {
+		if c := _captureFor(l); c != nil {
+			gates = append(gates, "the capture of the current goroutine keeps the record")
+		}
+	}
+	dest := fmt.Sprintf("destination: %s, format %s", _describeOutput(e._getOutput()), _formatName(e._getFlags()))
+	if levelOut, min := e._getLevelOutput(); levelOut != nil && l <= min {
+		dest += ", level output " + _describeOutput(levelOut)
+	}
+	if n := len(e._getSinks()); n > 0 {
+		dest += fmt.Sprintf(", %d sinks (filtered by their own minimum level)", n)
+	}
+	gates = append(gates, dest)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s record on %q: %s\n", l, e._scope(), verdict)
+	for _, g := range gates {
+		b.WriteString("  - ")
+		b.WriteString(g)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// _within describe a level check
+func _within(ok bool) string {
+	if ok {
+		return "at or below it"
+	}
+	return "above it"
+}
@@ -0,0 +1,35 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	r := NewRegistry()
+	elog := r.NewElog("TestExplain", "info", &bytes.Buffer{})
+
+	for _, c := range []struct {
+		setup    func()
+		level    string
+		expected []string
+	}{
+		{func() {}, "info", []string{"Info record on \"TestExplain\": emitted\n", "scope level Info: Info is at or below it", "global level not set", "destination: *bytes.Buffer, format text"}},
+		{func() {}, "trace", []string{": not emitted\n", "scope level Info: Trace is above it"}},
+		{func() { r.SetGlobalLogLevel("trace") }, "trace", []string{": emitted\n", "global level Trace: Trace is at or below it"}},
+		{func() { r.SetTraceFilter("*/db/*.go") }, "trace", []string{": emitted depending on the call site\n", "call sites matching */db/*.go"}},
+		{func() { r.LogsOff() }, "error", []string{": not emitted\n", "logs are off"}},
+	} {
+		c.setup()
+		got := elog.Explain(c.level)
+		for _, s := range c.expected {
+			if !strings.Contains(got, s) {
+				t.Errorf("missing %q in %q", s, got)
+			}
+		}
+	}
+	if got := elog.Explain("loud"); !strings.Contains(got, "unknown level") {
+		t.Errorf("unexpected explanation %q", got)
+	}
+}
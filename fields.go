@@ -0,0 +1,502 @@
+package elogging
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+type fieldKind uint8
+
+const (
+	kindAny fieldKind = iota
+	kindString
+	kindInt
+	kindUint
+	kindFloat
+	kindBool
+	kindDuration
+	kindTime
+	kindError
+	kindGroup
+	kindJSON // a value rendered as JSON, see CollectionsJSON
+)
+
+// FieldT is a typed key/value pair attached to a record, common types are stored without boxing
+type FieldT struct {
+	Key  string
+	kind fieldKind
+	num  uint64
+	str  string
+	any  interface{}
+}
+
+// Field create a field for any value type, the common types (strings, integers, floats, booleans,
+// durations, times and errors) are stored and rendered according to their type
+func Field[T any](key string, v T) FieldT {
+	switch x := any(v).(type) {
+	case string:
+		return String(key, x)
+	case int:
+		return Int64(key, int64(x))
+	case int8:
+		return Int64(key, int64(x))
+	case int16:
+		return Int64(key, int64(x))
+	case int32:
+		return Int64(key, int64(x))
+	case int64:
+		return Int64(key, x)
+	case uint:
+		return Uint64(key, uint64(x))
+	case uint8:
+		return Uint64(key, uint64(x))
+	case uint16:
+		return Uint64(key, uint64(x))
+	case uint32:
+		return Uint64(key, uint64(x))
+	case uint64:
+		return Uint64(key, x)
+	case float32:
+		return Float64(key, float64(x))
+	case float64:
+		return Float64(key, x)
+	case bool:
+		return Bool(key, x)
+	case time.Duration:
+		return Duration(key, x)
+	case time.Time:
+		return Time(key, x)
+	case error:
+		return Err(key, x)
+	}
+	return Any(key, v)
+}
+
+// MapFields expand a map into fields (see Field) sorted by key, so records built from a map render the same way
+// on every call; the fields given to the KV methods are otherwise rendered in the order given by every encoder
+func MapFields[V any](m map[string]V) []FieldT {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]FieldT, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, Field(k, m[k]))
+	}
+	return fields
+}
+
+// _missingValue is the value of a key/value key given without value
+const _missingValue = "(MISSING)"
+
+// KVFields turn alternating key/value arguments into fields (see Field), as taken by the f calls of the Elogs with
+// ELStructuredLog, e.g. KVFields("user", "bob", "attempts", 3) render as user=bob attempts=3. Keys that are not
+// strings are rendered with %v, a FieldT argument is taken as is and a trailing key without value get (MISSING).
+// The printf checkers may report the f calls given key/value pairs (no formatting directives), the KVf methods
+// (InfoKVf, ...) take the pairs without a format.
+func KVFields(args ...interface{}) []FieldT {
+	fields := make([]FieldT, 0, (len(args)+1)/2)
+	for i := 0; i < len(args); i++ {
+		if f, ok := args[i].(FieldT); ok {
+			fields = append(fields, f)
+			continue
+		}
+		key, ok := args[i].(string)
+		if !ok {
+			key = fmt.Sprint(args[i])
+		}
+		if i+1 == len(args) {
+			fields = append(fields, String(key, _missingValue))
+			break
+		}
+		i++
+		fields = append(fields, Field(key, args[i]))
+	}
+	return fields
+}
+
+// String create a string field
+func String(key, v string) FieldT {
+	return FieldT{Key: key, kind: kindString, str: v}
+}
+
+// Int create an integer field
+func Int(key string, v int) FieldT {
+	return Int64(key, int64(v))
+}
+
+// Int64 create an integer field
+func Int64(key string, v int64) FieldT {
+	return FieldT{Key: key, kind: kindInt, num: uint64(v)}
+}
+
+// Uint64 create an unsigned integer field
+func Uint64(key string, v uint64) FieldT {
+	return FieldT{Key: key, kind: kindUint, num: v}
+}
+
+// Float64 create a floating point field
+func Float64(key string, v float64) FieldT {
+	return FieldT{Key: key, kind: kindFloat, num: math.Float64bits(v)}
+}
+
+// Bool create a boolean field
+func Bool(key string, v bool) FieldT {
+	f := FieldT{Key: key, kind: kindBool}
+	if v {
+		f.num = 1
+	}
+	return f
+}
+
+// Duration create a duration field
+func Duration(key string, v time.Duration) FieldT {
+	return FieldT{Key: key, kind: kindDuration, num: uint64(v)}
+}
+
+// Time create a time field, rendered as RFC3339 with nanoseconds
+func Time(key string, v time.Time) FieldT {
+	return FieldT{Key: key, kind: kindTime, any: v}
+}
+
+// Err create an error field, a nil error is rendered as null
+func Err(key string, v error) FieldT {
+	return FieldT{Key: key, kind: kindError, any: v}
+}
+
+// Any create a field for a value of any type, rendered with %v
+func Any(key string, v interface{}) FieldT {
+	return FieldT{Key: key, kind: kindAny, any: v}
+}
+
+// Group create a field grouping other fields under a key, rendered as a nested object in JSON
+// and as dotted keys in text (http.method=GET http.status=200), groups may be nested
+func Group(key string, fields ...FieldT) FieldT {
+	return FieldT{Key: key, kind: kindGroup, any: append([]FieldT(nil), fields...)}
+}
+
+// Value return the value of the field as an interface, the fields of a group as a []FieldT
+func (f FieldT) Value() interface{} {
+	switch f.kind {
+	case kindString:
+		return f.str
+	case kindInt:
+		return int64(f.num)
+	case kindUint:
+		return f.num
+	case kindFloat:
+		return math.Float64frombits(f.num)
+	case kindBool:
+		return f.num == 1
+	case kindDuration:
+		return time.Duration(f.num)
+	}
+	return f.any
+}
+
+// _appendValueRaw append the plain rendering of the field value, without any quoting
+func _appendValueRaw(buf []byte, f *FieldT) []byte {
+	if s, ok := f._redacted(); ok {
+		return append(buf, s...)
+	}
+	switch f.kind {
+	case kindString:
+		return append(buf, f.str...)
+	case kindInt:
+		return strconv.AppendInt(buf, int64(f.num), 10)
+	case kindUint:
+		return strconv.AppendUint(buf, f.num, 10)
+	case kindFloat:
+		return strconv.AppendFloat(buf, math.Float64frombits(f.num), 'g', -1, 64)
+	case kindBool:
+		return strconv.AppendBool(buf, f.num == 1)
+	case kindDuration:
+		return append(buf, time.Duration(f.num).String()...)
+	case kindTime:
+		return f.any.(time.Time).AppendFormat(buf, time.RFC3339Nano)
+	case kindError:
+		if f.any == nil {
+			return append(buf, "<nil>"...)
+		}
+		if errs := _errorList(f.any.(error)); errs != nil {
+			for i, err := range errs {
+				if i > 0 {
+					buf = append(buf, "; "...)
+				}
+				buf = append(buf, err.Error()...)
+			}
+			return buf
+		}
+		return append(buf, f.any.(error).Error()...)
+	case kindGroup:
+		return _appendGroupJSON(buf, f.any.([]FieldT))
+	case kindJSON:
+		return append(buf, f.str...)
+	}
+	return append(buf, fmt.Sprint(f.any)...)
+}
+
+// _appendValueText append the text rendering of the field value, strings are quoted when needed
+func _appendValueText(buf []byte, f *FieldT) []byte {
+	if s, ok := f._redacted(); ok {
+		return _appendTextString(buf, s)
+	}
+	switch f.kind {
+	case kindString:
+		return _appendTextString(buf, f.str)
+	case kindError:
+		if f.any != nil {
+			if errs := _errorList(f.any.(error)); errs != nil {
+				return _appendErrorLines(buf, errs)
+			}
+			return _appendTextString(buf, f.any.(error).Error())
+		}
+	case kindAny:
+		return _appendTextString(buf, fmt.Sprint(f.any))
+	case kindJSON:
+		return _appendTextString(buf, f.str)
+	}
+	return _appendValueRaw(buf, f)
+}
+
+// _appendFieldText append the field as key=value, values with spaces or quotes are quoted,
+// a group as the key=value pairs of its fields with dotted keys
+func _appendFieldText(buf []byte, f *FieldT) []byte {
+	if members, _ := f.any.([]FieldT); f.kind == kindGroup && len(members) > 0 {
+		for i, m := range members {
+			if i > 0 {
+				buf = append(buf, ' ')
+			}
+			m.Key = f.Key + "." + m.Key
+			buf = _appendFieldText(buf, &m)
+		}
+		return buf
+	}
+	buf = append(buf, f.Key...)
+	buf = append(buf, '=')
+	return _appendValueText(buf, f)
+}
+
+// _flattenFields return the fields with the groups replaced by their fields with dotted keys, empty groups are kept,
+// fields itself when it has no groups
+func _flattenFields(fields []FieldT) []FieldT {
+	i := 0
+	for i < len(fields) && fields[i].kind != kindGroup {
+		i++
+	}
+	if i == len(fields) {
+		return fields
+	}
+	out := append([]FieldT(nil), fields[:i]...)
+	for _, f := range fields[i:] {
+		members, _ := f.any.([]FieldT)
+		if f.kind != kindGroup || len(members) == 0 {
+			out = append(out, f)
+			continue
+		}
+		for _, m := range _flattenFields(members) {
+			m.Key = f.Key + "." + m.Key
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// _appendGroupJSON append the fields as a JSON object
+func _appendGroupJSON(buf []byte, fields []FieldT) []byte {
+	buf = append(buf, '{')
+	for i := range fields {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = _appendFieldJSON(buf, &fields[i])
+	}
+	return append(buf, '}')
+}
+
+// _appendTextString append s, quoted when it is empty or contains spaces, quotes, '=' or control characters
+func _appendTextString(buf []byte, s string) []byte {
+	if s == "" {
+		return append(buf, `""`...)
+	}
+	for _, c := range s {
+		if c <= ' ' || c == '"' || c == '=' || c == 0x7f || c == utf8.RuneError {
+			return strconv.AppendQuote(buf, s)
+		}
+	}
+	return append(buf, s...)
+}
+
+// _appendFieldJSON append the field as "key":value
+func _appendFieldJSON(buf []byte, f *FieldT) []byte {
+	buf = _appendJSONString(buf, f.Key)
+	buf = append(buf, ':')
+	if s, ok := f._redacted(); ok {
+		return _appendJSONString(buf, s)
+	}
+	switch f.kind {
+	case kindString:
+		return _appendJSONString(buf, f.str)
+	case kindInt:
+		return strconv.AppendInt(buf, int64(f.num), 10)
+	case kindUint:
+		return strconv.AppendUint(buf, f.num, 10)
+	case kindFloat:
+		v := math.Float64frombits(f.num)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return _appendJSONString(buf, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		return strconv.AppendFloat(buf, v, 'g', -1, 64)
+	case kindBool:
+		return strconv.AppendBool(buf, f.num == 1)
+	case kindDuration:
+		return _appendJSONString(buf, time.Duration(f.num).String())
+	case kindTime:
+		buf = append(buf, '"')
+		buf = f.any.(time.Time).AppendFormat(buf, time.RFC3339Nano)
+		return append(buf, '"')
+	case kindError:
+		if f.any == nil {
+			return append(buf, "null"...)
+		}
+		if errs := _errorList(f.any.(error)); errs != nil {
+			buf = append(buf, '[')
+			for i, err := range errs {
+				if i > 0 {
+					buf = append(buf, ',')
+				}
+				buf = _appendJSONString(buf, err.Error())
+			}
+			return append(buf, ']')
+		}
+		return _appendJSONString(buf, f.any.(error).Error())
+	case kindGroup:
+		return _appendGroupJSON(buf, f.any.([]FieldT))
+	case kindJSON:
+		return append(buf, f.str...)
+	}
+	if f.any == nil {
+		return append(buf, "null"...)
+	}
+	return _appendJSONString(buf, fmt.Sprint(f.any))
+}
+
+// ErrorKV print prefixed (Error) log lines with level Error and the given fields
+func (e *Elog) ErrorKV(msg string, fields ...FieldT) {
+	e._logKV(2, lError, msg, fields)
+}
+
+// ErrorKVf print prefixed (Error) log lines with level Error and the key/value fields of args (see KVFields), as
+// Errorf with ELStructuredLog whatever the flags of the Elog
+func (e *Elog) ErrorKVf(msg string, args ...interface{}) {
+	e._logPairs(2, lError, "ErrorKVf", msg, args)
+}
+
+// WarnKV print prefixed (Warning) log lines with level Warning and the given fields
+func (e *Elog) WarnKV(msg string, fields ...FieldT) {
+	e._logKV(2, lWarn, msg, fields)
+}
+
+// WarnKVf print prefixed (Warning) log lines with level Warning and the key/value fields of args (see KVFields), as
+// Warnf with ELStructuredLog whatever the flags of the Elog
+func (e *Elog) WarnKVf(msg string, args ...interface{}) {
+	e._logPairs(2, lWarn, "WarnKVf", msg, args)
+}
+
+// InfoKV print prefixed (Info) log lines with level Info and the given fields
+func (e *Elog) InfoKV(msg string, fields ...FieldT) {
+	e._logKV(2, lInfo, msg, fields)
+}
+
+// InfoKVf print prefixed (Info) log lines with level Info and the key/value fields of args (see KVFields), as
+// Infof with ELStructuredLog whatever the flags of the Elog
+func (e *Elog) InfoKVf(msg string, args ...interface{}) {
+	e._logPairs(2, lInfo, "InfoKVf", msg, args)
+}
+
+// VerboseKV print prefixed (Verbose) log lines with level Verbose and the given fields
+func (e *Elog) VerboseKV(msg string, fields ...FieldT) {
+	e._logKV(2, lVerbose, msg, fields)
+}
+
+// VerboseKVf print prefixed (Verbose) log lines with level Verbose and the key/value fields of args (see KVFields), as
+// Verbosef with ELStructuredLog whatever the flags of the Elog
+func (e *Elog) VerboseKVf(msg string, args ...interface{}) {
+	e._logPairs(2, lVerbose, "VerboseKVf", msg, args)
+}
+
+// TraceKV print prefixed (Trace) log lines with level Trace and the given fields
+func (e *Elog) TraceKV(msg string, fields ...FieldT) {
+	e._logKV(2, lTrace, msg, fields)
+}
+
+// TraceKVf print prefixed (Trace) log lines with level Trace and the key/value fields of args (see KVFields), as
+// Tracef with ELStructuredLog whatever the flags of the Elog
+func (e *Elog) TraceKVf(msg string, args ...interface{}) {
+	e._logPairs(2, lTrace, "TraceKVf", msg, args)
+}
+
+// _logKV emit a leveled record with fields, calldepth is the same as for _log
+func (e *Elog) _logKV(calldepth int, level llevel, msg string, fields []FieldT) {
+	if e._dropCleared(_method(level) + "KV") {
+		return
+	}
+	if !e._enabled(level) || !e._traceAllowed(calldepth+1, level) {
+		if c := _captureFor(level); c != nil {
+			e._capture(calldepth+1, c, level, msg, fields)
+		}
+		e._debugDropped(level)
+		return
+	}
+	e._emit(calldepth+1, level, _valid(level.String()), msg, fields)
+}
+
+// _logPairs emit a leveled record of the message and the key/value fields of args (see KVFields), the call of
+// method, calldepth is the same as for _log
+func (e *Elog) _logPairs(calldepth int, level llevel, method, msg string, args []interface{}) {
+	if e._dropCleared(method) {
+		return
+	}
+	e._checkPairs(method, args)
+	if !e._enabled(level) || !e._traceAllowed(calldepth+1, level) {
+		if c := _captureFor(level); c != nil {
+			e._capture(calldepth+1, c, level, msg, KVFields(e._reg._renderArgs(args)...))
+		}
+		e._debugDropped(level)
+		return
+	}
+	e._emit(calldepth+1, level, _valid(level.String()), msg, KVFields(e._reg._renderArgs(args)...))
+}
+
+// SetLabels set static labels (team, component, tier, ...) emitted with every record of the Elog
+// in structured (JSON) output, labels are sorted by key and a nil or empty map remove them.
+// With ELNestFields the labels are written apart from the record fields, in a "tags" object.
+func (e *Elog) SetLabels(labels map[string]string) {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var fields []FieldT
+	for _, k := range keys {
+		fields = append(fields, String(k, labels[k]))
+	}
+	e._conf.Lock()
+	e._labels = fields
+	e._conf.Unlock()
+}
+
+// Labels retrieve the static labels of the Elog
+func (e *Elog) Labels() map[string]string {
+	fields := e._getLabels()
+	labels := make(map[string]string, len(fields))
+	for _, l := range fields {
+		labels[l.Key] = l.str
+	}
+	return labels
+}
@@ -0,0 +1,196 @@
+package elogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFieldsText(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestFieldsText", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(0)
+	elog.InfoKV("request", Field("path", "/a b"), Field("status", 200), Field("took", 1500*time.Millisecond),
+		Field("ok", true), Field("err", errors.New("boom")))
+	expected := `TestFieldsText (INFO) request path="/a b" status=200 took=1.5s ok=true err=boom` + "\n"
+	if b.String() != expected {
+		t.Errorf("unexpected fields rendering\n%q\n%q", b.String(), expected)
+	}
+}
+
+func TestFieldsJSON(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestFieldsJSON", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(ELJSONLog)
+	elog.WarnKV("fields", Field("n", -3), Field("f", 0.5), Field("u", uint8(7)), Field("any", []int{1, 2}), Err("err", nil))
+
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(b.Bytes(), &m); err != nil {
+		t.Fatalf("invalid json %q - %s", b.String(), err)
+	}
+	if m["n"] != -3.0 || m["f"] != 0.5 || m["u"] != 7.0 || m["any"] != "[1 2]" || m["err"] != nil {
+		t.Errorf("unexpected json fields %q", b.String())
+	}
+}
+
+func TestLabels(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestLabels", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(ELJSONLog)
+	elog.SetLabels(map[string]string{"team": "core", "tier": "1"})
+	elog.Info("labeled")
+	elog.SetFlags(0)
+	elog.Info("unlabeled text")
+
+	lines := bytes.Split(bytes.TrimSpace(b.Bytes()), []byte("\n"))
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(lines[0], &m); err != nil || m["team"] != "core" || m["tier"] != "1" {
+		t.Errorf("expected labels in json record %q", lines[0])
+	}
+	if bytes.Contains(lines[1], []byte("team")) {
+		t.Errorf("unexpected labels in text record %q", lines[1])
+	}
+}
+
+func TestNestFields(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestNestFields", "info", b)
+	elog.SetFlags(ELJSONLog | ELNestFields)
+	elog.SetLabels(map[string]string{"team": "core"})
+	elog.InfoKV("login", String("user", "bob"), Int("attempt", 2))
+	elog.SetLabels(nil)
+	elog.Info("bare")
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	expected := `{"scope":"TestNestFields","level":"INFO","msg":"login","tags":{"team":"core"},"fields":{"user":"bob","attempt":2}}`
+	if lines[0] != expected {
+		t.Errorf("expected %s, got %s", expected, lines[0])
+	}
+	if expected := `{"scope":"TestNestFields","level":"INFO","msg":"bare"}`; lines[1] != expected {
+		t.Errorf("expected %s, got %s", expected, lines[1])
+	}
+
+	replayed := &bytes.Buffer{}
+	if _, err := Replay(strings.NewReader(b.String()), FormatJSON, NewWriterSink(replayed, FlagsEncoder(ELJSONLog|ELNestFields), "")); err != nil {
+		t.Fatal(err)
+	}
+	if replayed.String() != b.String() {
+		t.Errorf("expected %q, got %q", b.String(), replayed.String())
+	}
+	rec := &Record{Scope: "s", Msg: "m", Fields: []FieldT{String("k", "v")}}
+	if err := CheckRecordInvariants(rec, _encodeFlags(nil, ELJSONLog|ELNestFields, rec)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMapFields(t *testing.T) {
+	m := map[string]interface{}{"zone": "eu", "id": 7, "active": true, "ratio": 0.5, "name": "bob"}
+	expected := "active=true id=7 name=bob ratio=0.5 zone=eu"
+	for i := 0; i < 20; i++ {
+		var buf []byte
+		for j, f := range MapFields(m) {
+			if j > 0 {
+				buf = append(buf, ' ')
+			}
+			buf = _appendFieldText(buf, &f)
+		}
+		if string(buf) != expected {
+			t.Fatalf("expected %s, got %s", expected, buf)
+		}
+	}
+	if fields := MapFields(map[string]int{}); len(fields) != 0 {
+		t.Errorf("unexpected fields %v", fields)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	rec := &Record{Scope: "s", Tag: "INFO", Msg: "m", Fields: []FieldT{
+		String("user", "bob"),
+		Group("http", String("method", "GET"), Int("status", 200), Group("req", String("path", "/a b"))),
+		Group("empty"),
+	}}
+	expected := `s (INFO) m user=bob http.method=GET http.status=200 http.req.path="/a b" empty={}` + "\n"
+	text := _encodeFlags(nil, log.Lmsgprefix, rec)
+	if string(text) != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+	expected = `{"scope":"s","level":"INFO","msg":"m","user":"bob","http":{"method":"GET","status":200,"req":{"path":"/a b"}},"empty":{}}` + "\n"
+	js := _encodeFlags(nil, ELJSONLog, rec)
+	if string(js) != expected {
+		t.Errorf("expected %q, got %q", expected, js)
+	}
+	for _, rendered := range [][]byte{text, js} {
+		if err := CheckRecordInvariants(rec, rendered); err != nil {
+			t.Error(err)
+		}
+	}
+
+	replayed := &bytes.Buffer{}
+	if _, err := Replay(bytes.NewReader(js), FormatJSON, NewWriterSink(replayed, FlagsEncoder(ELJSONLog), "")); err != nil {
+		t.Fatal(err)
+	}
+	if replayed.String() != string(js) {
+		t.Errorf("expected %q, got %q", js, replayed.String())
+	}
+	cef := (&CEFEncoder{FieldMap: map[string]string{}}).Encode(nil, rec)
+	if !bytes.Contains(cef, []byte(" http.method=GET http.status=200 http.req.path=/a b")) {
+		t.Errorf("unexpected CEF record %q", cef)
+	}
+	if fields := _normalizeFields([]FieldT{Group("Http", String("StatusCode", "x"))}, KeyCaseSnake, KeysKeepAll, nil); fields[0].Key != "http" ||
+		fields[0].Value().([]FieldT)[0].Key != "status_code" {
+		t.Errorf("unexpected normalized group %v", fields)
+	}
+}
+
+func TestStructuredLog(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestStructuredLog", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(ELStructuredLog)
+
+	elog.InfoKVf("login", "user", "bob smith", "attempts", 3, "admin", false)
+	elog.Warnf("retry", 42, time.Second, "orphan")
+	elog.Errorf("no pairs")
+	elog.CondErrf(errors.New("refused"), "info", "connect", "host", "db1")
+	elog.VerboseKVf("filtered", "k", "v")
+	expected := "TestStructuredLog (INFO) login user=\"bob smith\" attempts=3 admin=false\n" +
+		"TestStructuredLog (WARN) retry 42=1s orphan=(MISSING)\n" +
+		"TestStructuredLog (ERROR) no pairs\n" +
+		"TestStructuredLog (ERROR) connect host=db1 error=refused\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	elog.SetFlags(ELStructuredLog | ELJSONLog)
+	elog.Infof("login", "attempts", 3, Bool("admin", true))
+	if expected := `{"scope":"TestStructuredLog","level":"INFO","msg":"login","attempts":3,"admin":true}` + "\n"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	elog.SetFlags(0)
+	elog.Infof("user %s", "bob")
+	if expected := "TestStructuredLog (INFO) user bob\n"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	elog.InfoKVf("login", "user", "bob")
+	c := StartCapture("trace")
+	elog.TraceKVf("token", "token", testToken("tok_secret42"))
+	c.Stop()
+	if expected := "TestStructuredLog (INFO) login user=bob\n"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+	if captured := c.String(); !strings.Contains(captured, "token=tok_****42") {
+		t.Errorf("unexpected capture %q", captured)
+	}
+}
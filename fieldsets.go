@@ -0,0 +1,80 @@
+package elogging
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// canonical keys of the field set builders, following the OpenTelemetry semantic conventions
+const (
+	KeyHTTPMethod    = "http.request.method"
+	KeyHTTPPath      = "url.path"
+	KeyHTTPQuery     = "url.query"
+	KeyHTTPHost      = "server.address"
+	KeyHTTPProto     = "network.protocol.version"
+	KeyClientAddress = "client.address"
+	KeyUserAgent     = "user_agent.original"
+	KeyRequestID     = "request.id"
+
+	KeyRPCSystem  = "rpc.system"
+	KeyRPCService = "rpc.service"
+	KeyRPCMethod  = "rpc.method"
+	KeyRPCStatus  = "rpc.grpc.status_code"
+
+	KeyDBStatement = "db.statement"
+	KeyDBArgs      = "db.args"
+
+	KeyDuration = "duration"
+)
+
+// HTTPFields return the canonical fields describing an incoming HTTP request: method, path, query (when present),
+// host, protocol, client address, user agent and request id (X-Request-Id header) when present
+func HTTPFields(r *http.Request) []FieldT {
+	fields := []FieldT{String(KeyHTTPMethod, r.Method)}
+	if r.URL != nil {
+		fields = append(fields, String(KeyHTTPPath, r.URL.Path))
+		if r.URL.RawQuery != "" {
+			fields = append(fields, String(KeyHTTPQuery, r.URL.RawQuery))
+		}
+	}
+	fields = append(fields,
+		String(KeyHTTPHost, r.Host),
+		String(KeyHTTPProto, r.Proto),
+		String(KeyClientAddress, r.RemoteAddr))
+	if ua := r.UserAgent(); ua != "" {
+		fields = append(fields, String(KeyUserAgent, ua))
+	}
+	if id := r.Header.Get("X-Request-Id"); id != "" {
+		fields = append(fields, String(KeyRequestID, id))
+	}
+	return fields
+}
+
+// GRPCFields return the canonical fields describing a gRPC call from its full method name ("/pkg.Service/Method",
+// the FullMethod of the grpc server info), its status code (nil when not known yet) and duration (0 when not known)
+func GRPCFields(fullMethod string, code fmt.Stringer, dur time.Duration) []FieldT {
+	service, method := "", strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndexByte(method, '/'); i >= 0 {
+		service, method = method[:i], method[i+1:]
+	}
+	fields := []FieldT{String(KeyRPCSystem, "grpc"), String(KeyRPCService, service), String(KeyRPCMethod, method)}
+	if code != nil {
+		fields = append(fields, String(KeyRPCStatus, code.String()))
+	}
+	if dur > 0 {
+		fields = append(fields, Duration(KeyDuration, dur))
+	}
+	return fields
+}
+
+// DBFields return the canonical fields describing a database query: the statement, its arguments (when any)
+// and its duration
+func DBFields(query string, args []interface{}, dur time.Duration) []FieldT {
+	fields := []FieldT{String(KeyDBStatement, query)}
+	if len(args) > 0 {
+		fields = append(fields, Any(KeyDBArgs, args))
+	}
+	return append(fields, Duration(KeyDuration, dur))
+}
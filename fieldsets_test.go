@@ -0,0 +1,36 @@
+package elogging
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCode int
+
+func (c testCode) String() string { return "NotFound" }
+
+func TestFieldSets(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://example.com/items?id=3", nil)
+	r.Header.Set("X-Request-Id", "abc")
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestFieldSets", "info", b)
+	elog.SetFlags(0)
+
+	elog.InfoKV("request", HTTPFields(r)...)
+	elog.InfoKV("call", GRPCFields("/shop.Cart/AddItem", testCode(5), time.Second)...)
+	elog.InfoKV("query", DBFields("SELECT 1", nil, time.Millisecond)...)
+
+	for _, expected := range []string{
+		"http.request.method=GET url.path=/items url.query=\"id=3\" server.address=example.com",
+		"request.id=abc",
+		"rpc.system=grpc rpc.service=shop.Cart rpc.method=AddItem rpc.grpc.status_code=NotFound duration=1s",
+		"db.statement=\"SELECT 1\" duration=1ms",
+	} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("expected %q in %q", expected, b.String())
+		}
+	}
+}
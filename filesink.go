@@ -0,0 +1,121 @@
+package elogging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileSink is a sink writing records to files whose path is built from a template per record:
+//  {scope}  the scope of the Elog
+//  {level}  the record level: error, warning, info, verbose, trace, print or fatal
+//  {date}   the record date (UTC), 2006-01-02
+// e.g. "/var/log/app/{level}-{date}.log" puts errors in error-2024-05-01.log and trace records in trace-2024-05-01.log.
+// Opened files are kept open until the date changes or the sink is closed.
+type FileSink struct {
+	template string
+	enc      Encoder
+	minLevel llevel
+
+	mu    sync.Mutex
+	files map[string]*os.File
+	date  string
+}
+
+// NewFileSink create a sink writing the records at or above minLevel (empty for all) rendered by enc
+// (nil for the default text format) to the files given by the path template, missing directories are created.
+// The files of the template are pruned by the retention managers (see ManageDir) until the sink is closed.
+func NewFileSink(template string, enc Encoder, minLevel string) *FileSink {
+	if enc == nil {
+		enc = FlagsEncoder(DefaultFlags())
+	}
+	s := &FileSink{template: template, enc: enc, minLevel: lTrace, files: map[string]*os.File{}}
+	if minLevel != "" {
+		s.minLevel = _value(_valid(minLevel))
+	}
+	_setSinkTemplate(template, true)
+	return s
+}
+
+// Path return the file path the record is written to
+func (s *FileSink) Path(rec *Record) string {
+	return strings.NewReplacer(
+		"{scope}", _pathSafe(rec.Scope),
+		"{level}", strings.ToLower(rec.level.String()),
+		"{date}", rec.Time.UTC().Format("2006-01-02"),
+	).Replace(s.template)
+}
+
+// _pathSafe replace the path separators of a scope so it can be part of a file name
+func _pathSafe(scope string) string {
+	if scope == "" {
+		return "default"
+	}
+	return strings.NewReplacer("/", "_", `\`, "_").Replace(scope)
+}
+
+// ReceivesBootstrap report that the sink receives the records of the bootstrap buffer, see BootstrapReceiver
+func (s *FileSink) ReceivesBootstrap() bool {
+	return true
+}
+
+// WriteRecord render the record and append it to its file when its level is at or above the sink minimum level
+func (s *FileSink) WriteRecord(rec *Record) error {
+	if !_passes(rec.level, s.minLevel) {
+		return nil
+	}
+	buf := s.enc.Encode(make([]byte, 0, 128), rec)
+	path := s.Path(rec)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.files == nil {
+		return os.ErrClosed
+	}
+	if date := rec.Time.UTC().Format("2006-01-02"); date != s.date {
+		if s.date != "" && strings.Contains(s.template, "{date}") {
+			s._closeAll()
+		}
+		s.date = date
+	}
+	f, ok := s.files[path]
+	if !ok {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("elogging: file sink: %w", err)
+		}
+		var err error
+		if f, err = _openAppend(path); err != nil {
+			return err
+		}
+		s.files[path] = f
+		_setActive(path, true)
+	}
+	_, err := f.Write(buf)
+	return err
+}
+
+// _closeAll close the cached files
+func (s *FileSink) _closeAll() (err error) {
+	for path, f := range s.files {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		delete(s.files, path)
+		_setActive(path, false)
+	}
+	return
+}
+
+// Close close the opened files, records written afterwards are rejected
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.files == nil {
+		return nil
+	}
+	err := s._closeAll()
+	s.files = nil
+	_setSinkTemplate(s.template, false)
+	return err
+}
@@ -0,0 +1,43 @@
+package elogging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileSink(t *testing.T) {
+	dir := t.TempDir()
+	s := NewFileSink(filepath.Join(dir, "{scope}", "{level}-{date}.log"), FlagsEncoder(0), "")
+	elog := NewEphemeralElog("TestFileSink", "trace", &bytes.Buffer{})
+	elog.AddSink(s)
+
+	elog.Error("broken")
+	elog.Trace("step 1")
+	elog.Trace("step 2")
+	if len(s.files) != 2 {
+		t.Errorf("expected 2 cached files, got %d", len(s.files))
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Now().UTC().Format("2006-01-02")
+	errs, err := os.ReadFile(filepath.Join(dir, "TestFileSink", "error-"+date+".log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	traces, err := os.ReadFile(filepath.Join(dir, "TestFileSink", "trace-"+date+".log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(errs), "broken") || strings.Count(string(traces), "step") != 2 {
+		t.Errorf("unexpected files content %q %q", errs, traces)
+	}
+	if err := s.WriteRecord(&Record{Time: time.Now(), level: lError}); err == nil {
+		t.Error("expected an error once closed")
+	}
+}
@@ -0,0 +1,34 @@
+package elogging
+
+import (
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FingerprintKey is the key of the fingerprint field added to error records by ELErrorFingerprint
+const FingerprintKey = "fingerprint"
+
+// _variableParts match the parts of a message varying between occurrences of the same error:
+// quoted strings, UUIDs, hexadecimal values and numbers
+var _variableParts = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'[^']*'|` +
+	`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b|` +
+	`\b0[xX][0-9a-fA-F]+\b|\b[0-9a-fA-F]{8,}\b|\d+(?:\.\d+)?`)
+
+// NormalizeMessage return the message with its variable parts (quoted strings, UUIDs, hexadecimal values and numbers)
+// replaced by a placeholder and its spaces collapsed, e.g. `open "/tmp/a1": timeout after 30s` give `open <*>: timeout after <*>s`
+func NormalizeMessage(msg string) string {
+	return strings.Join(strings.Fields(_variableParts.ReplaceAllString(msg, "<*>")), " ")
+}
+
+// Fingerprint return a stable fingerprint of an error: a 64 bits FNV-1a hash, in hexadecimal, of the calling
+// function (pkg.Func) and the normalized message (see NormalizeMessage), so the occurrences of an error share it
+// whatever their arguments. The line of the call is left out so the fingerprint survives unrelated edits of the file.
+func Fingerprint(function, msg string) string {
+	h := fnv.New64a()
+	h.Write([]byte(function))
+	h.Write([]byte{0})
+	h.Write([]byte(NormalizeMessage(msg)))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
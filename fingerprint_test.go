@@ -0,0 +1,49 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	for msg, expected := range map[string]string{
+		`open "/tmp/a1": timeout after 30s`:                           `open <*>: timeout after <*>s`,
+		"user 42 not found  in shard 7\n":                             "user <*> not found in shard <*>",
+		"request 123e4567-e89b-12d3-a456-426614174000 failed at 0x1f": "request <*> failed at <*>",
+		"commit deadbeef42 rejected":                                  "commit <*> rejected",
+		"no such table":                                               "no such table",
+	} {
+		if got := NormalizeMessage(msg); got != expected {
+			t.Errorf("NormalizeMessage(%q) = %q, expected %q", msg, got, expected)
+		}
+	}
+}
+
+func _failLookup(elog *Elog, id int) {
+	elog.Errorf("user %d not found", id)
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestErrorFingerprint", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(ELErrorFingerprint)
+
+	_failLookup(elog, 1)
+	_failLookup(elog, 2)
+	elog.Errorf("user %d not found", 3)
+	elog.Info("user 4 found")
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 4 || strings.Contains(lines[3], FingerprintKey) {
+		t.Fatalf("unexpected output %q", b.String())
+	}
+	expected := FingerprintKey + "=" + Fingerprint("elogging._failLookup", "user 1 not found")
+	if !strings.HasSuffix(lines[0], expected) || !strings.HasSuffix(lines[1], expected) {
+		t.Errorf("expected the same fingerprint %q for the same call site, got %q", expected, lines[:2])
+	}
+	if strings.HasSuffix(lines[2], expected) || !strings.Contains(lines[2], FingerprintKey+"=") {
+		t.Errorf("expected another fingerprint for another call site, got %q", lines[2])
+	}
+}
@@ -0,0 +1,70 @@
+package elogging
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// RegisterFlags register the elogging configuration flags on fs (flag.CommandLine when nil):
+//  -elog.level         global log level
+//  -elog.scope-levels  comma separated scope=level list, e.g. db=trace,net=error
+//  -elog.format        output format: text, color, json or auto
+//  -elog.output        output destination: stdout, stderr, a file path or file:path?rotate=size
+// each flag configures the package as soon as it is parsed
+func RegisterFlags(fs *flag.FlagSet) {
+	_defaultRegistry.RegisterFlags(fs)
+}
+
+// RegisterFlags register the elogging configuration flags on fs (flag.CommandLine when nil) configuring the registry
+func (r *Registry) RegisterFlags(fs *flag.FlagSet) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	fs.Func("elog.level", "global log level (error, warning, info, verbose, trace)", func(s string) error {
+		if _, err := _parseLevel(s); err != nil {
+			return err
+		}
+		r.SetGlobalLogLevel(s)
+		return nil
+	})
+	fs.Func("elog.scope-levels", "comma separated scope=level list, e.g. db=trace,net=error", func(s string) error {
+		levels, err := _parseScopeLevels(s)
+		if err != nil {
+			return err
+		}
+		for _, sl := range levels {
+			r.SetScopeLogLevel(sl.Scope, sl.Level)
+		}
+		return nil
+	})
+	fs.Func("elog.format", "log output format (text, color, json, auto)", r.SetGlobalFormat)
+	fs.Func("elog.output", "log output (stdout, stderr, a file path or file:path?rotate=size)", func(s string) error {
+		out, err := r._openOutput(s)
+		if err != nil {
+			return err
+		}
+		r.SetGlobalOutput(out)
+		return nil
+	})
+}
+
+// _parseScopeLevels split a "scope=level,scope=level" list into validated scope/level pairs
+func _parseScopeLevels(s string) (levels []ScopeLevel, err error) {
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := strings.IndexByte(item, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("elogging: invalid scope level %q, expected scope=level", item)
+		}
+		scope, level := strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		if _, err = _parseLevel(level); err != nil {
+			return nil, err
+		}
+		levels = append(levels, ScopeLevel{Scope: scope, Level: level})
+	}
+	return
+}
@@ -0,0 +1,29 @@
+package elogging
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestRegisterFlags(t *testing.T) {
+	defer SetDefaultFlags(DefaultFlags())
+
+	db := NewElog("TestRegisterFlags.db", "info", nil)
+	defer db.Clear()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs)
+	err := fs.Parse([]string{"-elog.scope-levels", "TestRegisterFlags.db=trace", "-elog.format", "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.GetLevel() != "Trace" {
+		t.Errorf("expected trace level for scope, got %s", db.GetLevel())
+	}
+	if db.GetFlags()&ELJSONLog == 0 || DefaultFlags()&ELJSONLog == 0 {
+		t.Error("expected json format to be applied")
+	}
+	if err := fs.Parse([]string{"-elog.level", "loud"}); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
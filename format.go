@@ -0,0 +1,577 @@
+package elogging
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// elogging specific flags, they are combined with the golang log package flags (Ldate, Ltime, ...)
+const (
+	ELJSONLog          = 1 << (iota + 16) // write each record as a single line JSON object
+	ELColorLog                            // colorize the level tag of text records with ANSI escape sequences
+	ELFuncName                            // add the calling function (pkg.Func) to the caller info, alone when no file flag is set
+	ELTrimPath                            // log the file path relative to its module (see SetTrimPrefixes), Lshortfile overrides it
+	ELChecksum                            // end each record with a CRC32 of its content (see VerifyChecksum)
+	ELScopeColor                          // colorize the scope of text records with a stable color derived from the scope name
+	ELSymbols                             // replace the level tag of text records with a compact symbol (see ConsoleFlags)
+	ELTimeRFC3339                         // write the text timestamp as RFC3339 (with the fraction of the time flags, e.g. Lmicroseconds)
+	ELTimeEpoch                           // write the timestamp as unix epoch milliseconds (text and JSON)
+	ELSuppressRepeated                    // write a record repeated consecutively once, followed by a summary (see SetSuppressionPolicy)
+	ELTimeMillis                          // write the time with milliseconds, see ELTimeNanos
+	ELTimeNanos                           // write the time with nanoseconds (epoch timestamps too), over Lmicroseconds and ELTimeMillis
+	ELNestFields                          // write the JSON labels in a "tags" object and the record fields in a "fields" object
+	ELErrorFingerprint                    // add a fingerprint field to Error, Fatal and Panic records (see Fingerprint)
+	ELStructuredLog                       // the f calls (Infof, ...) take a message and key/value pairs, see KVFields
+)
+
+// ConsoleFlags is a compact console profile for narrow terminals: time only, colored level symbols
+// (✖ error, ⚠ warning, ℹ info, ·· verbose, ··· trace) instead of level words,
+// use it for the console output only so files and JSON sinks keep the canonical level tokens
+const ConsoleFlags = log.Ltime | log.Lmsgprefix | ELColorLog | ELSymbols
+
+const (
+	_fileFlags   = log.Lshortfile | log.Llongfile | ELTrimPath
+	_callerFlags = _fileFlags | ELFuncName
+	_timeFlags   = log.Ldate | log.Ltime | log.Lmicroseconds | ELTimeMillis | ELTimeNanos
+)
+
+// _timeDigits return the fractional second digits of the time according to the flags
+func _timeDigits(flags int) int {
+	switch {
+	case flags&ELTimeNanos != 0:
+		return 9
+	case flags&log.Lmicroseconds != 0:
+		return 6
+	case flags&ELTimeMillis != 0:
+		return 3
+	}
+	return 0
+}
+
+// _appendEpoch append the time as unix epoch milliseconds, nanoseconds with ELTimeNanos
+func _appendEpoch(buf []byte, flags int, t time.Time) []byte {
+	if flags&ELTimeNanos != 0 {
+		return strconv.AppendInt(buf, t.UnixNano(), 10)
+	}
+	return strconv.AppendInt(buf, t.UnixNano()/1e6, 10)
+}
+
+const _formatFlags = ELJSONLog | ELColorLog
+
+// _format render the record according to the Elog flags, a raw record (see WriteRaw) as is
+func (e *Elog) _format(rec *Record) []byte {
+	if rec.raw {
+		return append(append(make([]byte, 0, len(rec.Msg)+1), rec.Msg...), '\n')
+	}
+	if c := e._getCollections(); c != nil && len(rec.Fields) > 0 {
+		rendered := *rec
+		rendered.Fields = c._renderFields(rec.Fields)
+		rec = &rendered
+	}
+	return _encodeFlags(make([]byte, 0, 128), e._getFlags(), rec)
+}
+
+// _encodeFlags render the record according to the given flags
+func _encodeFlags(buf []byte, flags int, rec *Record) []byte {
+	if flags&ELJSONLog != 0 {
+		buf = _formatJSON(buf, flags, rec)
+	} else {
+		buf = _formatText(buf, flags, rec)
+	}
+	if flags&ELChecksum != 0 {
+		buf = _appendChecksum(buf, flags&ELJSONLog != 0)
+	}
+	return buf
+}
+
+const (
+	_crcText = " crc="
+	_crcJSON = `,"crc":"`
+)
+
+// _appendChecksum insert the CRC32 (IEEE) of the record content before its end (newline, or closing brace for JSON)
+func _appendChecksum(buf []byte, json bool) []byte {
+	end := len(buf) - 1 // newline
+	if json {
+		end-- // closing brace
+	}
+	sum := crc32.ChecksumIEEE(buf[:end])
+	tail := string(buf[end:])
+	buf = buf[:end]
+	if json {
+		buf = append(buf, _crcJSON...)
+	} else {
+		buf = append(buf, _crcText...)
+	}
+	for shift := 28; shift >= 0; shift -= 4 {
+		buf = append(buf, _hex[(sum>>uint(shift))&0xf])
+	}
+	if json {
+		buf = append(buf, '"')
+	}
+	return append(buf, tail...)
+}
+
+// VerifyChecksum check the checksum of a record written with the ELChecksum flag (text or JSON),
+// it returns false for a record without a checksum or whose content does not match it
+func VerifyChecksum(line []byte) bool {
+	line = bytes.TrimRight(line, "\r\n")
+	var i, start int
+	if i = bytes.LastIndex(line, []byte(_crcJSON)); i >= 0 && bytes.HasSuffix(line, []byte(`"}`)) {
+		start = i + len(_crcJSON)
+		line = line[:len(line)-2]
+	} else if i = bytes.LastIndex(line, []byte(_crcText)); i >= 0 {
+		start = i + len(_crcText)
+	} else {
+		return false
+	}
+	sum, err := strconv.ParseUint(string(line[start:]), 16, 32)
+	return err == nil && len(line)-start == 8 && uint32(sum) == crc32.ChecksumIEEE(line[:i])
+}
+
+// _formatText render a record the same way the golang log package does, with the scope as the prefix,
+// ParseLine read it back: keep them in sync
+func _formatText(buf []byte, flags int, rec *Record) []byte {
+	if flags&log.Lmsgprefix == 0 {
+		buf = _appendScope(buf, flags, rec.Scope)
+	}
+	if flags&_timeFlags != 0 && flags&(ELTimeRFC3339|ELTimeEpoch) != 0 {
+		t := rec.Time
+		if flags&log.LUTC != 0 {
+			t = t.UTC()
+		}
+		if flags&ELTimeEpoch != 0 {
+			buf = _appendEpoch(buf, flags, t)
+		} else {
+			buf = _appendRFC3339(buf, t, _timeDigits(flags))
+		}
+		buf = append(buf, ' ')
+	} else if flags&_timeFlags != 0 {
+		t := rec.Time
+		if flags&log.LUTC != 0 {
+			t = t.UTC()
+		}
+		if flags&log.Ldate != 0 {
+			year, month, day := t.Date()
+			_itoa(&buf, year, 4)
+			buf = append(buf, '/')
+			_itoa(&buf, int(month), 2)
+			buf = append(buf, '/')
+			_itoa(&buf, day, 2)
+			buf = append(buf, ' ')
+		}
+		if flags&(_timeFlags&^log.Ldate) != 0 {
+			hour, min, sec := t.Clock()
+			_itoa(&buf, hour, 2)
+			buf = append(buf, ':')
+			_itoa(&buf, min, 2)
+			buf = append(buf, ':')
+			_itoa(&buf, sec, 2)
+			if digits := _timeDigits(flags); digits > 0 {
+				ns := t.Nanosecond()
+				for i := digits; i < 9; i++ {
+					ns /= 10
+				}
+				buf = append(buf, '.')
+				_itoa(&buf, ns, digits)
+			}
+			buf = append(buf, ' ')
+		}
+	}
+	if flags&_fileFlags != 0 && rec.File != "" {
+		file := rec.File
+		if flags&log.Lshortfile != 0 {
+			file = file[strings.LastIndexByte(file, '/')+1:]
+		}
+		buf = append(buf, file...)
+		buf = append(buf, ':')
+		_itoa(&buf, rec.Line, -1)
+		buf = append(buf, ": "...)
+	}
+	if flags&ELFuncName != 0 && rec.Func != "" {
+		buf = append(buf, rec.Func...)
+		buf = append(buf, ": "...)
+	}
+	if flags&log.Lmsgprefix != 0 {
+		buf = _appendScope(buf, flags, rec.Scope)
+	}
+	if !rec.bare && flags&ELSymbols != 0 && rec.level != lPrint {
+		buf = append(buf, ' ')
+		if flags&ELColorLog != 0 {
+			buf = append(buf, _levelColor(rec.level)...)
+			buf = append(buf, _levelSymbol(rec.level)...)
+			buf = append(buf, _colorReset...)
+		} else {
+			buf = append(buf, _levelSymbol(rec.level)...)
+		}
+		buf = append(buf, ' ')
+	} else if !rec.bare {
+		buf = append(buf, " ("...)
+		if flags&ELColorLog != 0 && rec.level != lPrint {
+			buf = append(buf, _levelColor(rec.level)...)
+			buf = append(buf, rec.Tag...)
+			buf = append(buf, _colorReset...)
+		} else {
+			buf = append(buf, rec.Tag...)
+		}
+		buf = append(buf, ") "...)
+	}
+	version := rec.version != nil && !rec.bare
+	if len(rec.Fields) == 0 && !version {
+		buf = append(buf, rec.Msg...)
+	} else {
+		buf = append(buf, strings.TrimSuffix(rec.Msg, "\n")...)
+		if version {
+			buf = append(buf, ' ')
+			buf = _appendFieldText(buf, &FieldT{Key: rec.version.key, kind: kindString, str: rec.version.value})
+		}
+		for i := range rec.Fields {
+			buf = append(buf, ' ')
+			buf = _appendFieldText(buf, &rec.Fields[i])
+		}
+	}
+	if len(buf) == 0 || buf[len(buf)-1] != '\n' {
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+// _formatJSON render a record as a single line JSON object
+func _formatJSON(buf []byte, flags int, rec *Record) []byte {
+	buf = append(buf, '{')
+	buf = _appendVersionField(buf, rec)
+	if flags&_timeFlags != 0 {
+		t := rec.Time
+		if flags&log.LUTC != 0 {
+			t = t.UTC()
+		}
+		if flags&ELTimeEpoch != 0 {
+			buf = append(buf, `"time":`...)
+			buf = _appendEpoch(buf, flags, t)
+			buf = append(buf, ',')
+		} else {
+			digits := 9
+			if flags&(ELTimeMillis|ELTimeNanos) == ELTimeMillis && flags&log.Lmicroseconds == 0 {
+				digits = 3
+			}
+			buf = append(buf, `"time":"`...)
+			buf = _appendRFC3339(buf, t, digits)
+			buf = append(buf, `",`...)
+		}
+	}
+	buf = append(buf, `"scope":`...)
+	buf = _appendJSONString(buf, rec.Scope)
+	buf = append(buf, `,"level":`...)
+	buf = _appendJSONString(buf, rec.Tag)
+	if flags&_fileFlags != 0 && rec.File != "" {
+		file := rec.File
+		if flags&log.Lshortfile != 0 {
+			file = file[strings.LastIndexByte(file, '/')+1:]
+		}
+		buf = append(buf, `,"file":`...)
+		buf = _appendJSONString(buf, file)
+		buf = append(buf, `,"line":`...)
+		buf = strconv.AppendInt(buf, int64(rec.Line), 10)
+	}
+	if flags&ELFuncName != 0 && rec.Func != "" {
+		buf = append(buf, `,"func":`...)
+		buf = _appendJSONString(buf, rec.Func)
+	}
+	buf = append(buf, `,"msg":`...)
+	buf = _appendJSONString(buf, strings.TrimSuffix(rec.Msg, "\n"))
+	if flags&ELNestFields != 0 {
+		buf = _appendFieldsObject(buf, `,"tags":`, rec.Labels)
+		buf = _appendFieldsObject(buf, `,"fields":`, rec.Fields)
+		return append(buf, "}\n"...)
+	}
+	for i := range rec.Labels {
+		buf = append(buf, ',')
+		buf = _appendFieldJSON(buf, &rec.Labels[i])
+	}
+	for i := range rec.Fields {
+		buf = append(buf, ',')
+		buf = _appendFieldJSON(buf, &rec.Fields[i])
+	}
+	buf = append(buf, "}\n"...)
+	return buf
+}
+
+// _appendFieldsObject append the fields as a JSON object member after head, nothing when there are no fields
+func _appendFieldsObject(buf []byte, head string, fields []FieldT) []byte {
+	if len(fields) == 0 {
+		return buf
+	}
+	buf = append(buf, head...)
+	return _appendGroupJSON(buf, fields)
+}
+
+const _hex = "0123456789abcdef"
+
+// _appendJSONString append s as a quoted JSON string, invalid UTF-8 is replaced with U+FFFD
+func _appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf = append(buf, '\\', c)
+			case c == '\n':
+				buf = append(buf, '\\', 'n')
+			case c == '\r':
+				buf = append(buf, '\\', 'r')
+			case c == '\t':
+				buf = append(buf, '\\', 't')
+			case c < 0x20:
+				buf = append(buf, '\\', 'u', '0', '0', _hex[c>>4], _hex[c&0xf])
+			default:
+				buf = append(buf, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, "\ufffd"...)
+		} else {
+			buf = append(buf, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(buf, '"')
+}
+
+// _itoa append the decimal representation of i, zero padded to wid digits (negative wid for no padding)
+func _itoa(buf *[]byte, i int, wid int) {
+	var b [20]byte
+	bp := len(b) - 1
+	for i >= 10 || wid > 1 {
+		wid--
+		q := i / 10
+		b[bp] = byte('0' + i - q*10)
+		bp--
+		i = q
+	}
+	b[bp] = byte('0' + i)
+	*buf = append(*buf, b[bp:]...)
+}
+
+const _colorReset = "\x1b[0m"
+
+func _levelColor(level llevel) string {
+	switch level {
+	case lFatal:
+		return "\x1b[1;31m"
+	case lError:
+		return "\x1b[31m"
+	case lWarn:
+		return "\x1b[33m"
+	case lInfo:
+		return "\x1b[32m"
+	case lVerbose:
+		return "\x1b[36m"
+	case lTrace:
+		return "\x1b[35m"
+	}
+	return ""
+}
+
+// _levelSymbol return the compact symbol of the level used by ELSymbols
+func _levelSymbol(level llevel) string {
+	switch level {
+	case lFatal:
+		return "\u203c"
+	case lError:
+		return "\u2716"
+	case lWarn:
+		return "\u26a0"
+	case lInfo:
+		return "\u2139"
+	case lVerbose:
+		return "\u00b7\u00b7"
+	}
+	return "\u00b7\u00b7\u00b7"
+}
+
+// _scopeColors are the colors assigned to scopes by ELScopeColor, all readable on dark and light backgrounds
+var _scopeColors = [...]string{
+	"\x1b[38;5;33m", "\x1b[38;5;37m", "\x1b[38;5;70m", "\x1b[38;5;130m", "\x1b[38;5;133m", "\x1b[38;5;166m",
+	"\x1b[38;5;26m", "\x1b[38;5;29m", "\x1b[38;5;92m", "\x1b[38;5;100m", "\x1b[38;5;162m", "\x1b[38;5;67m",
+}
+
+// _scopeColor return the color of the scope, the same scope always gets the same color
+func _scopeColor(scope string) string {
+	h := uint32(2166136261)
+	for i := 0; i < len(scope); i++ {
+		h = (h ^ uint32(scope[i])) * 16777619
+	}
+	return _scopeColors[h%uint32(len(_scopeColors))]
+}
+
+// _appendScope append the scope, colored when ELScopeColor is set
+func _appendScope(buf []byte, flags int, scope string) []byte {
+	if flags&ELScopeColor == 0 || scope == "" {
+		return append(buf, scope...)
+	}
+	buf = append(buf, _scopeColor(scope)...)
+	buf = append(buf, scope...)
+	return append(buf, _colorReset...)
+}
+
+// _isTerminal report whether w is a character device (a console or a tty)
+func _isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// AutoFormatFlags return the format flag suited to the runtime environment:
+// ELJSONLog when ELOG_FORMAT=json is set or when the default output is not attached to a terminal,
+// ELColorLog otherwise. ELOG_FORMAT=text and ELOG_FORMAT=color force plain or colored text.
+// The NO_COLOR and FORCE_COLOR conventions are honoured: a terminal gets plain text when NO_COLOR is set
+// or when it does not support colors (Windows consoles without virtual terminal processing),
+// FORCE_COLOR selects colored text whatever the output.
+func AutoFormatFlags() int {
+	return _defaultRegistry.AutoFormatFlags()
+}
+
+// AutoFormatFlags return the format flag suited to the runtime environment and the default output of the registry
+func (r *Registry) AutoFormatFlags() int {
+	out := r._defaultOut
+	if out == nil {
+		out = os.Stdout
+	}
+	switch strings.ToLower(os.Getenv("ELOG_FORMAT")) {
+	case "json":
+		return ELJSONLog
+	case "color", "colour":
+		_enableColor(out)
+		return ELColorLog
+	case "text", "plain":
+		return 0
+	}
+	if os.Getenv("FORCE_COLOR") != "" {
+		_enableColor(out)
+		return ELColorLog
+	}
+	if _isTerminal(out) {
+		if os.Getenv("NO_COLOR") != "" || !_enableColor(out) {
+			return 0
+		}
+		return ELColorLog
+	}
+	return ELJSONLog
+}
+
+// UseAutoFormat replace the format part of the default flags with AutoFormatFlags,
+// Elogs created afterwards and the package default log use the selected format
+func UseAutoFormat() {
+	_defaultRegistry.UseAutoFormat()
+}
+
+// UseAutoFormat replace the format part of the default flags of the registry with AutoFormatFlags
+func (r *Registry) UseAutoFormat() {
+	ff := r.AutoFormatFlags()
+	r.SetDefaultFlags(r._defaultFlags&^_formatFlags | ff)
+	r._stdLog.SetFlags(r._stdLog._getFlags()&^_formatFlags | ff)
+}
+
+// _formatFlagsByName return the format flags for a format name: text, color, json or auto, auto enable the colors of
+// the default output when it selects them
+func (r *Registry) _formatFlagsByName(format string) (int, error) {
+	ff, auto, err := _parseFormat(format)
+	if auto {
+		return r.AutoFormatFlags(), nil
+	}
+	return ff, err
+}
+
+// _parseFormat return the format flags for a format name, auto report the auto format whose flags depend on the
+// runtime environment, without side effects
+func _parseFormat(format string) (ff int, auto bool, err error) {
+	switch strings.ToLower(format) {
+	case "text", "plain":
+		return 0, false, nil
+	case "color", "colour":
+		return ELColorLog, false, nil
+	case "json":
+		return ELJSONLog, false, nil
+	case "auto":
+		return 0, true, nil
+	}
+	return 0, false, fmt.Errorf("elogging: unknown format %q", format)
+}
+
+// SetGlobalFormat change the format (text, color, json or auto) of the default flags, of all the existing Elogs
+// and of the package default log
+func SetGlobalFormat(format string) error {
+	return _defaultRegistry.SetGlobalFormat(format)
+}
+
+// SetGlobalFormat change the format of the default flags, of all the Elogs and of the default log of the registry
+func (r *Registry) SetGlobalFormat(format string) error {
+	ff, err := r._formatFlagsByName(format)
+	if err != nil {
+		return err
+	}
+	r.SetDefaultFlags(r._defaultFlags&^_formatFlags | ff)
+	for k := range r._registered() {
+		k.SetFlags(k._getFlags()&^_formatFlags | ff)
+	}
+	r._stdLog.SetFlags(r._stdLog._getFlags()&^_formatFlags | ff)
+	return nil
+}
+
+// _funcName return the name of the function at pc trimmed to pkg.Func
+func _funcName(pc uintptr) string {
+	f := runtime.FuncForPC(pc)
+	if f == nil {
+		return "???"
+	}
+	name := f.Name()
+	return name[strings.LastIndexByte(name, '/')+1:]
+}
+
+var _trimPrefixes []string
+
+// SetTrimPrefixes set the path prefixes removed from file paths by ELTrimPath (e.g. the module root on the build machine),
+// files not matching any prefix are shown as their package import path followed by the file name
+func SetTrimPrefixes(prefixes ...string) {
+	_trimPrefixes = append([]string(nil), prefixes...)
+}
+
+// _trimPath return file relative to the first matching trim prefix, or as import/path/file.go
+// using the package path of the function at pc
+func _trimPath(file string, pc uintptr) string {
+	for _, prefix := range _trimPrefixes {
+		if strings.HasPrefix(file, prefix) {
+			return strings.TrimLeft(file[len(prefix):], "/")
+		}
+	}
+	f := runtime.FuncForPC(pc)
+	if f == nil {
+		return file
+	}
+	name := f.Name()
+	slash := strings.LastIndexByte(name, '/')
+	dot := strings.IndexByte(name[slash+1:], '.')
+	if dot < 0 {
+		return file
+	}
+	return name[:slash+1+dot] + "/" + file[strings.LastIndexByte(file, '/')+1:]
+}
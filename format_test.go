@@ -0,0 +1,157 @@
+package elogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestJSONFormat(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestJSONFormat", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(elog.GetFlags() | ELJSONLog)
+	elog.Infof("a \"quoted\" %s", "message")
+
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(b.Bytes(), &m); err != nil {
+		t.Fatalf("invalid json record %q - %s", b.String(), err)
+	}
+	if m["scope"] != "TestJSONFormat" || m["level"] != "INFO" || m["msg"] != `a "quoted" message` {
+		t.Errorf("unexpected json record %q", b.String())
+	}
+	if _, ok := m["line"]; !ok {
+		t.Errorf("missing caller info in json record %q", b.String())
+	}
+}
+
+func TestColorFormat(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestColorFormat", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(elog.GetFlags() | ELColorLog)
+	elog.Error("colored")
+	if !strings.Contains(b.String(), "(\x1b[31mERROR\x1b[0m) colored") {
+		t.Errorf("expected colored level tag, got %q", b.String())
+	}
+}
+
+func TestAutoFormat(t *testing.T) {
+	defer SetDefaultFlags(DefaultFlags())
+	defer os.Unsetenv("ELOG_FORMAT")
+
+	os.Setenv("ELOG_FORMAT", "json")
+	UseAutoFormat()
+	if DefaultFlags()&ELJSONLog == 0 {
+		t.Error("expected json format with ELOG_FORMAT=json")
+	}
+	os.Setenv("ELOG_FORMAT", "color")
+	UseAutoFormat()
+	if DefaultFlags()&_formatFlags != ELColorLog {
+		t.Error("expected only color format with ELOG_FORMAT=color")
+	}
+	os.Unsetenv("ELOG_FORMAT")
+	SetDefaultOutput(&bytes.Buffer{})
+	defer SetDefaultOutput(nil)
+	UseAutoFormat()
+	if DefaultFlags()&_formatFlags != ELJSONLog {
+		t.Error("expected json format when not attached to a terminal")
+	}
+}
+
+func TestFuncName(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestFuncName", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(log.Lmsgprefix | ELFuncName)
+	elog.Info("where")
+	if b.String() != "elogging.TestFuncName: TestFuncName (INFO) where\n" {
+		t.Errorf("unexpected function caller info %q", b.String())
+	}
+}
+
+func TestTrimPath(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestTrimPath", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(log.Lmsgprefix | ELTrimPath)
+	elog.Info("module relative")
+	if !strings.HasPrefix(b.String(), "github.com/gilwo/elogging/format_test.go:") {
+		t.Errorf("unexpected trimmed path %q", b.String())
+	}
+
+	b.Reset()
+	wd, _ := os.Getwd()
+	SetTrimPrefixes(wd)
+	defer SetTrimPrefixes()
+	elog.Info("prefix relative")
+	if !strings.HasPrefix(b.String(), "format_test.go:") {
+		t.Errorf("unexpected trimmed path %q", b.String())
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestChecksum", "info", b)
+	defer elog.Clear()
+	for _, flags := range []int{ELChecksum, ELChecksum | ELJSONLog} {
+		b.Reset()
+		elog.SetFlags(DefaultFlags() | flags)
+		elog.Info("check me")
+		line := b.Bytes()
+		if !VerifyChecksum(line) {
+			t.Errorf("checksum verification failed for %q", line)
+		}
+		corrupted := bytes.Replace(line, []byte("check me"), []byte("check m3"), 1)
+		if VerifyChecksum(corrupted) {
+			t.Errorf("checksum verification passed for corrupted %q", corrupted)
+		}
+		if flags&ELJSONLog != 0 && json.Unmarshal(line, &map[string]interface{}{}) != nil {
+			t.Errorf("invalid json with checksum %q", line)
+		}
+	}
+}
+
+func TestColorConventions(t *testing.T) {
+	r := NewRegistry()
+	r.SetDefaultOutput(&bytes.Buffer{})
+	t.Setenv("FORCE_COLOR", "1")
+	if r.AutoFormatFlags() != ELColorLog {
+		t.Error("expected color with FORCE_COLOR")
+	}
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("NO_COLOR", "1")
+	if r.AutoFormatFlags() != ELJSONLog {
+		t.Error("expected json when not attached to a terminal")
+	}
+}
+
+func TestScopeColor(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestScopeColor", "info", b)
+	elog.SetFlags(log.Lmsgprefix | ELScopeColor)
+	elog.Info("colored")
+	elog.Info("again")
+	color := _scopeColor("TestScopeColor")
+	if strings.Count(b.String(), color+"TestScopeColor"+_colorReset+" (INFO)") != 2 {
+		t.Errorf("expected the scope colored, got %q", b.String())
+	}
+	if _scopeColor("TestScopeColor") != color {
+		t.Error("expected a stable scope color")
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestSymbols", "trace", b)
+	elog.SetFlags(log.Lmsgprefix | ELSymbols)
+	elog.Error("failed")
+	elog.Trace("step")
+	elog.Print("plain")
+	if b.String() != "TestSymbols ✖ failed\nTestSymbols ··· step\nTestSymbols (Print) plain\n" {
+		t.Errorf("unexpected symbols output %q", b.String())
+	}
+}
@@ -0,0 +1,258 @@
+package elogging
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// ForwardEnv is the environment variable giving a child process started with StartForwarded the descriptor of its
+// forwarding pipe
+const ForwardEnv = "ELOG_FORWARD_FD"
+
+// _frame is a record as framed on a forwarding pipe: one JSON object per line keeping the level, the call tag,
+// the caller and the field types
+type _frame struct {
+	Time   time.Time     `json:"t"`
+	Scope  string        `json:"s,omitempty"`
+	Level  llevel        `json:"l"`
+	Tag    string        `json:"g"`
+	File   string        `json:"f,omitempty"`
+	Line   int           `json:"n,omitempty"`
+	Func   string        `json:"fn,omitempty"`
+	Msg    string        `json:"m"`
+	Fields []_frameField `json:"k,omitempty"`
+}
+
+// _frameField is a typed field of a frame
+type _frameField struct {
+	Key   string        `json:"k"`
+	Kind  fieldKind     `json:"t"`
+	Num   uint64        `json:"n,omitempty"`
+	Str   string        `json:"s,omitempty"`
+	Group []_frameField `json:"g,omitempty"` // the fields of a group
+}
+
+// ForwardSink is the child side of the log forwarding: a sink framing the records onto a pipe read by the parent
+// process (see StartForwarded and ReadForwarded)
+type ForwardSink struct {
+	w io.Writer
+}
+
+// NewForwardSink create a sink framing the records onto w
+func NewForwardSink(w io.Writer) *ForwardSink {
+	return &ForwardSink{w: w}
+}
+
+// ForwardSinkFromEnv return a sink on the forwarding pipe inherited from a parent which started the process with
+// StartForwarded, nil when the process was not started this way, e.g.
+//  if s, err := elogging.ForwardSinkFromEnv(); s != nil {
+//  	elog.AddSink(s)
+//  	elog.ModifyParams("", "", io.Discard) // the parent writes the records
+//  }
+func ForwardSinkFromEnv() (*ForwardSink, error) {
+	v := os.Getenv(ForwardEnv)
+	if v == "" {
+		return nil, nil
+	}
+	fd, err := strconv.Atoi(v)
+	if err != nil || fd < 3 {
+		return nil, fmt.Errorf("elogging: invalid %s %q", ForwardEnv, v)
+	}
+	return NewForwardSink(os.NewFile(uintptr(fd), "elogging-forward")), nil
+}
+
+// ReceivesBootstrap report that the sink receives the records of the bootstrap buffer, see BootstrapReceiver
+func (s *ForwardSink) ReceivesBootstrap() bool {
+	return true
+}
+
+// WriteRecord frame the record onto the pipe, with a single write
+func (s *ForwardSink) WriteRecord(rec *Record) error {
+	f := _frame{Time: rec.Time, Scope: rec.Scope, Level: rec.level, Tag: rec.Tag, File: rec.File, Line: rec.Line,
+		Func: rec.Func, Msg: rec.Msg}
+	for _, fields := range [][]FieldT{rec.Labels, rec.Fields} {
+		for i := range fields {
+			f.Fields = append(f.Fields, _toFrameField(&fields[i]))
+		}
+	}
+	buf, err := json.Marshal(&f)
+	if err != nil {
+		return err
+	}
+	return _lockedWrite(s.w, append(buf, '\n'))
+}
+
+// _toFrameField convert a field for framing, numbers are kept as their bits, redacted values as strings
+func _toFrameField(f *FieldT) _frameField {
+	ff := _frameField{Key: f.Key, Kind: f.kind}
+	if s, ok := f._redacted(); ok {
+		ff.Kind, ff.Str = kindString, s
+		return ff
+	}
+	switch f.kind {
+	case kindString:
+		ff.Str = f.str
+	case kindInt, kindUint, kindFloat, kindBool, kindDuration:
+		ff.Num = f.num
+	case kindError:
+		if f.any != nil {
+			ff.Num, ff.Str = 1, string(_appendValueRaw(nil, f))
+		}
+	case kindGroup:
+		for _, m := range f.any.([]FieldT) {
+			ff.Group = append(ff.Group, _toFrameField(&m))
+		}
+	default:
+		ff.Kind, ff.Str = kindString, string(_appendValueRaw(nil, f))
+		if f.kind == kindTime {
+			ff.Kind = kindTime
+		}
+	}
+	return ff
+}
+
+// _fromFrameField rebuild a field from its frame
+func _fromFrameField(ff *_frameField) FieldT {
+	switch ff.Kind {
+	case kindInt, kindUint, kindFloat, kindBool, kindDuration:
+		return FieldT{Key: ff.Key, kind: ff.Kind, num: ff.Num}
+	case kindTime:
+		t, _ := time.Parse(time.RFC3339Nano, ff.Str)
+		return Time(ff.Key, t)
+	case kindGroup:
+		fields := make([]FieldT, 0, len(ff.Group))
+		for i := range ff.Group {
+			fields = append(fields, _fromFrameField(&ff.Group[i]))
+		}
+		return Group(ff.Key, fields...)
+	case kindError:
+		if ff.Num == 0 {
+			return Err(ff.Key, nil)
+		}
+		return Err(ff.Key, errors.New(ff.Str))
+	}
+	return String(ff.Key, ff.Str)
+}
+
+// ReadForwarded re-emit the records framed by a child process on r, see the package ReadForwarded
+func (r *Registry) ReadForwarded(rd io.Reader, prefix string) error {
+	elogs := map[string]*Elog{}
+	sc := bufio.NewScanner(rd)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var f _frame
+		if err := json.Unmarshal(sc.Bytes(), &f); err != nil {
+			_internalf("invalid forwarded record: %v", err)
+			continue
+		}
+		scope := prefix
+		if f.Scope != "" {
+			scope = _joinScope(prefix, f.Scope)
+		}
+		e := elogs[scope]
+		if e == nil {
+			if e = r._findScope(scope); e == nil {
+				e = r.NewElogDefaults(scope)
+			}
+			elogs[scope] = e
+		}
+		e._forward(&f)
+	}
+	return sc.Err()
+}
+
+// ReadForwarded is the parent side of the log forwarding: it re-emits the records framed by a child process
+// (see ForwardSink) on r until EOF, through the Elogs of the registry scoped prefix.<child scope> (created when
+// missing), so the levels, outputs and sinks of the parent apply to them. Time, caller, tag and typed fields of the
+// child records are kept, Fatal and Panic records of the child are written but do not exit nor panic.
+func ReadForwarded(r io.Reader, prefix string) error {
+	return _defaultRegistry.ReadForwarded(r, prefix)
+}
+
+// _joinScope join a scope prefix and a scope
+func _joinScope(prefix, scope string) string {
+	if prefix == "" {
+		return scope
+	}
+	return prefix + "." + scope
+}
+
+// _forward emit a forwarded record when the Elog levels enable it
+func (e *Elog) _forward(f *_frame) {
+	level := f.Level
+	switch {
+	case level == lPrint && !e._printEnabled(), level >= lError && level <= lTrace && !e._enabled(level):
+		e._debugDropped(level)
+		return
+	case level < lFatal || level > lTrace || level == lDisabled:
+		level = lInfo
+	}
+	fields := make([]FieldT, 0, len(e._fields)+len(f.Fields))
+	fields = append(fields, e._fields...)
+	for i := range f.Fields {
+		fields = append(fields, _fromFrameField(&f.Fields[i]))
+	}
+	rec := Record{Time: f.Time, Scope: e._scope(), Tag: f.Tag, File: f.File, Line: f.Line, Func: f.Func, Msg: f.Msg,
+		Fields: fields, Labels: e._getLabels(), level: level, bare: e._getCompat(_family(level)) != CompatElogging}
+	for _, h := range e._getHooks() {
+		h(&rec)
+	}
+	e._emitRecord(1, rec)
+}
+
+// Forwarder is a child process whose records are forwarded to the parent, see StartForwarded
+type Forwarder struct {
+	done chan struct{}
+	err  error
+}
+
+// StartForwarded start cmd with a forwarding pipe (see ForwardSinkFromEnv for the child side) and re-emit its
+// records through the Elogs of the registry scoped prefix.<child scope>, see ReadForwarded
+func (r *Registry) StartForwarded(cmd *exec.Cmd, prefix string) (*Forwarder, error) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("elogging: forwarding pipe: %w", err)
+	}
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.ExtraFiles = append(cmd.ExtraFiles, pw)
+	cmd.Env = append(cmd.Env, ForwardEnv+"="+strconv.Itoa(2+len(cmd.ExtraFiles)))
+	err = cmd.Start()
+	pw.Close()
+	if err != nil {
+		pr.Close()
+		return nil, err
+	}
+	fw := &Forwarder{done: make(chan struct{})}
+	go func() {
+		defer close(fw.done)
+		fw.err = r.ReadForwarded(pr, prefix)
+		pr.Close()
+	}()
+	return fw, nil
+}
+
+// StartForwarded start cmd and forward the records of the child process (framed with the sink returned by
+// ForwardSinkFromEnv) through the package Elogs scoped prefix.<child scope>, e.g.
+//  cmd := exec.Command("./worker")
+//  fw, err := elogging.StartForwarded(cmd, "worker1")
+//  ...
+//  cmd.Wait()
+//  fw.Wait() // the last records of the worker are written
+func StartForwarded(cmd *exec.Cmd, prefix string) (*Forwarder, error) {
+	return _defaultRegistry.StartForwarded(cmd, prefix)
+}
+
+// Wait wait until the child process closed its forwarding pipe (usually by exiting) and its records were written
+func (fw *Forwarder) Wait() error {
+	<-fw.done
+	return fw.err
+}
@@ -0,0 +1,71 @@
+package elogging
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForwardSink(t *testing.T) {
+	pipe := &bytes.Buffer{}
+	child := NewRegistry()
+	worker := child.NewElog("db", "trace", &bytes.Buffer{})
+	worker.AddSink(NewForwardSink(pipe))
+	worker.InfoKV("query", Int("rows", -3), Float64("ratio", 0.25), Duration("took", time.Second),
+		Err("err", errors.New("boom")), Err("none", nil), Time("at", time.Unix(0, 5).UTC()), Any("v", []int{1}),
+		Group("http", String("method", "GET"), Group("req", Int("size", 2))))
+	worker.Trace("details")
+	worker.Println("plain")
+
+	parent := NewRegistry()
+	out := &bytes.Buffer{}
+	parent.SetDefaultOutput(out)
+	parent.SetDefaultFlags(log.Lmsgprefix)
+	if err := parent.ReadForwarded(bytes.NewReader(pipe.Bytes()), "worker1"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "worker1.db (INFO) query rows=-3 ratio=0.25 took=1s err=boom none=<nil> at=1970-01-01T00:00:00.000000005Z v=[1] http.method=GET http.req.size=2\n" +
+		"worker1.db (Println) plain\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+	if parent.ListScopedLogs()[0].Scope() != "worker1.db" {
+		t.Error("forwarded scope not registered")
+	}
+}
+
+func TestStartForwarded(t *testing.T) {
+	if os.Getenv("ELOG_TEST_CHILD") == "1" {
+		s, err := ForwardSinkFromEnv()
+		if s == nil || err != nil {
+			os.Exit(2)
+		}
+		elog := NewRegistry().NewElog("child", "info", &bytes.Buffer{})
+		elog.AddSink(s)
+		elog.Warn("from the child")
+		os.Exit(0)
+	}
+	r := NewRegistry()
+	out := &bytes.Buffer{}
+	r.SetDefaultOutput(out)
+	cmd := exec.Command(os.Args[0], "-test.run=TestStartForwarded")
+	cmd.Env = append(os.Environ(), "ELOG_TEST_CHILD=1")
+	fw, err := r.StartForwarded(cmd, "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fw.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "w.child (WARN) from the child") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
@@ -0,0 +1,143 @@
+package elogging
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+)
+
+// FreezeToken authorize the configuration changes of a frozen registry, see Freeze
+type FreezeToken struct {
+	r      *Registry
+	secret string
+}
+
+// String return the secret of the token, expected by the admin handler in the X-Elog-Token header
+func (t *FreezeToken) String() string {
+	return t.secret
+}
+
+// Do run fn with the configuration changes of the registry made by the calling goroutine allowed,
+// the changes of the other goroutines are still ignored while fn runs
+func (t *FreezeToken) Do(fn func()) {
+	r := t.r
+	if r == nil || r._frozen() != t {
+		fn()
+		return
+	}
+	gid := _goid()
+	r._freezeMu.Lock()
+	r._thawed[gid]++
+	r._freezeMu.Unlock()
+	defer func() {
+		r._freezeMu.Lock()
+		if r._thawed[gid]--; r._thawed[gid] == 0 {
+			delete(r._thawed, gid)
+		}
+		r._freezeMu.Unlock()
+	}()
+	fn()
+}
+
+// _check report whether secret is the secret of the token, in constant time
+func (t *FreezeToken) _check(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(t.secret)) == 1
+}
+
+// Freeze freeze the configuration of the registry, see the package Freeze
+func (r *Registry) Freeze() *FreezeToken {
+	r._freezeMu.Lock()
+	defer r._freezeMu.Unlock()
+	if r._freeze != nil {
+		return nil
+	}
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		_internalf("configuration not frozen, no freeze token: %v", err)
+		return nil
+	}
+	r._freeze = &FreezeToken{r: r, secret: hex.EncodeToString(b[:])}
+	r._thawed = map[uint64]int{}
+	return r._freeze
+}
+
+// Freeze freeze the package configuration once the application configured it at startup: the following changes
+// of levels, global level and mode, logs on/off, flags, formats, outputs, defaults and level overrides are reported
+// on stderr (with the caller) and ignored, so library code can not stomp on the application configuration.
+// The returned token, only given to the first caller, authorize changes with token.Do or Thaw; the admin handler
+// accepts level changes carrying the token in the X-Elog-Token header. Creating Elogs is not restricted.
+// nil is returned when the registry is already frozen or no token can be generated (reported on stderr).
+func Freeze() *FreezeToken {
+	return _defaultRegistry.Freeze()
+}
+
+// Thaw unfreeze the configuration of the registry
+func (r *Registry) Thaw(t *FreezeToken) error {
+	r._freezeMu.Lock()
+	defer r._freezeMu.Unlock()
+	if r._freeze == nil {
+		return nil
+	}
+	if t != r._freeze {
+		return fmt.Errorf("elogging: invalid freeze token")
+	}
+	r._freeze, r._thawed = nil, nil
+	return nil
+}
+
+// Thaw unfreeze the package configuration with the token returned by Freeze
+func Thaw(t *FreezeToken) error {
+	return _defaultRegistry.Thaw(t)
+}
+
+// Frozen report whether the configuration of the registry is frozen
+func (r *Registry) Frozen() bool {
+	return r._frozen() != nil
+}
+
+// Frozen report whether the package configuration is frozen
+func Frozen() bool {
+	return _defaultRegistry.Frozen()
+}
+
+// _frozen return the freeze token of the registry, nil when not frozen
+func (r *Registry) _frozen() *FreezeToken {
+	r._freezeMu.Lock()
+	defer r._freezeMu.Unlock()
+	return r._freeze
+}
+
+// _allow report whether a configuration change is allowed, a change of a frozen registry is reported and ignored
+func (r *Registry) _allow(scope, setting string) bool {
+	return r._allowWith(nil, scope, setting)
+}
+
+// _allowWith report whether a configuration change authorized by t (nil for none) is allowed, the changes of a
+// frozen registry are allowed with its token or from a goroutine running its Do, the others are reported and ignored
+func (r *Registry) _allowWith(t *FreezeToken, scope, setting string) bool {
+	r._freezeMu.Lock()
+	allowed := r._freeze == nil || t == r._freeze || (len(r._thawed) > 0 && r._thawed[_goid()] > 0)
+	r._freezeMu.Unlock()
+	if allowed {
+		return true
+	}
+	if scope != "" {
+		setting = scope + " " + setting
+	}
+	_internalf("configuration frozen, %s change by %s ignored", setting, _externalCaller())
+	return false
+}
+
+// _allow report whether a configuration change of the Elog is allowed, the internal Elogs are never frozen
+func (e *Elog) _allow(setting string) bool {
+	return e._allowWith(nil, setting)
+}
+
+// _allowWith report whether a configuration change of the Elog authorized by t (nil for none) is allowed
+func (e *Elog) _allowWith(t *FreezeToken, setting string) bool {
+	if r := e._reg; e == r._auditLog || e == r._debug {
+		return true
+	}
+	return e._reg._allowWith(t, e._scope(), setting)
+}
@@ -0,0 +1,94 @@
+package elogging
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	r := NewRegistry()
+	db := r.NewElog("TestFreeze.db", "info", io.Discard)
+	token := r.Freeze()
+	if token == nil || !r.Frozen() || r.Freeze() != nil {
+		t.Fatal("unexpected freeze tokens")
+	}
+	db.SetLevel("trace")
+	db.SetFlags(ELJSONLog)
+	r.SetGlobalLogLevel("trace")
+	r.LogsOff()
+	r.SetDefaultOutput(io.Discard)
+	db.ModifyParams("TestFreeze.other", "trace", nil)
+	db.SetCompat(FamilyLeveled, CompatStdlib)
+	db.SetPrintGate(PrintAsInfo, "")
+	if gate, _ := db.GetPrintGate(); db.Scope() != "TestFreeze.db" || db.Compat(FamilyLeveled) != CompatElogging ||
+		gate != PrintBypass {
+		t.Errorf("frozen scope, compatibility profile or print gate changed: %s %s %s", db.Scope(),
+			db.Compat(FamilyLeveled), gate)
+	}
+	if db.GetLevel() != "Info" || db.GetFlags() == ELJSONLog || r._getGlobalLevel() != lDisabled || !r._active() || r._defaultOut != nil {
+		t.Error("frozen configuration changed")
+	}
+
+	token.Do(func() { db.SetLevel("verbose") })
+	if db.GetLevel() != "Verbose" {
+		t.Error("change through the token not applied")
+	}
+	db.SetLevel("trace")
+	if db.GetLevel() != "Verbose" {
+		t.Error("configuration not frozen again after Do")
+	}
+
+	srv := httptest.NewServer(http.StripPrefix("/elog", r.AdminHandler()))
+	defer srv.Close()
+	post := func(tok string) int {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/elog/level?level=error&scope=TestFreeze.db", strings.NewReader(""))
+		req.Header.Set("X-Elog-Token", tok)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := post("wrong"); code != http.StatusForbidden || db.GetLevel() != "Verbose" {
+		t.Errorf("unexpected status %d, level %s", code, db.GetLevel())
+	}
+	if code := post(token.String()); code != http.StatusNoContent || db.GetLevel() != "Error" {
+		t.Errorf("unexpected status %d, level %s", code, db.GetLevel())
+	}
+
+	if err := r.Thaw(&FreezeToken{}); err == nil {
+		t.Error("expected an error with a foreign token")
+	}
+	if err := r.Thaw(token); err != nil || r.Frozen() {
+		t.Errorf("registry still frozen: %v", err)
+	}
+	db.SetLevel("trace")
+	if db.GetLevel() != "Trace" {
+		t.Error("change not applied after Thaw")
+	}
+}
+
+func TestFreezeDoGoroutine(t *testing.T) {
+	r := NewRegistry()
+	db := r.NewElog("TestFreezeDoGoroutine.db", "info", io.Discard)
+	token := r.Freeze()
+	token.Do(func() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			db.SetLevel("trace")
+		}()
+		<-done
+		if db.GetLevel() != "Info" {
+			t.Errorf("change of another goroutine applied while Do runs, level %s", db.GetLevel())
+		}
+		db.SetLevel("verbose")
+	})
+	if db.GetLevel() != "Verbose" {
+		t.Errorf("change through the token not applied, level %s", db.GetLevel())
+	}
+}
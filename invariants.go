@@ -0,0 +1,108 @@
+package elogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// _reservedKeys are the keys of the record itself in the JSON format
+var _reservedKeys = map[string]bool{"time": true, "scope": true, "level": true, "msg": true, "file": true,
+	"line": true, "func": true, "crc": true}
+
+// CheckRecordInvariants check the rendering of a record by a formatter (an Encoder, the flags of an Elog) and return
+// the first invariant violated, meant to be driven by fuzz tests of custom formatters, e.g.
+//  f.Fuzz(func(t *testing.T, msg, value string) {
+//  	rec := &elogging.Record{Time: time.Now(), Scope: "fuzz", Msg: msg, Fields: []elogging.FieldT{elogging.String("k", value)}}
+//  	if err := elogging.CheckRecordInvariants(rec, myEncoder.Encode(nil, rec)); err != nil {
+//  		t.Fatal(err)
+//  	}
+//  })
+// The rendering must be valid UTF-8 and end with a single newline. A JSON object must be on a single line, be valid
+// JSON and hold the message (msg or message), the scope and a key for each field of the record (at the top level
+// or in a "fields" object, see ELNestFields). A text line may only
+// span several lines when the message does (the text format keeps the newlines of messages), it must parse back with
+// ParseLine to the scope, message and fields of the record (groups as their dotted keys) (the message is not checked when it holds key=value
+// pairs, read back as fields). Records without level tag are only checked for the lines.
+func CheckRecordInvariants(rec *Record, rendered []byte) error {
+	if !utf8.Valid(rendered) {
+		return fmt.Errorf("elogging: invariant: invalid UTF-8 in %q", rendered)
+	}
+	if !bytes.HasSuffix(rendered, []byte("\n")) || bytes.HasSuffix(rendered, []byte("\n\n")) {
+		return fmt.Errorf("elogging: invariant: %q does not end with a single newline", rendered)
+	}
+	line := rendered[:len(rendered)-1]
+	if bytes.HasPrefix(line, []byte("{")) {
+		return _checkJSONRecord(rec, line)
+	}
+	if n, allowed := bytes.Count(line, []byte("\n")), strings.Count(rec.Msg, "\n"); n > allowed {
+		return fmt.Errorf("elogging: invariant: %q spans %d lines, the message %d", rendered, n+1, allowed+1)
+	} else if n > 0 || rec.bare {
+		return nil
+	}
+	return _checkTextRecord(rec, string(line))
+}
+
+// _checkJSONRecord check a record rendered as a JSON object
+func _checkJSONRecord(rec *Record, line []byte) error {
+	if bytes.IndexByte(line, '\n') >= 0 {
+		return fmt.Errorf("elogging: invariant: JSON record %q spans several lines", line)
+	}
+	var obj map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil || dec.More() {
+		return fmt.Errorf("elogging: invariant: invalid JSON record %q: %v", line, err)
+	}
+	msg, ok := obj["msg"]
+	if !ok {
+		msg, ok = obj["message"]
+	}
+	if !ok || msg != rec.Msg {
+		return fmt.Errorf("elogging: invariant: JSON record %q does not hold the message %q", line, rec.Msg)
+	}
+	if scope, ok := obj["scope"]; rec.Scope != "" && (!ok || scope != rec.Scope) {
+		return fmt.Errorf("elogging: invariant: JSON record %q does not hold the scope %q", line, rec.Scope)
+	}
+	nested, _ := obj["fields"].(map[string]interface{})
+	for _, f := range rec.Fields {
+		if _, ok := nested[f.Key]; ok {
+			continue
+		}
+		if _, ok := obj[f.Key]; !ok && f.Key != "" && !_reservedKeys[f.Key] {
+			return fmt.Errorf("elogging: invariant: JSON record %q does not hold the field %q", line, f.Key)
+		}
+	}
+	return nil
+}
+
+// _checkTextRecord check a record rendered as a text line parses back
+func _checkTextRecord(rec *Record, line string) error {
+	got, err := ParseLine(line)
+	if err != nil {
+		return fmt.Errorf("elogging: invariant: %w", err)
+	}
+	if got.Scope != rec.Scope {
+		return fmt.Errorf("elogging: invariant: %q parses back to the scope %q, expected %q", line, got.Scope, rec.Scope)
+	}
+	if strings.Contains(rec.Msg, "=") {
+		return nil // a message ending with key=value pairs is read as fields
+	}
+	if got.Msg != rec.Msg {
+		return fmt.Errorf("elogging: invariant: %q parses back to the message %q, expected %q", line, got.Msg, rec.Msg)
+	}
+	fields := _flattenFields(rec.Fields)
+	if len(got.Fields) != len(fields) {
+		return fmt.Errorf("elogging: invariant: %q parses back to %d fields, expected %d", line, len(got.Fields), len(fields))
+	}
+	for i := range fields {
+		f, g := &fields[i], &got.Fields[i]
+		if g.Key != f.Key || g.str != string(_appendValueRaw(nil, f)) {
+			return fmt.Errorf("elogging: invariant: %q parses back to the field %s=%q, expected %s=%q", line, g.Key, g.str,
+				f.Key, _appendValueRaw(nil, f))
+		}
+	}
+	return nil
+}
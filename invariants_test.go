@@ -0,0 +1,35 @@
+package elogging
+
+import (
+	"log"
+	"testing"
+	"time"
+)
+
+func TestCheckRecordInvariants(t *testing.T) {
+	for _, msg := range []string{"plain", "two\nlines", "quote \" and \\ and tab\t", "ünïcode ✓", "a=1 b"} {
+		rec := &Record{Time: time.Now(), Scope: "db", Tag: "INFO", Msg: msg, level: lInfo,
+			Fields: []FieldT{String("user", "bob smith"), Int("n", 3), String("nl", "x\ny")}}
+		for _, flags := range []int{_initialFlags, log.Lmsgprefix | ELTimeRFC3339 | log.Ltime, ELJSONLog, ELJSONLog | ELChecksum} {
+			if err := CheckRecordInvariants(rec, _encodeFlags(nil, flags, rec)); err != nil {
+				t.Errorf("flags %#x: %v", flags, err)
+			}
+		}
+	}
+
+	rec := &Record{Scope: "db", Tag: "INFO", Msg: "msg", level: lInfo, Fields: []FieldT{String("k", "v")}}
+	for _, bad := range []string{
+		"db (INFO) msg k=v",                     // no newline
+		"db (INFO) msg k=v\n\n",                 // blank line
+		"db (INFO) msg\nk=v\n",                  // split line
+		"db (INFO) msg k=\xff\n",                // invalid UTF-8
+		"db (INFO) other k=v\n",                 // message
+		"db (INFO) msg k=w\n",                   // field value
+		`{"scope":"db","msg":"msg"}` + "\n",     // missing field
+		`{"scope":"db","msg":"msg","k":` + "\n", // invalid JSON
+	} {
+		if err := CheckRecordInvariants(rec, []byte(bad)); err == nil {
+			t.Errorf("%q: expected an invariant violation", bad)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package elogging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// EnableKeyboardControl let the developer control the logs of the registry from the terminal, see the package EnableKeyboardControl
+func (r *Registry) EnableKeyboardControl() (disable func(), err error) {
+	if !_isTerminal(os.Stdin) {
+		return nil, errors.New("elogging: keyboard control needs a terminal on stdin")
+	}
+	restore, err := _setRawInput(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("elogging: keyboard control: %w", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		buf := make([]byte, 16)
+		for {
+			n, err := os.Stdin.Read(buf)
+			select {
+			case <-done:
+				return
+			default:
+			}
+			for _, c := range buf[:n] {
+				r._keyControl(c, os.Stderr)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			restore()
+		})
+	}, nil
+}
+
+// EnableKeyboardControl let the developer control the logs from the terminal of a local run:
+//  +  raise the global level (cycling back to disabled after trace)
+//  -  lower the global level
+//  s  write the registry dump (see DumpState) to stderr
+//  p  pause or resume the output (see LogsOff)
+// the terminal input is switched to unbuffered mode where supported (Linux, macOS), elsewhere keys are read
+// once enter is pressed. Call disable to restore the terminal, stdin must not be read by the program meanwhile.
+func EnableKeyboardControl() (disable func(), err error) {
+	return _defaultRegistry.EnableKeyboardControl()
+}
+
+// _keyControl apply the action of a control key, reporting it on w
+func (r *Registry) _keyControl(c byte, w io.Writer) {
+	switch c {
+	case '+', '=':
+		r.SetGlobalLogLevel(((r._getGlobalLevel() + 1) % (lTrace + 1)).String())
+		fmt.Fprintf(w, "elogging: global level %s\n", r._getGlobalLevel())
+	case '-', '_':
+		r.SetGlobalLogLevel(((r._getGlobalLevel() + lTrace) % (lTrace + 1)).String())
+		fmt.Fprintf(w, "elogging: global level %s\n", r._getGlobalLevel())
+	case 's', 'S':
+		r.DumpState(w)
+	case 'p', 'P':
+		if r._active() {
+			r.LogsOff()
+		} else {
+			r.LogsOn()
+		}
+		if r._active() {
+			fmt.Fprintln(w, "elogging: output resumed")
+		} else {
+			fmt.Fprintln(w, "elogging: output paused")
+		}
+	}
+}
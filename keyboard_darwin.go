@@ -0,0 +1,8 @@
+package elogging
+
+import "syscall"
+
+const (
+	_ioctlGetTermios = syscall.TIOCGETA
+	_ioctlSetTermios = syscall.TIOCSETA
+)
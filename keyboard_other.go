@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package elogging
+
+import "os"
+
+// _setRawInput leave the terminal input line buffered, keys are read once enter is pressed
+func _setRawInput(f *os.File) (restore func(), err error) {
+	return func() {}, nil
+}
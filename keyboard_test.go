@@ -0,0 +1,32 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestKeyControl(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	r._keyControl('+', b)
+	r._keyControl('+', b)
+	if r._getGlobalLevel() != lWarn {
+		t.Errorf("expected warning global level, got %s", r._getGlobalLevel())
+	}
+	r._keyControl('-', b)
+	r._keyControl('-', b)
+	r._keyControl('-', b)
+	if r._getGlobalLevel() != lTrace {
+		t.Errorf("expected the global level to cycle to trace, got %s", r._getGlobalLevel())
+	}
+	r._keyControl('p', b)
+	if r._active() {
+		t.Error("expected the output paused")
+	}
+	r._keyControl('p', b)
+	r._keyControl('s', b)
+	if !r._active() || !strings.Contains(b.String(), "logs active: true") {
+		t.Errorf("unexpected control output %q", b.String())
+	}
+}
@@ -0,0 +1,31 @@
+//go:build linux || darwin
+
+package elogging
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// _setRawInput disable the line buffering of the terminal input, echo and signals (Ctrl-C) are kept
+func _setRawInput(f *os.File) (restore func(), err error) {
+	var saved syscall.Termios
+	if err = _termios(f.Fd(), _ioctlGetTermios, &saved); err != nil {
+		return nil, err
+	}
+	raw := saved
+	raw.Lflag &^= syscall.ICANON
+	raw.Cc[syscall.VMIN], raw.Cc[syscall.VTIME] = 1, 0
+	if err = _termios(f.Fd(), _ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	return func() { _termios(f.Fd(), _ioctlSetTermios, &saved) }, nil
+}
+
+func _termios(fd, req uintptr, t *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(unsafe.Pointer(t))); errno != 0 {
+		return errno
+	}
+	return nil
+}
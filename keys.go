@@ -0,0 +1,128 @@
+package elogging
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// KeyCase is the normalization applied to the field keys of the records
+type KeyCase int
+
+const (
+	KeyCaseAsIs  KeyCase = iota // keys are kept as given (default)
+	KeyCaseLower                // keys are lowercased: userID -> userid
+	KeyCaseSnake                // keys are converted to snake_case: userID -> user_id, user-name -> user_name
+)
+
+// KeyCollision decide how fields with the same key (once normalized) in a record are handled
+type KeyCollision int
+
+const (
+	KeysKeepAll  KeyCollision = iota // all the fields are written, collectors may reject the record (default)
+	KeysLastWins                     // only the last field with the key is kept, at the position of the first one
+	KeysSuffix                       // the following fields with the key are renamed key_2, key_3, ...
+)
+
+// SetKeyNormalization change the normalization of the field keys and the handling of duplicate keys
+// of the records of the Elogs of the registry
+func (r *Registry) SetKeyNormalization(keyCase KeyCase, collision KeyCollision) {
+	r._keyCase, r._keyCollision = keyCase, collision
+}
+
+// SetKeyNormalization change the normalization of the field keys (case) and the deterministic handling of
+// duplicate keys (collision) of the records, e.g. SetKeyNormalization(KeyCaseSnake, KeysLastWins).
+// Unless the fields are nested (ELNestFields) a field with a key of the record itself (time, scope, level, msg, ...,
+// the version field) or of a label is renamed key_2, key_3, ... with both KeysLastWins and KeysSuffix.
+func SetKeyNormalization(keyCase KeyCase, collision KeyCollision) {
+	_defaultRegistry.SetKeyNormalization(keyCase, collision)
+}
+
+// _normalizeKey return the key converted to the key case
+func _normalizeKey(key string, keyCase KeyCase) string {
+	switch keyCase {
+	case KeyCaseLower:
+		return strings.ToLower(key)
+	case KeyCaseSnake:
+		return _snakeCase(key)
+	}
+	return key
+}
+
+// _snakeCase convert a camelCase, PascalCase, kebab-case or space separated key to snake_case,
+// dots (namespaces) are kept: httpStatus -> http_status, HTTPStatus -> http_status, user.firstName -> user.first_name
+func _snakeCase(key string) string {
+	runes := []rune(key)
+	var b strings.Builder
+	for i, c := range runes {
+		switch {
+		case c == '-' || c == ' ':
+			b.WriteByte('_')
+		case unicode.IsUpper(c):
+			if i > 0 && runes[i-1] != '.' && runes[i-1] != '_' && runes[i-1] != '-' && runes[i-1] != ' ' &&
+				(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+					(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(c))
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// _recordKeys are the keys written by the JSON encoder before the fields of a record
+var _recordKeys = []string{"time", "scope", "level", "file", "line", "func", "msg"}
+
+// _normalizeRecord normalize the keys of the fields of the record and handle their duplicates, the keys of the
+// record itself (time, scope, level, msg, ..., the version field) and of its labels are taken (unless the fields
+// are nested): a field with one of them is always renamed key_2, key_3, ... as the record keys and labels can't
+// be dropped
+func (r *Registry) _normalizeRecord(rec *Record, nested bool) {
+	var taken []string
+	if !nested && r._keyCollision != KeysKeepAll {
+		taken = append(taken, _recordKeys...)
+		if rec.version != nil {
+			taken = append(taken, rec.version.key)
+		}
+		for i := range rec.Labels {
+			taken = append(taken, rec.Labels[i].Key)
+		}
+	}
+	rec.Fields = _normalizeFields(rec.Fields, r._keyCase, r._keyCollision, taken)
+}
+
+// _normalizeFields return the fields with their keys normalized and the duplicates handled, a field with a taken
+// key is renamed, fields is not modified
+func _normalizeFields(fields []FieldT, keyCase KeyCase, collision KeyCollision, taken []string) []FieldT {
+	out := make([]FieldT, 0, len(fields))
+	index := make(map[string]int, len(fields)+len(taken))
+	for _, key := range taken {
+		index[key] = -1
+	}
+	for _, f := range fields {
+		f.Key = _normalizeKey(f.Key, keyCase)
+		if f.kind == kindGroup {
+			f.any = _normalizeFields(f.any.([]FieldT), keyCase, collision, nil)
+		}
+		if i, dup := index[f.Key]; dup {
+			switch {
+			case collision == KeysLastWins && i >= 0:
+				out[i] = f
+				continue
+			case collision == KeysSuffix || collision == KeysLastWins:
+				base := f.Key
+				for n := 2; dup; n++ {
+					f.Key = base + "_" + strconv.Itoa(n)
+					_, dup = index[f.Key]
+				}
+			}
+		}
+		if _, ok := index[f.Key]; !ok {
+			index[f.Key] = len(out)
+		}
+		out = append(out, f)
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package elogging
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSnakeCase(t *testing.T) {
+	for key, expected := range map[string]string{
+		"userID":         "user_id",
+		"HTTPStatus":     "http_status",
+		"user-name":      "user_name",
+		"user.firstName": "user.first_name",
+		"already_snake":  "already_snake",
+		"Count2Items":    "count2_items",
+	} {
+		if got := _snakeCase(key); got != expected {
+			t.Errorf("%s: expected %s, got %s", key, expected, got)
+		}
+	}
+}
+
+func TestKeyNormalization(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestKeyNormalization", "info", b)
+	elog.SetFlags(ELJSONLog)
+
+	r.SetKeyNormalization(KeyCaseSnake, KeysLastWins)
+	elog.InfoKV("m", Int("userID", 1), Int("user_id", 2), Int("count", 3))
+	r.SetKeyNormalization(KeyCaseLower, KeysSuffix)
+	elog.InfoKV("m", Int("ID", 1), Int("id", 2), Int("Id", 3))
+
+	if !bytes.Contains(b.Bytes(), []byte(`"user_id":2,"count":3}`)) ||
+		!bytes.Contains(b.Bytes(), []byte(`"id":1,"id_2":2,"id_3":3}`)) {
+		t.Errorf("unexpected output %s", b.String())
+	}
+}
+
+func TestKeyNormalizationReserved(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestKeyNormalizationReserved", "info", b)
+	elog.SetFlags(ELJSONLog)
+	elog.SetLabels(map[string]string{"env": "prod"})
+	r.SetVersionField("schema", "")
+
+	r.SetKeyNormalization(KeyCaseLower, KeysLastWins)
+	elog.InfoKV("m", String("MSG", "x"), String("Level", "y"), String("env", "dev"), String("schema", "2"), Int("n", 1), Int("N", 2))
+	r.SetKeyNormalization(KeyCaseAsIs, KeysSuffix)
+	elog.InfoKV("m", String("scope", "a"), String("scope", "b"), String("env", "dev"))
+	elog.SetFlags(ELJSONLog | ELNestFields)
+	elog.InfoKV("m", String("msg", "x"))
+
+	for _, expected := range []string{
+		`"msg":"m","env":"prod","msg_2":"x","level_2":"y","env_2":"dev","schema_2":"2","n":2}`,
+		`"msg":"m","env":"prod","scope_2":"a","scope_3":"b","env_2":"dev"}`,
+		`"msg":"m","tags":{"env":"prod"},"fields":{"msg":"x"}}`,
+	} {
+		if !bytes.Contains(b.Bytes(), []byte(expected)) {
+			t.Errorf("expected %s in %s", expected, b.String())
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package elogging
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// LazyValue is a value computed only when a record using it is actually written,
+// use it for arguments or fields which are expensive to compute. With ELSuppressRepeated the value is computed for
+// every record passing the levels, a repeated record is recognized from its rendered message and fields.
+type LazyValue func() interface{}
+
+// Lazy wrap fn so it is only called when the record it is passed to is written (or checked for repetition, see
+// LazyValue), e.g. e.Verbosef("state: %v", elogging.Lazy(func() interface{} { return dumpState() }))
+func Lazy(fn func() interface{}) LazyValue {
+	return LazyValue(fn)
+}
+
+// String compute the value and return its default formatting
+func (l LazyValue) String() string {
+	return fmt.Sprint(l())
+}
+
+// Format compute the value and format it with the same verb, flags, width and precision
+func (l LazyValue) Format(f fmt.State, verb rune) {
+	format := []byte{'%'}
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			format = append(format, byte(flag))
+		}
+	}
+	if w, ok := f.Width(); ok {
+		format = strconv.AppendInt(format, int64(w), 10)
+	}
+	if p, ok := f.Precision(); ok {
+		format = append(format, '.')
+		format = strconv.AppendInt(format, int64(p), 10)
+	}
+	format = append(format, string(verb)...)
+	fmt.Fprintf(f, string(format), l())
+}
@@ -0,0 +1,50 @@
+package elogging
+
+import (
+	"bytes"
+	"testing"
+)
+
+type countingStringer struct{ calls *int }
+
+func (c countingStringer) String() string {
+	*c.calls++
+	return "counted"
+}
+
+func TestLazyEvaluation(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestLazyEvaluation", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(0)
+
+	calls, lazyCalls := 0, 0
+	lazy := Lazy(func() interface{} { lazyCalls++; return 42 })
+	elog.Verbose(countingStringer{&calls}, lazy)
+	elog.Tracef("%s %d", countingStringer{&calls}, lazy)
+	elog.VerboseKV("kv", Any("stringer", countingStringer{&calls}), Any("lazy", lazy))
+	if calls != 0 || lazyCalls != 0 {
+		t.Errorf("filtered records evaluated their arguments: %d stringer calls, %d lazy calls", calls, lazyCalls)
+	}
+
+	elog.Infof("%s %04d", countingStringer{&calls}, lazy)
+	if calls != 1 || lazyCalls != 1 || b.String() != "TestLazyEvaluation (INFO) counted 0042\n" {
+		t.Errorf("unexpected output %q (%d stringer calls, %d lazy calls)", b.String(), calls, lazyCalls)
+	}
+}
+
+func TestLazyEvaluationSuppressed(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestLazyEvaluationSuppressed", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(ELSuppressRepeated)
+
+	lazyCalls := 0
+	lazy := Lazy(func() interface{} { lazyCalls++; return 42 })
+	for i := 0; i < 3; i++ {
+		elog.Infof("value %d", lazy)
+	}
+	if lazyCalls != 3 || b.String() != "TestLazyEvaluationSuppressed (INFO) value 42\n" {
+		t.Errorf("unexpected output %q (%d lazy calls)", b.String(), lazyCalls)
+	}
+}
@@ -0,0 +1,134 @@
+package elogging
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// _misuseSet is the set of the call sites already warned about a misuse
+type _misuseSet struct {
+	mu     sync.Mutex
+	warned map[string]bool
+}
+
+// _formatVerb match a fmt verb in a message passed to a non-f method
+var _formatVerb = regexp.MustCompile(`%[-+# 0]*[0-9*]*(\.[0-9*]*)?[vTtbcdoOqxXUeEfFgGsp]`)
+
+// SetMisuseWarnings turn on or off the runtime detection of the common misuses of the Elogs of the registry,
+// see the package SetMisuseWarnings
+func (r *Registry) SetMisuseWarnings(on bool) {
+	if !on {
+		r._misuse = nil
+		return
+	}
+	if r._misuse == nil {
+		r._misuse = &_misuseSet{warned: map[string]bool{}}
+	}
+}
+
+// SetMisuseWarnings turn on the runtime detection of common misuses, each one reported once per call site on stderr:
+// logging with an Elog after Clear, an unknown level name (SetLevel, NewElog, SetGlobalLogLevel, ...),
+// format verbs passed to a non-f method (Info("%d users", n) rather than Infof) and key/value arguments passed to a
+// non-f method of a JSON Elog (Info("login", "user", u) rather than InfoKV), with an odd count, and a key without
+// value in the key/value pairs of the f calls with ELStructuredLog and of the KVf methods. Meant for development,
+// the checks cost a little on every call.
+func SetMisuseWarnings(on bool) {
+	_defaultRegistry.SetMisuseWarnings(on)
+}
+
+// _warnMisuse report a misuse once per call site
+func (r *Registry) _warnMisuse(format string, args ...interface{}) {
+	m := r._misuse
+	if m == nil {
+		return
+	}
+	site := _externalCaller()
+	msg := fmt.Sprintf(format, args...)
+	m.mu.Lock()
+	warned := m.warned[site+msg]
+	m.warned[site+msg] = true
+	m.mu.Unlock()
+	if !warned {
+		_internalf("misuse at %s: %s", site, msg)
+	}
+}
+
+// _dropCleared report whether the Elog was cleared, the record of the call of method is then dropped (a misuse,
+// reported when detected)
+func (e *Elog) _dropCleared(method string) bool {
+	if !e._isCleared() {
+		return false
+	}
+	e._reg._warnMisuse("%s called on the Elog %q after Clear, the record is dropped", method, e._scope())
+	return true
+}
+
+// _method return the name of the methods of a level (Warn for Warning)
+func _method(level llevel) string {
+	if level == lWarn {
+		return "Warn"
+	}
+	return level.String()
+}
+
+// _checkLevelName warn about an unknown level name
+func (r *Registry) _checkLevelName(level string) {
+	if r._misuse == nil || level == "" {
+		return
+	}
+	if _, err := _parseLevel(level); err != nil {
+		r._warnMisuse("unknown level %q, taken as disabled", level)
+	}
+}
+
+// _checkCall warn about the misuses of a call of a non-f method (Info, Print, ...) named method
+func (e *Elog) _checkCall(method string, args []interface{}) {
+	r := e._reg
+	if r._misuse == nil {
+		return
+	}
+	if len(args) < 2 {
+		return
+	}
+	msg, ok := args[0].(string)
+	if !ok {
+		return
+	}
+	if _formatVerb.MatchString(msg) {
+		r._warnMisuse("format verbs in the message of %s, use %sf", method, method)
+		return
+	}
+	if e._getFlags()&ELJSONLog == 0 {
+		return
+	}
+	for i := 1; i < len(args); i += 2 {
+		if _, ok := args[i].(string); !ok {
+			return
+		}
+	}
+	if len(args)%2 == 0 {
+		r._warnMisuse("odd key/value count passed to %s, key %q has no value, use %sKV with fields", method, args[len(args)-1], method)
+	} else {
+		r._warnMisuse("key/value arguments passed to %s are part of the message, use %sKV with fields", method, method)
+	}
+}
+
+// _checkPairs warn about a trailing key without value in the key/value arguments of a call of method taking pairs
+// (the f calls with ELStructuredLog, InfoKVf, ...)
+func (e *Elog) _checkPairs(method string, args []interface{}) {
+	r := e._reg
+	if r._misuse == nil {
+		return
+	}
+	for i := 0; i < len(args); i++ {
+		if _, ok := args[i].(FieldT); ok {
+			continue
+		}
+		if i+1 == len(args) {
+			r._warnMisuse("odd key/value count passed to %s, key %q has no value", method, fmt.Sprint(args[i]))
+			return
+		}
+		i++
+	}
+}
@@ -0,0 +1,80 @@
+package elogging
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMisuseWarnings(t *testing.T) {
+	r := NewRegistry()
+	r.SetMisuseWarnings(true)
+	elog := r.NewElog("TestMisuseWarnings", "info", io.Discard)
+	msg := "%d users"
+	for i := 0; i < 3; i++ {
+		elog.Info(msg, i)
+	}
+	elog.SetLevel("loud")
+	elog.SetFlags(ELJSONLog)
+	elog.Info("login", "user", "bob", "attempts")
+	elog.Infof("%d users", 1)
+	elog.Clear()
+	elog.Warn("late")
+
+	var warnings []string
+	for w := range r._misuse.warned {
+		warnings = append(warnings, w)
+	}
+	all := strings.Join(warnings, "\n")
+	if len(warnings) != 4 {
+		t.Errorf("expected 4 warnings, got:\n%s", all)
+	}
+	for _, expected := range []string{"format verbs in the message of Info, use Infof", `unknown level "loud"`,
+		`key "attempts" has no value`, "Warn called on the Elog \"TestMisuseWarnings\" after Clear"} {
+		if !strings.Contains(all, expected) {
+			t.Errorf("expected a warning %q, got:\n%s", expected, all)
+		}
+	}
+	if !strings.Contains(all, "misuse_test.go:") {
+		t.Errorf("expected the call sites, got:\n%s", all)
+	}
+}
+
+func TestMisusePairs(t *testing.T) {
+	r := NewRegistry()
+	r.SetMisuseWarnings(true)
+	elog := r.NewElog("TestMisusePairs", "info", io.Discard)
+	elog.InfoKVf("login", "user", "bob", Int("n", 1))
+	elog.SetFlags(ELStructuredLog)
+	elog.Warnf("login", "user", "bob", Int("n", 1), "attempts")
+	elog.TraceKVf("filtered", "user")
+
+	var warnings []string
+	for w := range r._misuse.warned {
+		warnings = append(warnings, w)
+	}
+	all := strings.Join(warnings, "\n")
+	if len(warnings) != 2 || !strings.Contains(all, `odd key/value count passed to Warnf, key "attempts" has no value`) ||
+		!strings.Contains(all, `passed to TraceKVf, key "user"`) {
+		t.Errorf("unexpected warnings:\n%s", all)
+	}
+}
+
+func TestClearDropsRecords(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestClearDropsRecords", "info", b)
+	elog.Clear()
+	r.SetGlobalLogLevel("trace")
+	elog.Info("x")
+	elog.Infof("x %d", 1)
+	elog.InfoKV("x", Int("n", 1))
+	elog.Print("x")
+	elog.Printf("x")
+	elog.Println("x")
+	elog.ReadOnly().Print("x")
+	if err := elog.Output(1, "info", "x"); err != nil || b.Len() > 0 {
+		t.Errorf("unexpected output %q %v", b.String(), err)
+	}
+}
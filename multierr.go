@@ -0,0 +1,54 @@
+package elogging
+
+import (
+	"strconv"
+	"strings"
+)
+
+// multiError is implemented by errors made of several errors (errors.Join, multi-error packages)
+type multiError interface {
+	Unwrap() []error
+}
+
+// _errorList return the constituents of a multi-error, nested multi-errors flattened, or nil for other errors
+func _errorList(err error) []error {
+	m, ok := err.(multiError)
+	if !ok {
+		return nil
+	}
+	var errs []error
+	for _, e := range m.Unwrap() {
+		if e == nil {
+			continue
+		}
+		if sub := _errorList(e); sub != nil {
+			errs = append(errs, sub...)
+		} else {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// _appendErrorLines append the constituents of a multi-error each on its own indented line:
+//  2 errors:
+//      - first error
+//      - second error
+func _appendErrorLines(buf []byte, errs []error) []byte {
+	buf = strconv.AppendInt(buf, int64(len(errs)), 10)
+	buf = append(buf, " errors:"...)
+	for _, err := range errs {
+		buf = append(buf, "\n    - "...)
+		buf = append(buf, strings.ReplaceAll(err.Error(), "\n", "\n      ")...)
+	}
+	return buf
+}
+
+// multiErrorArg render a multi-error argument of a logging call on indented lines
+type multiErrorArg struct {
+	errs []error
+}
+
+func (m multiErrorArg) Error() string {
+	return string(_appendErrorLines(nil, m.errs))
+}
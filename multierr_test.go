@@ -0,0 +1,37 @@
+package elogging
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type testMultiError []error
+
+func (m testMultiError) Error() string   { return "multiple errors" }
+func (m testMultiError) Unwrap() []error { return m }
+
+func TestMultiError(t *testing.T) {
+	err := testMultiError{errors.New("disk full"), testMultiError{errors.New("timeout"), errors.New("refused")}}
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestMultiError", "info", b)
+	elog.SetFlags(0)
+
+	elog.Error("save failed: ", err)
+	elog.ErrorKV("save failed", Err("err", err))
+	lines := "3 errors:\n    - disk full\n    - timeout\n    - refused\n"
+	if b.String() != "TestMultiError (ERROR) save failed: "+lines+"TestMultiError (ERROR) save failed err="+lines {
+		t.Errorf("unexpected text output %q", b.String())
+	}
+
+	b.Reset()
+	elog.SetFlags(ELJSONLog)
+	elog.ErrorKV("save failed", Err("err", err))
+	if !bytes.Contains(b.Bytes(), []byte(`"err":["disk full","timeout","refused"]`)) {
+		t.Errorf("unexpected json output %s", b.String())
+	}
+	f := Err("err", err)
+	if string(_appendValueRaw(nil, &f)) != "disk full; timeout; refused" {
+		t.Error("unexpected raw rendering")
+	}
+}
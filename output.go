@@ -0,0 +1,357 @@
+package elogging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// outputSpec is a parsed output description
+type outputSpec struct {
+	std    io.Writer // stdout or stderr, nil for a file
+	path   string
+	rotate int64 // rotation size, 0 for no rotation
+}
+
+// _parseOutput parse an output description: "stdout", "stderr", a file path (opened for append)
+// or file:path[?rotate=size] for a file rotated once it grows over size (e.g. 100MB)
+func _parseOutput(spec string) (o outputSpec, err error) {
+	switch strings.ToLower(spec) {
+	case "", "stdout", "-":
+		return outputSpec{std: os.Stdout}, nil
+	case "stderr":
+		return outputSpec{std: os.Stderr}, nil
+	}
+	if !strings.HasPrefix(spec, "file:") {
+		return outputSpec{path: spec}, nil
+	}
+	o.path = spec[len("file:"):]
+	query := ""
+	if i := strings.IndexByte(o.path, '?'); i >= 0 {
+		o.path, query = o.path[:i], o.path[i+1:]
+	}
+	if o.path == "" {
+		return o, fmt.Errorf("elogging: missing file path in output %q", spec)
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return o, fmt.Errorf("elogging: invalid output options %q: %w", query, err)
+	}
+	if rotate := values.Get("rotate"); rotate != "" {
+		if o.rotate, err = ParseSize(rotate); err != nil {
+			return o, err
+		}
+	}
+	return o, nil
+}
+
+// _openOutput resolve an output description (see _parseOutput) to a writer, files opened are owned by the registry
+func (r *Registry) _openOutput(spec string) (io.Writer, error) {
+	o, err := _parseOutput(spec)
+	if err != nil {
+		return nil, err
+	}
+	if o.std != nil {
+		return o.std, nil
+	}
+	return r._openFile(o)
+}
+
+// _sharedFile is a file output shared by the Elogs of a registry writing to the same path
+type _sharedFile struct {
+	w      io.WriteCloser
+	rotate int64
+}
+
+// _fileSet is the file outputs opened by a registry, keyed by absolute path
+type _fileSet struct {
+	mu    sync.Mutex
+	files map[string]_sharedFile
+}
+
+// _openFile open the file output of an output description, or return the writer already opened for its path so the
+// Elogs writing to a file share a single handle and rotation state
+func (r *Registry) _openFile(o outputSpec) (io.Writer, error) {
+	key := o.path
+	if abs, err := filepath.Abs(key); err == nil {
+		key = abs
+	}
+	r._files.mu.Lock()
+	defer r._files.mu.Unlock()
+	if f, ok := r._files.files[key]; ok {
+		if f.rotate != o.rotate {
+			return nil, fmt.Errorf("elogging: output %s already open with another rotation size", o.path)
+		}
+		return f.w, nil
+	}
+	var w io.WriteCloser
+	var err error
+	if o.rotate > 0 {
+		w, err = OpenRotatingFile(o.path, o.rotate)
+	} else {
+		var f *os.File
+		if f, err = _openAppend(o.path); err == nil {
+			w = _newOutputFile(f)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r._files.files == nil {
+		r._files.files = map[string]_sharedFile{}
+	}
+	r._files.files[key] = _sharedFile{w: w, rotate: o.rotate}
+	r._opened = append(r._opened, w)
+	return w, nil
+}
+
+// NewFileElog create an Elog in the registry writing to a file, see the package NewFileElog
+func (r *Registry) NewFileElog(scope, level, path string) (*Elog, error) {
+	o, err := _parseOutput(path)
+	if err != nil {
+		return nil, err
+	}
+	if o.std != nil {
+		return nil, fmt.Errorf("elogging: %q is not a file output", path)
+	}
+	out, err := r._openFile(o)
+	if err != nil {
+		return nil, err
+	}
+	return r.NewElog(scope, level, out), nil
+}
+
+// NewFileElog create an Elog writing to the file at path, a file path or file:path?rotate=size for a file rotated
+// once it grows over size (see NewElog for the scope and level). The Elogs of the registry writing to the same file
+// (created by NewFileElog or configured with a file output, the path cleaned and made absolute) share its handle and
+// rotation state: their records never interleave and the file is rotated once, e.g.
+//  db, _ := elogging.NewFileElog("db", "info", "file:/var/log/app.log?rotate=100MB")
+//  api, _ := elogging.NewFileElog("api", "info", "file:/var/log/app.log?rotate=100MB")
+// opening a file already open with another rotation size is an error. The file is closed by ClearAll.
+func NewFileElog(scope, level, path string) (*Elog, error) {
+	return _defaultRegistry.NewFileElog(scope, level, path)
+}
+
+func _openAppend(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("elogging: open output: %w", err)
+	}
+	return f, nil
+}
+
+// _outputFile is a file output opened by the package, active (see _setActive) until closed
+type _outputFile struct {
+	*os.File
+	once sync.Once
+}
+
+func _newOutputFile(f *os.File) *_outputFile {
+	_setActive(f.Name(), true)
+	return &_outputFile{File: f}
+}
+
+// Close close the file, which is no longer active
+func (f *_outputFile) Close() error {
+	f.once.Do(func() { _setActive(f.Name(), false) })
+	return f.File.Close()
+}
+
+var (
+	_activeMu    sync.Mutex
+	_activeFiles = map[string]int{}
+)
+
+// _setActive record that a file is (active true) or no longer (active false) written by the package,
+// active files are never pruned by the retention managers
+func _setActive(path string, active bool) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	_activeMu.Lock()
+	defer _activeMu.Unlock()
+	if active {
+		_activeFiles[path]++
+	} else if _activeFiles[path]--; _activeFiles[path] <= 0 {
+		delete(_activeFiles, path)
+	}
+}
+
+// _isActive report whether the file at the absolute path is written by the package
+func _isActive(path string) bool {
+	_activeMu.Lock()
+	defer _activeMu.Unlock()
+	return _activeFiles[path] > 0
+}
+
+// ParseSize parse a size such as 512, 64KB, 100MB or 1GB (binary multiples) into bytes
+func ParseSize(s string) (int64, error) {
+	u := strings.ToUpper(strings.TrimSpace(s))
+	mult := int64(1)
+	for _, m := range []struct {
+		suffix string
+		mult   int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30}, {"B", 1}} {
+		if strings.HasSuffix(u, m.suffix) {
+			u, mult = strings.TrimSpace(strings.TrimSuffix(u, m.suffix)), m.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(u, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("elogging: invalid size %q", s)
+	}
+	return n * mult, nil
+}
+
+// RotatingFile is a log file which is rotated once it grows over a size limit,
+// the rotated file is renamed with a timestamp suffix (path.20060102-150405.000[-n])
+type RotatingFile struct {
+	path    string
+	maxSize int64
+	mu      sync.Mutex
+	f       *os.File // nil when closed or when the reopen of a rotation failed
+	size    int64
+	closed  bool
+}
+
+// OpenRotatingFile open (or create) the file at path for append, rotating it when it grows over maxSize bytes
+func OpenRotatingFile(path string, maxSize int64) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxSize: maxSize}
+	if err := r._open(); err != nil {
+		return nil, err
+	}
+	_setActive(path, true)
+	return r, nil
+}
+
+func (r *RotatingFile) _open() error {
+	f, err := _openAppend(r.path)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("elogging: open output: %w", err)
+	}
+	r.f, r.size = f, fi.Size()
+	return nil
+}
+
+// Path return the path of the active file
+func (r *RotatingFile) Path() string {
+	return r.path
+}
+
+// Write append p to the file, rotating the file first if p does not fit in the size limit
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.f == nil {
+		if err := r._open(); err != nil { // the reopen of the last rotation failed, retried by every write
+			return 0, err
+		}
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r._rotate(); err != nil {
+			if r.f == nil {
+				return 0, err
+			}
+			_internalf("%s not rotated, writing on: %v", r.path, errors.Unwrap(err)) // retried by the next write
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Rotate force the rotation of the file
+func (r *RotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return os.ErrClosed
+	}
+	return r._rotate()
+}
+
+// _rename rename the rotated files, replaced by the tests
+var _rename = os.Rename
+
+// _rotate rename the file with a timestamp suffix and open a new one, the file is reopened when the rename fails
+// so the writer remains usable
+func (r *RotatingFile) _rotate() error {
+	if r.f != nil {
+		r.f.Close()
+		r.f = nil
+	}
+	rotated := r.path + "." + time.Now().Format("20060102-150405.000")
+	for i, base := 1, rotated; ; i++ {
+		if _, err := os.Stat(rotated); os.IsNotExist(err) {
+			break
+		}
+		rotated = base + "-" + strconv.Itoa(i)
+	}
+	if err := _rename(r.path, rotated); err != nil && !os.IsNotExist(err) {
+		if oerr := r._open(); oerr != nil {
+			return fmt.Errorf("elogging: rotate output: %w, reopen: %v", err, oerr)
+		}
+		return fmt.Errorf("elogging: rotate output: %w", err)
+	}
+	return r._open()
+}
+
+// Close close the active file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	_setActive(r.path, false)
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+// SetGlobalOutput replace the default output and move all the Elogs writing to the previous default output to out
+func SetGlobalOutput(out io.Writer) {
+	_defaultRegistry.SetGlobalOutput(out)
+}
+
+// SetGlobalOutput replace the default output of the registry and move its Elogs writing to the previous one to out
+func (r *Registry) SetGlobalOutput(out io.Writer) {
+	if !r._allow("", "default_output") {
+		return
+	}
+	r._audit("", "default_output", _describeOutput(r._defaultOut), _describeOutput(out))
+	prev := r._defaultOut
+	if prev == nil {
+		prev = os.Stdout
+	}
+	r._defaultOut = out
+	if out == nil {
+		out = os.Stdout
+	}
+	for k := range r._registered() {
+		if k._getOutput() == prev {
+			k._audit("output", _describeOutput(prev), _describeOutput(out))
+			k._setOutput(out)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package elogging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewFileElog(t *testing.T) {
+	r := NewRegistry()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	db, err := r.NewFileElog("TestNewFileElog.db", "info", "file:"+path+"?rotate=1KB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, err := r.NewFileElog("TestNewFileElog.api", "info", "file:"+dir+"/./app.log?rotate=1KB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db._out != api._out {
+		t.Fatal("expected the Elogs to share the file handle")
+	}
+	if _, err := r.NewFileElog("TestNewFileElog.web", "info", path); err == nil {
+		t.Error("expected an error for another rotation size")
+	}
+	for i := 0; i < 20; i++ {
+		db.Info("a record of the database layer, long enough to fill the file")
+		api.Info("a record of the api layer, long enough to fill the file")
+	}
+	r.ClearAll()
+	rotated, _ := filepath.Glob(path + ".*")
+	if len(rotated) == 0 {
+		t.Fatal("expected rotated files")
+	}
+	for _, p := range append(rotated, path) {
+		if fi, err := os.Stat(p); err != nil {
+			t.Error(err)
+		} else if fi.Size() > 1024 {
+			t.Errorf("expected %s rotated once over 1KB, got %d bytes", p, fi.Size())
+		}
+	}
+}
+
+func TestRotatingFileRenameFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenRotatingFile(path, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	defer func() { _rename = os.Rename }()
+	_rename = func(string, string) error { return errors.New("device busy") }
+
+	if err := f.Rotate(); err == nil || !strings.Contains(err.Error(), "device busy") {
+		t.Errorf("expected the rename error, got %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := f.Write([]byte(strings.Repeat("x", 60))); err != nil {
+			t.Fatalf("write after a failed rotation: %v", err)
+		}
+	}
+	_rename = os.Rename
+	if _, err := f.Write([]byte("y")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "y" {
+		t.Errorf("expected the rotation retried, active file %q", data)
+	}
+	if matches, _ := filepath.Glob(path + ".*"); len(matches) != 1 {
+		t.Errorf("expected a rotated file, got %v", matches)
+	}
+}
+
+func TestRotatingFileReopenFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenRotatingFile(path, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _rename = os.Rename }()
+	_rename = func(from, to string) error {
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+		return os.Mkdir(from, 0o700) // the active file can't be reopened
+	}
+
+	if err := f.Rotate(); err == nil {
+		t.Fatal("expected the reopen error")
+	}
+	if _, err := f.Write([]byte("lost")); err == nil {
+		t.Fatal("expected a write error while the file can't be reopened")
+	}
+	os.Remove(path)
+	if _, err := f.Write([]byte("y")); err != nil {
+		t.Fatalf("write not retrying the open: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "y" {
+		t.Errorf("unexpected active file %q", data)
+	}
+	f.Close()
+	if _, err := f.Write([]byte("z")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected a closed file error, got %v", err)
+	}
+}
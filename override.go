@@ -0,0 +1,98 @@
+package elogging
+
+// scopeOverride is an entry of the level override table of a registry
+type scopeOverride struct {
+	scope string // scope or scope pattern
+	level llevel
+}
+
+// SetScopeLevelOverride set the level of the scope in the override table of the registry, see the package SetScopeLevelOverride
+func (r *Registry) SetScopeLevelOverride(scope, level string) {
+	scope = r.ResolveScope(scope)
+	if !r._allow(scope, "level_override") {
+		return
+	}
+	l := _value(_valid(level))
+	r._audit(scope, "level_override", r.ScopeLevelOverrides()[scope], l.String())
+	r._removeOverride(scope)
+	r._overrides = append(r._overrides, scopeOverride{scope: scope, level: l})
+	r._applyOverrides()
+}
+
+// SetScopeLevelOverride set the level of every current and future Elog of the scope, whatever the level of the Elog
+// itself (given at creation or changed with SetLevel), until the override is removed. The override table belongs to
+// the registry: it survives Clear and the recreation of the Elogs, e.g. loggers created per connection. scope can be
+// a pattern (path.Match syntax) or a scope alias, the last override set matching a scope applies.
+func SetScopeLevelOverride(scope, level string) {
+	_defaultRegistry.SetScopeLevelOverride(scope, level)
+}
+
+// RemoveScopeLevelOverride remove the override of the scope from the table of the registry
+func (r *Registry) RemoveScopeLevelOverride(scope string) {
+	scope = r.ResolveScope(scope)
+	if !r._allow(scope, "level_override") {
+		return
+	}
+	r._audit(scope, "level_override", r.ScopeLevelOverrides()[scope], "")
+	r._removeOverride(scope)
+	r._applyOverrides()
+}
+
+// RemoveScopeLevelOverride remove the override of the scope (as given to SetScopeLevelOverride),
+// the Elogs of the scope are back to their own level
+func RemoveScopeLevelOverride(scope string) {
+	_defaultRegistry.RemoveScopeLevelOverride(scope)
+}
+
+// ScopeLevelOverrides return the override table of the registry, scope to level
+func (r *Registry) ScopeLevelOverrides() map[string]string {
+	overrides := make(map[string]string, len(r._overrides))
+	for _, o := range r._overrides {
+		overrides[o.scope] = o.level.String()
+	}
+	return overrides
+}
+
+// ScopeLevelOverrides return the level override table, scope to level
+func ScopeLevelOverrides() map[string]string {
+	return _defaultRegistry.ScopeLevelOverrides()
+}
+
+func (r *Registry) _removeOverride(scope string) {
+	for i, o := range r._overrides {
+		if o.scope == scope {
+			r._overrides = append(r._overrides[:i:i], r._overrides[i+1:]...)
+			return
+		}
+	}
+}
+
+// _applyOverrides apply the override table to the registered Elogs
+func (r *Registry) _applyOverrides() {
+	for e := range r._registered() {
+		r._applyOverride(e)
+	}
+	if r._stdLog != nil {
+		r._applyOverride(r._stdLog)
+	}
+}
+
+// _applyOverride set the level override of the Elog from the last matching entry of the table
+func (r *Registry) _applyOverride(e *Elog) {
+	scope := e._scope()
+	for i := len(r._overrides) - 1; i >= 0; i-- {
+		if o := r._overrides[i]; _matchScope(o.scope, scope) {
+			e._storeOverride(o.level)
+			return
+		}
+	}
+	e._storeOverride(_noOverride)
+}
+
+// _level return the level gating the records of the Elog, its override when set
+func (e *Elog) _level() llevel {
+	if o := e._overrideLevel(); o != _noOverride {
+		return o
+	}
+	return e._scopeLevel()
+}
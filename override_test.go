@@ -0,0 +1,51 @@
+package elogging
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestScopeLevelOverride(t *testing.T) {
+	r := NewRegistry()
+	conn := r.NewElog("TestOverride.conn", "info", io.Discard)
+	r.SetScopeLevelOverride("TestOverride.*", "trace")
+	if !conn._enabled(lTrace) {
+		t.Error("override not applied to the existing Elog")
+	}
+	conn.SetLevel("error")
+	if !conn._enabled(lTrace) || conn.GetLevel() != "Error" {
+		t.Error("override should take precedence over the Elog level")
+	}
+	if !strings.Contains(conn.Explain("trace"), "overridden by the override table to Trace") {
+		t.Errorf("unexpected explanation %q", conn.Explain("trace"))
+	}
+	conn.Clear()
+
+	conn = r.NewElog("TestOverride.conn", "info", io.Discard)
+	if !conn._enabled(lTrace) {
+		t.Error("override not applied to the recreated Elog")
+	}
+	r.SetScopeLevelOverride("TestOverride.conn", "warning")
+	if conn._enabled(lInfo) {
+		t.Error("last matching override should apply")
+	}
+	if o := r.ScopeLevelOverrides(); len(o) != 2 || o["TestOverride.conn"] != "Warning" {
+		t.Errorf("unexpected overrides %v", o)
+	}
+	r.RemoveScopeLevelOverride("TestOverride.conn")
+	r.RemoveScopeLevelOverride("TestOverride.*")
+	if conn._enabled(lVerbose) || !conn._enabled(lInfo) {
+		t.Error("Elog not back to its own level")
+	}
+
+	r.SetScopeLevelOverride("TestOverride.other", "trace")
+	conn.ModifyParams("TestOverride.other", "", nil)
+	if !conn._enabled(lTrace) {
+		t.Error("override not applied on scope change")
+	}
+	r.Reset()
+	if conn._enabled(lTrace) || len(r.ScopeLevelOverrides()) != 0 {
+		t.Error("overrides not reset")
+	}
+}
@@ -0,0 +1,212 @@
+package elogging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseLine parse a record written in the elogging text format back into a Record, e.g.
+//  2021/03/04 05:06:07.000008 /src/db/conn.go:42: db (WARN) slow query ms=120 table=users
+// The timestamp (date and time, RFC3339 or epoch milliseconds, read as UTC), the caller file, line and function,
+// the scope, the level tag (colors are ignored), the message and the trailing key=value pairs (as string fields)
+// are recovered, a checksum (ELChecksum) is dropped. A message ending with key=value pairs is read as fields.
+// Without a time flag the scope is only recovered when written after the caller info (log.Lmsgprefix, the default).
+// Records without a tag (ELSymbols, compatibility profiles) are reported as an error.
+func ParseLine(s string) (Record, error) {
+	var rec Record
+	s = strings.TrimRight(s, "\r\n")
+	if strings.IndexByte(s, '\x1b') >= 0 {
+		s = _stripANSI(s)
+	}
+	if i := strings.LastIndex(s, _crcText); i >= 0 && len(s)-i-len(_crcText) == 8 {
+		if _, err := strconv.ParseUint(s[i+len(_crcText):], 16, 32); err == nil {
+			s = s[:i]
+		}
+	}
+	open, end := _findTag(s)
+	if open < 0 {
+		return rec, fmt.Errorf("elogging: no level tag in %q", s)
+	}
+	rec.Tag = s[open+1 : end]
+	rec.level = _tagLevel(rec.Tag)
+	if err := _parseTextPrefix(s[:open], &rec); err != nil {
+		return rec, err
+	}
+	rec.Msg, rec.Fields = _parseTextFields(strings.TrimPrefix(s[end+1:], " "))
+	return rec, nil
+}
+
+// _findTag return the position of the parentheses of the level tag: " (TAG) " or "(TAG) " at the start of the line
+func _findTag(s string) (int, int) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '(' || (i > 0 && s[i-1] != ' ') {
+			continue
+		}
+		end := strings.IndexAny(s[i+1:], " ()")
+		if end > 0 && s[i+1+end] == ')' && (i+2+end == len(s) || s[i+2+end] == ' ') {
+			return i, i + 1 + end
+		}
+	}
+	return -1, -1
+}
+
+// _stripANSI remove the ANSI color sequences of a line
+func _stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '[' {
+			if end := strings.IndexByte(s[i:], 'm'); end > 0 {
+				i += end
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// _parseTextPrefix parse the part of a text record before its tag: scope, time, caller and scope (log.Lmsgprefix)
+func _parseTextPrefix(prefix string, rec *Record) error {
+	tokens := strings.Fields(prefix)
+	if len(tokens) > 0 && !_isTimeToken(tokens[0]) {
+		// scope written before the time (no log.Lmsgprefix)
+		for i := 1; i < len(tokens[0]); i++ {
+			if _isTimeToken(tokens[0][i:]) {
+				rec.Scope, tokens[0] = tokens[0][:i], tokens[0][i:]
+				break
+			}
+		}
+	}
+	var date, clock string
+	for len(tokens) > 0 && _isTimeToken(tokens[0]) {
+		switch t := tokens[0]; {
+		case len(t) == 10 && t[4] == '/':
+			date = t
+		case len(t) >= 8 && t[2] == ':':
+			clock = t
+		default:
+			ts, err := _replayTime(t)
+			if err != nil {
+				return fmt.Errorf("elogging: invalid time %q", t)
+			}
+			rec.Time = ts.UTC()
+		}
+		tokens = tokens[1:]
+	}
+	if date != "" || clock != "" {
+		layout, value := "", ""
+		if date != "" {
+			layout, value = "2006/01/02 ", date+" "
+		}
+		if clock != "" {
+			layout, value = layout+"15:04:05.999999999", value+clock
+		}
+		ts, err := time.Parse(strings.TrimSpace(layout), strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("elogging: invalid time %q", value)
+		}
+		rec.Time = ts
+	}
+	if len(tokens) > 0 && strings.HasSuffix(tokens[0], ":") {
+		t := strings.TrimSuffix(tokens[0], ":")
+		if i := strings.LastIndexByte(t, ':'); i > 0 {
+			if line, err := strconv.Atoi(t[i+1:]); err == nil {
+				rec.File, rec.Line = t[:i], line
+				tokens = tokens[1:]
+			}
+		}
+	}
+	if len(tokens) > 0 && strings.HasSuffix(tokens[0], ":") {
+		rec.Func = strings.TrimSuffix(tokens[0], ":")
+		tokens = tokens[1:]
+	}
+	switch {
+	case len(tokens) == 1 && rec.Scope == "":
+		rec.Scope = tokens[0]
+	case len(tokens) > 0:
+		return fmt.Errorf("elogging: unexpected %q before the level tag", strings.Join(tokens, " "))
+	}
+	return nil
+}
+
+// _isTimeToken report whether t is a timestamp part written by the text format: a date, a time, RFC3339 or epoch
+func _isTimeToken(t string) bool {
+	digits := func(s string) bool {
+		for _, c := range s {
+			if c < '0' || c > '9' {
+				return false
+			}
+		}
+		return s != ""
+	}
+	switch {
+	case len(t) == 10 && t[4] == '/' && t[7] == '/':
+		return digits(t[:4]) && digits(t[5:7]) && digits(t[8:])
+	case len(t) >= 8 && t[2] == ':' && t[5] == ':':
+		return digits(t[:2]) && digits(t[3:5]) && digits(strings.Replace(t[6:], ".", "", 1))
+	case len(t) >= 20 && t[4] == '-' && t[10] == 'T':
+		_, err := time.Parse(time.RFC3339Nano, t)
+		return err == nil
+	}
+	return len(t) >= 10 && digits(t)
+}
+
+// _parseTextFields split the text after the tag into the message and its trailing key=value fields
+func _parseTextFields(s string) (string, []FieldT) {
+	var fields []FieldT
+	run := -1
+	for p := 0; p < len(s); {
+		if s[p] == ' ' {
+			p++
+			continue
+		}
+		f, n, ok := _parseTextField(s[p:])
+		if !ok {
+			fields, run = fields[:0], -1
+			if sp := strings.IndexByte(s[p:], ' '); sp >= 0 {
+				p += sp
+			} else {
+				p = len(s)
+			}
+			continue
+		}
+		if run < 0 {
+			run = p
+		}
+		fields = append(fields, f)
+		p += n
+	}
+	if run < 0 {
+		return s, nil
+	}
+	return strings.TrimSuffix(s[:run], " "), fields
+}
+
+// _parseTextField parse a key=value pair (value quoted when needed) at the start of s, followed by a space or the end
+func _parseTextField(s string) (FieldT, int, bool) {
+	eq := strings.IndexByte(s, '=')
+	if eq <= 0 || strings.ContainsAny(s[:eq], " \"") {
+		return FieldT{}, 0, false
+	}
+	rest, value, n := s[eq+1:], "", 0
+	if strings.HasPrefix(rest, `"`) {
+		q, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return FieldT{}, 0, false
+		}
+		value, _ = strconv.Unquote(q)
+		n = len(q)
+	} else {
+		n = strings.IndexByte(rest, ' ')
+		if n < 0 {
+			n = len(rest)
+		}
+		value = rest[:n]
+	}
+	if n < len(rest) && rest[n] != ' ' {
+		return FieldT{}, 0, false
+	}
+	return String(s[:eq], value), eq + 1 + n, true
+}
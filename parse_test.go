@@ -0,0 +1,66 @@
+package elogging
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLineRoundTrip(t *testing.T) {
+	rec := Record{
+		Time:   time.Date(2021, 3, 4, 5, 6, 7, 8000, time.UTC),
+		Scope:  "db.conn",
+		Tag:    "WARN",
+		File:   "/src/db/conn.go",
+		Line:   42,
+		Func:   "db.(*Conn).Query",
+		Msg:    "slow query (retrying)",
+		Fields: []FieldT{Int("ms", 120), String("table", "user accounts"), String("q", `a "b"`)},
+		level:  lWarn,
+	}
+	for _, flags := range []int{
+		_initialFlags,
+		log.LstdFlags | log.Lmicroseconds | log.Lshortfile,
+		log.Ldate | log.Ltime | log.Lmicroseconds | log.Llongfile | ELFuncName | log.Lmsgprefix | ELColorLog,
+		log.LstdFlags | log.Lmsgprefix | ELChecksum,
+		log.Lmicroseconds | ELTimeRFC3339 | log.LUTC | log.Lmsgprefix | ELScopeColor,
+		log.Ltime | ELTimeEpoch | log.Llongfile,
+		log.Lmsgprefix,
+		0,
+	} {
+		line := string(_encodeFlags(nil, flags, &rec))
+		got, err := ParseLine(line)
+		if err != nil {
+			t.Errorf("%q: %v", line, err)
+			continue
+		}
+		again := string(_encodeFlags(nil, flags&^(ELColorLog|ELScopeColor), &got))
+		if stripped := _stripANSI(line); again != stripped {
+			t.Errorf("flags %#x: expected %q, got %q", flags, stripped, again)
+		}
+		if got.Scope != rec.Scope || got.Msg != rec.Msg || got.Level() != "Warning" || len(got.Fields) != 3 {
+			t.Errorf("flags %#x: unexpected record %+v", flags, got)
+		}
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestParseLine", "trace", b)
+	elog.InfoKV("done", String("user", "bob"))
+	elog.Println("plain")
+	elog.Trace("a=1 then text")
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	for i, expected := range []struct{ tag, msg string }{{"INFO", "done"}, {"Println", "plain"}, {"TRACE", "a=1 then text"}} {
+		rec, err := ParseLine(lines[i])
+		if err != nil || rec.Tag != expected.tag || rec.Msg != expected.msg || rec.Scope != "TestParseLine" ||
+			!strings.HasSuffix(rec.File, "parse_test.go") {
+			t.Errorf("%q: unexpected record %+v %v", lines[i], rec, err)
+		}
+	}
+	if _, err := ParseLine("no tag here"); err == nil {
+		t.Error("expected an error for a line without a tag")
+	}
+}
@@ -0,0 +1,57 @@
+package elogging
+
+import "time"
+
+// Record is a single log entry on its way to the output
+type Record struct {
+	Time   time.Time
+	Scope  string
+	Tag    string // the level tag (ERROR, WARN, INFO, VERBOSE, TRACE) or the call tag (Print, Printf, Println, Fatal, Panic)
+	File   string // caller file, only set when a caller flag is set
+	Line   int    // caller line, only set when a caller flag is set
+	Func   string // caller function, only set when a caller flag is set
+	Msg    string
+	Fields []FieldT // in the order given, see MapFields for maps
+	Labels []FieldT // the static labels of the Elog, shared: never modify them in place
+
+	level   llevel
+	bare    bool          // no level tag in text output, as written by the golang log package
+	raw     bool          // the message is written as is by the Elog output, see WriteRaw
+	version *versionField // see SetVersionField
+}
+
+// Level return the level name of the record (Print and Fatal for the records not subject to leveling)
+func (r *Record) Level() string {
+	return r.level.String()
+}
+
+// Clone return a deep copy of the record, safe to keep or modify after the hook it was given to returned
+func (r *Record) Clone() *Record {
+	c := *r
+	if r.Fields != nil {
+		c.Fields = append([]FieldT(nil), r.Fields...)
+	}
+	return &c
+}
+
+// Hook is called with every record emitted by an Elog, before the record is rendered and written.
+//
+// Hooks run synchronously in the order they were added, a hook may modify the record and the modifications
+// are seen by the following hooks and by the output. The record and its Fields slice belong to the caller:
+// a hook must not keep them once it returns nor modify the Fields elements in place (assign a new slice instead),
+// a hook forwarding records elsewhere (another sink, a queue) must forward rec.Clone().
+type Hook func(rec *Record)
+
+// AddHook add a hook called for every record emitted by the Elog
+func (e *Elog) AddHook(h Hook) {
+	e._conf.Lock()
+	e._hooks = append(e._hooks, h)
+	e._conf.Unlock()
+}
+
+// ClearHooks remove all the hooks of the Elog
+func (e *Elog) ClearHooks() {
+	e._conf.Lock()
+	e._hooks = nil
+	e._conf.Unlock()
+}
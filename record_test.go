@@ -0,0 +1,32 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHooksAndClone(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewElog("TestHooks", "info", b)
+	defer elog.Clear()
+	elog.SetFlags(0)
+
+	var forwarded []*Record
+	elog.AddHook(func(rec *Record) {
+		forwarded = append(forwarded, rec.Clone())
+	})
+	elog.AddHook(func(rec *Record) {
+		rec.Msg = strings.ToUpper(rec.Msg)
+		rec.Fields = append(rec.Fields[:len(rec.Fields):len(rec.Fields)], String("hooked", "yes"))
+	})
+	elog.InfoKV("shout", Int("n", 1))
+	elog.Verbose("filtered")
+
+	if b.String() != "TestHooks (INFO) SHOUT n=1 hooked=yes\n" {
+		t.Errorf("unexpected hooked output %q", b.String())
+	}
+	if len(forwarded) != 1 || forwarded[0].Msg != "shout" || len(forwarded[0].Fields) != 1 || forwarded[0].Level() != "Info" {
+		t.Errorf("forwarded record affected by later hooks %+v", forwarded)
+	}
+}
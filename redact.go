@@ -0,0 +1,57 @@
+package elogging
+
+// Redactor is implemented by types holding sensitive data (tokens, card numbers, credentials) to declare
+// their own safe rendering, used instead of their value (and of their String or Error methods) by all the
+// encoders, in fields as well as in the arguments of the logging calls
+type Redactor interface {
+	Redacted() string
+}
+
+// _redacted return the safe rendering of a field value implementing Redactor
+func (f *FieldT) _redacted() (string, bool) {
+	if f.kind != kindAny && f.kind != kindError {
+		return "", false
+	}
+	r, ok := f.any.(Redactor)
+	if !ok {
+		return "", false
+	}
+	return r.Redacted(), true
+}
+
+// renderedArg is the replacement rendering of an argument of a logging call
+type renderedArg string
+
+func (r renderedArg) String() string {
+	return string(r)
+}
+
+// _renderArgs return args with its Redactor arguments replaced by their safe rendering, its []byte arguments
+// by their rendering (see SetBytesRendering, %v only) and its multi-error arguments by their indented rendering,
+// args itself when it has none
+func (r *Registry) _renderArgs(args []interface{}) []interface{} {
+	var out []interface{}
+	for i, arg := range args {
+		var rendered interface{}
+		if red, ok := arg.(Redactor); ok {
+			rendered = renderedArg(red.Redacted())
+		} else if b, ok := arg.([]byte); ok && r._bytesMode != BytesRaw {
+			rendered = bytesArg{b: b, rendered: r._renderBytes(b)}
+		} else if err, ok := arg.(error); ok {
+			if errs := _errorList(err); errs != nil {
+				rendered = multiErrorArg{errs}
+			}
+		}
+		if rendered == nil {
+			continue
+		}
+		if out == nil {
+			out = append([]interface{}(nil), args...)
+		}
+		out[i] = rendered
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
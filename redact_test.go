@@ -0,0 +1,56 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type testToken string
+
+func (t testToken) String() string   { return string(t) }
+func (t testToken) Redacted() string { return "tok_****" + string(t[len(t)-2:]) }
+
+func TestRedactor(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestRedactor", "info", b)
+	elog.SetFlags(0)
+	token := testToken("secret42")
+
+	elog.Info("token ", token)
+	elog.Infof("token %v", token)
+	elog.InfoKV("login", Field("token", token))
+	elog.SetFlags(ELJSONLog)
+	elog.InfoKV("login", Any("token", token))
+
+	out := b.String()
+	if strings.Contains(out, "secret") || strings.Count(out, "tok_****42") != 4 {
+		t.Errorf("unexpected output %q", out)
+	}
+}
+
+func TestRedactorPrintAndCapture(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestRedactorPrintAndCapture", "info", b)
+	elog.SetFlags(0)
+	token := testToken("secret42")
+
+	elog.Print("print ", token)
+	elog.Printf("printf %v", token)
+	elog.Println("println", token)
+	elog.ReadOnly().Print("view ", token)
+	c := StartCapture("trace")
+	elog.Trace("dropped ", token)
+	elog.Tracef("dropped %v", token)
+	c.Stop()
+
+	defer _defaultRegistry._stdLog.ModifyParams("", "", _defaultRegistry._stdLog._out)
+	_defaultRegistry._stdLog.ModifyParams("", "info", b)
+	Print("std ", token)
+	Printf("std %s", token)
+
+	out := b.String() + c.String()
+	if strings.Contains(out, "secret") || strings.Count(out, "tok_****42") != 8 {
+		t.Errorf("unexpected output %q", out)
+	}
+}
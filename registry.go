@@ -0,0 +1,305 @@
+package elogging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+	"sync/atomic"
+)
+
+// Registry is an independent set of Elogs with its own defaults (flags, output, level), global level,
+// package default log and settings (limit, duplicate scope policy, aliases, ring buffer),
+// e.g. for a plugin host, a test harness or a multi-tenant engine embedding elogging more than once.
+// The package functions operate on the default registry.
+type Registry struct {
+	_logs         map[*Elog]string
+	_logsMu       sync.RWMutex // guards _logs
+	_logsOff      int32        // the logs are off, see LogsOff
+	_silence      int32        // count of the WithSilence calls in progress
+	_logsOffFloor llevel       // records at or above it are still emitted while the logs are off, see LogsOffExcept
+	_globalLevel  llevel
+	_globalMode   GlobalLevelMode
+	_defaultLevel llevel
+	_defaultFlags int
+	_defaultOut   io.Writer
+	_stdLog       *Elog
+
+	_warn      int
+	_warned    bool
+	_limit     int
+	_limitHit  bool
+	_evictions int
+
+	_defaultFields   []FieldT
+	_scopeNaming     *regexp.Regexp
+	_duplicatePolicy DuplicateScopePolicy
+	_aliases         map[string]string
+	_opened          []io.Closer // outputs opened by the registry (configuration, flags), closed by ClearAll
+	_files           _fileSet    // file outputs shared by path
+	_scopeDir        outputSpec  // directory of the scope files, see SetScopeFileDir
+	_ring            *ringBuffer
+	_bootstrap       atomic.Value // *_bootstrapBuffer, startup records held until the first sink, see EnableBootstrapBuffer
+	_traceFilter     []string
+	_schemas         map[string]*Schema
+	_schemaSeen      _violationSet
+	_keyCase         KeyCase
+	_keyCollision    KeyCollision
+	_bytesMode       BytesRendering
+	_version         atomic.Value // *versionField, see SetVersionField
+	_bytesMax        int
+	_utf8Repair      bool
+	_debug           *Elog // diagnostics of the registry, nil when off
+	_auditLog        *Elog // configuration changes of the registry, nil when off
+	_freezeMu        sync.Mutex
+	_freeze          *FreezeToken
+	_thawed          map[uint64]int // goroutines running FreezeToken.Do, their changes are allowed
+	_overrides       []scopeOverride
+	_periodic        _periodicSet // periodic tasks, stopped by Shutdown
+	_closers         _closerSet   // closers run by Shutdown
+	_suppression     SuppressionPolicy
+	_misuse          *_misuseSet // call sites warned about a misuse, nil when not detected, see SetMisuseWarnings
+	_traceExtractor  TraceExtractor
+	_tenantExtractor TenantExtractor
+	_traceSampling   bool
+}
+
+// _defaultRegistry is the registry the package functions operate on
+var _defaultRegistry = NewRegistry()
+
+// NewRegistry create an empty registry with the package initial defaults
+func NewRegistry() *Registry {
+	r := &Registry{_logs: map[*Elog]string{}}
+	r.Reset()
+	return r
+}
+
+// DefaultRegistry return the registry the package functions operate on
+func DefaultRegistry() *Registry {
+	return _defaultRegistry
+}
+
+// _internalf report a problem of the logging library itself on stderr
+func _internalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "elogging: "+format+"\n", args...)
+}
+
+// _register add the Elog to the registry of scoped logs
+func (r *Registry) _register(e *Elog) {
+	r._logsMu.Lock()
+	defer r._logsMu.Unlock()
+	if r._limit > 0 {
+		for len(r._logs) >= r._limit {
+			r._evictLRU()
+		}
+	}
+	r._logs[e] = e._scope()
+	if r._warn > 0 && len(r._logs) > r._warn && !r._warned {
+		r._warned = true
+		_internalf("%d registered logs exceed the warning threshold (%d), Elogs not cleared or NewEphemeralElog not used?",
+			len(r._logs), r._warn)
+	}
+}
+
+// SetDefaultFields set the fields emitted with every record of the Elogs created afterwards by the registry
+// (e.g. service, team, environment), ahead of the record own fields; no fields remove them
+func (r *Registry) SetDefaultFields(fields ...FieldT) {
+	r._defaultFields = append([]FieldT(nil), fields...)
+}
+
+// DefaultFields return the fields set by SetDefaultFields
+func (r *Registry) DefaultFields() []FieldT {
+	return append([]FieldT(nil), r._defaultFields...)
+}
+
+// SetScopeNaming enforce a scope naming convention: the scope of every Elog created by the registry must match
+// the regular expression (e.g. `^[a-z]+(\.[a-z]+)*$`), an empty pattern remove the convention.
+// A non conforming Elog is still created (logging never fails) and reported on stderr, use CheckScope to reject it.
+func (r *Registry) SetScopeNaming(pattern string) error {
+	if pattern == "" {
+		r._scopeNaming = nil
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("elogging: invalid scope naming pattern: %w", err)
+	}
+	r._scopeNaming = re
+	return nil
+}
+
+// CheckScope report whether the scope follows the naming convention of the registry (see SetScopeNaming)
+func (r *Registry) CheckScope(scope string) error {
+	if r._scopeNaming != nil && !r._scopeNaming.MatchString(scope) {
+		return fmt.Errorf("elogging: scope %q does not match the naming convention %s", scope, r._scopeNaming)
+	}
+	return nil
+}
+
+// NewEphemeralElog create an Elog with the registry defaults without registering it
+func (r *Registry) NewEphemeralElog(scope, level string, out io.Writer) *Elog {
+	return r._newElog(scope, level, out)
+}
+
+// NewEphemeralElog create an Elog like NewElog but without registering it, it is not listed nor reachable
+// through the package functions and is garbage collected as soon as it is no longer referenced,
+// intended for short lived loggers (per connection, per request)
+func NewEphemeralElog(scope, level string, out io.Writer) *Elog {
+	return _defaultRegistry.NewEphemeralElog(scope, level, out)
+}
+
+// Size return the number of Elogs registered in the registry
+func (r *Registry) Size() int {
+	r._logsMu.RLock()
+	defer r._logsMu.RUnlock()
+	return len(r._logs)
+}
+
+// RegistrySize return the number of registered Elogs
+func RegistrySize() int {
+	return _defaultRegistry.Size()
+}
+
+// SetWarnThreshold report once on stderr when the number of Elogs of the registry grows over n (0 disable the check)
+func (r *Registry) SetWarnThreshold(n int) {
+	r._logsMu.Lock()
+	defer r._logsMu.Unlock()
+	r._warn = n
+	r._warned = false
+}
+
+// SetRegistryWarnThreshold report once on stderr when the number of registered Elogs grows over n (0 disable the check),
+// a registry growing without bounds usually means Elogs created per request and never cleared
+func SetRegistryWarnThreshold(n int) {
+	_defaultRegistry.SetWarnThreshold(n)
+}
+
+// SetLimit cap the number of Elogs of the registry to n (0 for no limit), see SetRegistryLimit
+func (r *Registry) SetLimit(n int) {
+	r._logsMu.Lock()
+	defer r._logsMu.Unlock()
+	r._limit = n
+	r._limitHit = false
+	if n > 0 {
+		for len(r._logs) > n {
+			r._evictLRU()
+		}
+	}
+}
+
+// SetRegistryLimit cap the number of registered Elogs to n (0 for no limit), when the limit is reached
+// the least recently active Elog is unregistered to make room for a new one; the evicted Elog remains usable
+// but is no longer listed nor reachable through the package functions
+func SetRegistryLimit(n int) {
+	_defaultRegistry.SetLimit(n)
+}
+
+// Evictions return the number of Elogs unregistered because of the registry limit
+func (r *Registry) Evictions() int {
+	r._logsMu.RLock()
+	defer r._logsMu.RUnlock()
+	return r._evictions
+}
+
+// RegistryEvictions return the number of Elogs unregistered because of the registry limit
+func RegistryEvictions() int {
+	return _defaultRegistry.Evictions()
+}
+
+// _evictLRU unregister the Elog with the oldest last activity, the registry lock is held
+func (r *Registry) _evictLRU() {
+	var lru *Elog
+	for k := range r._logs {
+		if lru == nil || k.LastActive().Before(lru.LastActive()) {
+			lru = k
+		}
+	}
+	if lru == nil {
+		return
+	}
+	delete(r._logs, lru)
+	r._evictions++
+	if !r._limitHit {
+		r._limitHit = true
+		_internalf("registry limit (%d) reached, evicting least recently active logs (first: %s)", r._limit, lru)
+	}
+}
+
+// DuplicateScopePolicy decide what NewElog does when the requested scope is already registered
+type DuplicateScopePolicy int
+
+const (
+	DuplicateAllow  DuplicateScopePolicy = iota // create another Elog with the same scope (default)
+	DuplicateReuse                              // return the registered Elog, level and out are ignored
+	DuplicateSuffix                             // create an Elog with a counter suffixed scope: scope#2, scope#3, ...
+)
+
+// SetDuplicateScopePolicy change the policy applied by NewElog of the registry to already registered scopes
+func (r *Registry) SetDuplicateScopePolicy(policy DuplicateScopePolicy) {
+	r._duplicatePolicy = policy
+}
+
+// SetDuplicateScopePolicy change the policy applied by NewElog to already registered scopes
+func SetDuplicateScopePolicy(policy DuplicateScopePolicy) {
+	_defaultRegistry.SetDuplicateScopePolicy(policy)
+}
+
+// GetOrCreateElog return the Elog of the registry with the given scope (or scope alias), or create it with the defaults
+func (r *Registry) GetOrCreateElog(scope string) *Elog {
+	scope = r.ResolveScope(scope)
+	if e := r._findScope(scope); e != nil {
+		return e
+	}
+	return r.NewElogDefaults(scope)
+}
+
+// GetOrCreateElog return the registered Elog with the given scope (or scope alias), or create it with the defaults
+func GetOrCreateElog(scope string) *Elog {
+	return _defaultRegistry.GetOrCreateElog(scope)
+}
+
+// _findScope return the first registered Elog (in listing order) with the given scope
+func (r *Registry) _findScope(scope string) *Elog {
+	var found *Elog
+	for k, v := range r._registered() {
+		if v == scope && (found == nil || k.String() < found.String()) {
+			found = k
+		}
+	}
+	return found
+}
+
+// AliasScope register alias as a short name for scope in the registry
+func (r *Registry) AliasScope(alias, scope string) {
+	r._aliases[alias] = scope
+}
+
+// AliasScope register alias as a short name for scope, aliases are accepted wherever a scope is given
+// to the package functions (SetScopeLogLevel, GetOrCreateElog, configuration rules and flags)
+func AliasScope(alias, scope string) {
+	_defaultRegistry.AliasScope(alias, scope)
+}
+
+// RemoveScopeAlias remove an alias registered in the registry
+func (r *Registry) RemoveScopeAlias(alias string) {
+	delete(r._aliases, alias)
+}
+
+// RemoveScopeAlias remove a registered alias
+func RemoveScopeAlias(alias string) {
+	_defaultRegistry.RemoveScopeAlias(alias)
+}
+
+// ResolveScope return the scope the name is an alias of in the registry, or the name itself
+func (r *Registry) ResolveScope(name string) string {
+	if scope, ok := r._aliases[name]; ok {
+		return scope
+	}
+	return name
+}
+
+// ResolveScope return the scope the name is an alias of, or the name itself when it is not an alias
+func ResolveScope(name string) string {
+	return _defaultRegistry.ResolveScope(name)
+}
@@ -0,0 +1,128 @@
+package elogging
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestEphemeralElog(t *testing.T) {
+	size := RegistrySize()
+	elog := NewEphemeralElog("TestEphemeralElog", "info", nil)
+	if RegistrySize() != size || GetScopedLogByID(elog.ID()) != nil {
+		t.Error("ephemeral elog must not be registered")
+	}
+	elog.Clear()
+}
+
+func TestRegistryLimit(t *testing.T) {
+	for _, e := range ListScopedLogs() {
+		defer _defaultRegistry._register(e)
+	}
+	_defaultRegistry._logs = map[*Elog]string{}
+	defer SetRegistryLimit(0)
+
+	SetRegistryLimit(2)
+	a := NewElog("TestRegistryLimit.a", "info", &bytes.Buffer{})
+	b := NewElog("TestRegistryLimit.b", "info", &bytes.Buffer{})
+	a.Info("a is active")
+	c := NewElog("TestRegistryLimit.c", "info", &bytes.Buffer{})
+	defer a.Clear()
+	defer c.Clear()
+
+	if RegistrySize() != 2 || GetScopedLogByID(b.ID()) != nil || GetScopedLogByID(a.ID()) == nil {
+		t.Errorf("expected b to be evicted, registry: %v", ListScopedLogs())
+	}
+	if RegistryEvictions() == 0 {
+		t.Error("expected eviction count")
+	}
+}
+
+func TestDuplicateScopePolicy(t *testing.T) {
+	defer SetDuplicateScopePolicy(DuplicateAllow)
+
+	a := GetOrCreateElog("TestDuplicateScope")
+	defer a.Clear()
+	if GetOrCreateElog("TestDuplicateScope") != a {
+		t.Error("expected the existing elog")
+	}
+
+	SetDuplicateScopePolicy(DuplicateReuse)
+	if NewElog("TestDuplicateScope", "trace", nil) != a {
+		t.Error("expected reuse of the existing elog")
+	}
+
+	SetDuplicateScopePolicy(DuplicateSuffix)
+	b := NewElog("TestDuplicateScope", "info", nil)
+	defer b.Clear()
+	c := NewElog("TestDuplicateScope", "info", nil)
+	defer c.Clear()
+	if b.Scope() != "TestDuplicateScope#2" || c.Scope() != "TestDuplicateScope#3" {
+		t.Errorf("unexpected suffixed scopes %s %s", b.Scope(), c.Scope())
+	}
+}
+
+func TestAliasScope(t *testing.T) {
+	AliasScope("nw", "TestAliasScope.network.transport")
+	defer RemoveScopeAlias("nw")
+
+	elog := GetOrCreateElog("nw")
+	defer elog.Clear()
+	if elog.Scope() != "TestAliasScope.network.transport" {
+		t.Errorf("expected aliased scope, got %s", elog.Scope())
+	}
+	if err := Configure("nw=trace"); err != nil {
+		t.Fatal(err)
+	}
+	if elog.GetLevel() != "Trace" {
+		t.Errorf("expected level set through the alias, got %s", elog.GetLevel())
+	}
+}
+
+func TestRegistryIsolation(t *testing.T) {
+	r := NewRegistry()
+	r.SetDefaultFlags(log.Lmsgprefix)
+	r.SetDefaultLevel("error")
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestRegistryIsolation", "", b)
+
+	if r.Size() != 1 || GetScopedLogByID(elog.ID()) != nil {
+		t.Error("expected the elog in the new registry only")
+	}
+	if DefaultFlags() == log.Lmsgprefix || DefaultLevel() != "Info" {
+		t.Error("default registry settings changed")
+	}
+	SetGlobalLogLevel("trace")
+	defer SetGlobalLogLevel("disabled")
+	elog.Info("hidden")
+	r.SetScopeLogLevel("TestRegistryIsolation", "info")
+	elog.Info("shown")
+	r.LogsOff()
+	elog.Error("hidden")
+	if b.String() != "TestRegistryIsolation (INFO) shown\n" {
+		t.Errorf("unexpected output %q", b.String())
+	}
+}
+
+func TestRegistryConventions(t *testing.T) {
+	r := NewRegistry()
+	r.SetDefaultFlags(ELJSONLog)
+	r.SetDefaultFields(String("team", "payments"))
+	if err := r.SetScopeNaming(`^[a-z]+(\.[a-z]+)*$`); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetScopeNaming(`(`); err == nil {
+		t.Error("expected an invalid pattern error")
+	}
+	if r.CheckScope("billing.invoices") != nil || r.CheckScope("Billing_Invoices") == nil {
+		t.Error("unexpected naming check result")
+	}
+
+	b := &bytes.Buffer{}
+	elog := r.NewElog("billing", "info", b)
+	elog.InfoKV("paid", Int("amount", 3))
+	if !strings.Contains(b.String(), `"team":"payments","amount":3`) {
+		t.Errorf("expected the default fields first, got %s", b.String())
+	}
+}
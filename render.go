@@ -0,0 +1,47 @@
+package elogging
+
+import "fmt"
+
+// Render return the line a record of the given level, message and key/value pairs would be written as by the Elog,
+// with its current format, flags, fields, labels and hooks, without writing it nor sending it to the sinks,
+// e.g. for the previews of an admin UI or the tests of a custom format:
+//  line, err := elog.Render("info", "user logged in", "user", "bob", "attempts", 3)
+// kv holds key/value pairs (the values typed as with Field) and FieldT values, the levels and LogsOff do not apply.
+// The caller is rendered as Render's caller and the time is the current time.
+func (e *Elog) Render(level string, msg string, kv ...interface{}) (string, error) {
+	l, err := _parseLevel(level)
+	if err != nil || l == lDisabled {
+		return "", fmt.Errorf("elogging: invalid render level %q", level)
+	}
+	fields, err := _kvFields(kv)
+	if err != nil {
+		return "", err
+	}
+	tag := _valid(l.String())
+	if l == lPrint {
+		tag = ""
+	}
+	rec := e._record(2, l, tag, msg, fields)
+	e._prepare(&rec)
+	return string(e._format(&rec)), nil
+}
+
+// _kvFields convert key/value pairs and fields to fields
+func _kvFields(kv []interface{}) ([]FieldT, error) {
+	var fields []FieldT
+	for i := 0; i < len(kv); i++ {
+		switch x := kv[i].(type) {
+		case FieldT:
+			fields = append(fields, x)
+		case string:
+			if i+1 == len(kv) {
+				return nil, fmt.Errorf("elogging: key %q without value", x)
+			}
+			fields = append(fields, Field(x, kv[i+1]))
+			i++
+		default:
+			return nil, fmt.Errorf("elogging: invalid key %v (%T) at %d", x, x, i)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,34 @@
+package elogging
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestRender", "error", b)
+	for _, flags := range []int{0, ELJSONLog} {
+		elog.SetFlags(flags)
+		line, err := elog.Render("info", "user logged in", "user", "bob", "attempts", 3, Bool("admin", true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Len() != 0 {
+			t.Errorf("render wrote %q", b.String())
+		}
+		elog.SetLevel("info")
+		elog.InfoKV("user logged in", String("user", "bob"), Int("attempts", 3), Bool("admin", true))
+		if line != b.String() {
+			t.Errorf("flags %d: rendered %q, written %q", flags, line, b.String())
+		}
+		elog.SetLevel("error")
+		b.Reset()
+	}
+	if _, err := elog.Render("info", "msg", "user"); err == nil {
+		t.Error("expected an error for a key without value")
+	}
+	if _, err := elog.Render("loud", "msg"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
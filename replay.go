@@ -0,0 +1,286 @@
+package elogging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format identify the format of persisted records read by Replay
+type Format int
+
+const (
+	FormatJSON   Format = iota // one JSON object per line, as written with ELJSONLog
+	FormatLogfmt               // one line of key=value pairs per record, e.g. time=... level=INFO scope=db msg="..." user=bob
+	FormatText                 // the elogging text format, see ParseLine
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatLogfmt:
+		return "logfmt"
+	case FormatText:
+		return "text"
+	}
+	return "json"
+}
+
+// Replay parse the records previously written to r in the given format and re-emit them through the sink, e.g. to
+// backfill a new log backend from existing files with the same sink (and field mapping) as the live records.
+// Text records are parsed with ParseLine, for JSON and logfmt the time, scope, level, file, line, func and msg keys
+// (ts, lvl and message are accepted too) are mapped to the record, the other keys become its fields in their order
+// (the JSON "tags" and "fields" objects written with ELNestFields become the labels and the fields of the record,
+// the other objects become groups).
+// Empty lines are skipped, a malformed line stops the replay with an error giving its line number.
+// The count of records replayed is returned.
+func Replay(r io.Reader, format Format, sink Sink) (int, error) {
+	parse := _parseJSONRecord
+	switch format {
+	case FormatLogfmt:
+		parse = _parseLogfmtRecord
+	case FormatText:
+		parse = _parseTextRecord
+	}
+	version := _defaultRegistry._getVersionField()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	n := 0
+	for line := 1; sc.Scan(); line++ {
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		rec := Record{Tag: _valid(LEVEL_Info), level: lInfo}
+		if err := parse(b, &rec); err != nil {
+			return n, fmt.Errorf("elogging: replay line %d: %w", line, err)
+		}
+		_takeVersionField(&rec, version)
+		if err := sink.WriteRecord(&rec); err != nil {
+			return n, fmt.Errorf("elogging: replay line %d: %w", line, err)
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		return n, fmt.Errorf("elogging: replay: %w", err)
+	}
+	return n, nil
+}
+
+// _tagLevel return the level of a record tag, a level name or a call tag (Print, Fatal, ...)
+func _tagLevel(tag string) llevel {
+	switch tag {
+	case "Print", "Printf", "Println":
+		return lPrint
+	case "Fatal", "Panic":
+		return lFatal
+	}
+	if l := _value(tag); l != lDisabled {
+		return l
+	}
+	return lInfo
+}
+
+// _replayTime parse a persisted record time, RFC3339 or epoch milliseconds (nanoseconds from 17 digits on)
+func _replayTime(s string) (time.Time, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if len(s) >= 17 {
+			return time.Unix(0, n), nil
+		}
+		return time.UnixMilli(n), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// _setReplayKey map a well known key to the record, false for the keys which are record fields
+func _setReplayKey(rec *Record, key, value string) (bool, error) {
+	switch key {
+	case "time", "ts":
+		t, err := _replayTime(value)
+		if err != nil {
+			return true, fmt.Errorf("invalid time %q", value)
+		}
+		rec.Time = t
+	case "scope":
+		rec.Scope = value
+	case "level", "lvl":
+		rec.Tag, rec.level = value, _tagLevel(value)
+	case "file":
+		rec.File = value
+	case "line":
+		rec.Line, _ = strconv.Atoi(value)
+	case "func":
+		rec.Func = value
+	case "msg", "message":
+		rec.Msg = value
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// _parseJSONRecord parse a JSON object into the record, keeping the order of its fields
+func _parseJSONRecord(b []byte, rec *Record) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return fmt.Errorf("not a JSON object")
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := t.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		if _isJSONObject(raw) {
+			nested, err := _parseJSONFields(raw)
+			if err != nil {
+				return err
+			}
+			switch key {
+			case "tags":
+				rec.Labels = append(rec.Labels, nested...)
+			case "fields":
+				rec.Fields = append(rec.Fields, nested...)
+			default:
+				rec.Fields = append(rec.Fields, Group(key, nested...))
+			}
+			continue
+		}
+		v, err := _decodeJSONValue(raw)
+		if err != nil {
+			return err
+		}
+		if s, ok := v.(string); ok {
+			if known, err := _setReplayKey(rec, key, s); known {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		} else if n, ok := v.(json.Number); ok && (key == "time" || key == "ts" || key == "line") {
+			if _, err := _setReplayKey(rec, key, n.String()); err != nil {
+				return err
+			}
+			continue
+		}
+		rec.Fields = append(rec.Fields, _jsonField(key, v))
+	}
+	return nil
+}
+
+// _isJSONObject report whether the raw JSON value is an object
+func _isJSONObject(raw json.RawMessage) bool {
+	return len(raw) > 0 && raw[0] == '{'
+}
+
+// _decodeJSONValue decode a raw JSON value, numbers are kept as json.Number
+func _decodeJSONValue(raw json.RawMessage) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	err := dec.Decode(&v)
+	return v, err
+}
+
+// _parseJSONFields parse the members of a JSON object into fields, in order (see ELNestFields),
+// nested objects become groups
+func _parseJSONFields(b []byte) ([]FieldT, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+	var fields []FieldT
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		if _isJSONObject(raw) {
+			nested, err := _parseJSONFields(raw)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, Group(t.(string), nested...))
+			continue
+		}
+		v, err := _decodeJSONValue(raw)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, _jsonField(t.(string), v))
+	}
+	return fields, nil
+}
+
+// _jsonField create a field for a decoded JSON value
+func _jsonField(key string, v interface{}) FieldT {
+	switch x := v.(type) {
+	case string:
+		return String(key, x)
+	case bool:
+		return Bool(key, x)
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return Int64(key, i)
+		}
+		if f, err := x.Float64(); err == nil {
+			return Float64(key, f)
+		}
+		return String(key, x.String())
+	}
+	return Any(key, v)
+}
+
+// _parseLogfmtRecord parse a line of key=value pairs into the record, quoted values are unquoted as Go strings
+func _parseLogfmtRecord(b []byte, rec *Record) error {
+	s := string(b)
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 || strings.ContainsAny(s[:eq], " \t") {
+			return fmt.Errorf("expected key=value at %q", s)
+		}
+		key, value := s[:eq], ""
+		s = s[eq+1:]
+		if strings.HasPrefix(s, `"`) {
+			q, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return fmt.Errorf("invalid quoted value of %q", key)
+			}
+			value, _ = strconv.Unquote(q)
+			s = s[len(q):]
+		} else if sp := strings.IndexAny(s, " \t"); sp >= 0 {
+			value, s = s[:sp], s[sp:]
+		} else {
+			value, s = s, ""
+		}
+		s = strings.TrimLeft(s, " \t")
+		known, err := _setReplayKey(rec, key, value)
+		if err != nil {
+			return err
+		}
+		if !known {
+			rec.Fields = append(rec.Fields, String(key, value))
+		}
+	}
+	return nil
+}
+
+// _parseTextRecord parse a line of the elogging text format into the record
+func _parseTextRecord(b []byte, rec *Record) (err error) {
+	*rec, err = ParseLine(string(b))
+	return err
+}
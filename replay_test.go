@@ -0,0 +1,50 @@
+package elogging
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestReplayJSON(t *testing.T) {
+	flags := ELJSONLog | log.LstdFlags | log.Lmicroseconds | log.Lshortfile
+	written := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestReplay", "trace", written)
+	elog.SetFlags(flags)
+	elog.InfoKV("user login", String("user", "bob"), Int("attempt", 2), Bool("ok", true), Float64("ratio", 0.5))
+	elog.Warn("disk \"almost\" full\n")
+	elog.Print("plain")
+
+	replayed := &bytes.Buffer{}
+	n, err := Replay(bytes.NewReader(written.Bytes()), FormatJSON, NewWriterSink(replayed, FlagsEncoder(flags), ""))
+	if err != nil || n != 3 {
+		t.Fatalf("replayed %d records: %v", n, err)
+	}
+	if replayed.String() != written.String() {
+		t.Errorf("expected %q, got %q", written.String(), replayed.String())
+	}
+}
+
+func TestReplayLogfmt(t *testing.T) {
+	sink := NewBenchmarkSink(nil)
+	input := "time=2021-03-04T05:06:07Z level=WARN scope=db msg=\"slow query\" ms=120\n\nlvl=error msg=failed\n"
+	n, err := Replay(strings.NewReader(input), FormatLogfmt, sink)
+	if err != nil || n != 2 {
+		t.Fatalf("replayed %d records: %v", n, err)
+	}
+	if _, err := Replay(strings.NewReader("ok=1\nbroken line\n"), FormatLogfmt, sink); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected an error on line 2, got %v", err)
+	}
+}
+
+func TestReplayRecord(t *testing.T) {
+	var rec Record
+	if err := _parseLogfmtRecord([]byte(`time=1614834367000 level=Println scope=db msg="a \"b\"" k=v`), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Time.UnixMilli() != 1614834367000 || rec.Level() != "Print" || rec.Scope != "db" || rec.Msg != `a "b"` ||
+		len(rec.Fields) != 1 || rec.Fields[0].Key != "k" {
+		t.Errorf("unexpected record %+v", rec)
+	}
+}
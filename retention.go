@@ -0,0 +1,185 @@
+package elogging
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetentionInterval is the period at which the retention managers prune their directory
+var RetentionInterval = time.Minute
+
+// DirRetention is a background retention manager of a log directory, see ManageDir
+type DirRetention struct {
+	dir          string
+	maxTotalSize int64
+	maxAge       time.Duration
+
+	mu      sync.Mutex
+	removed int
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// ManageDir start a retention manager pruning the log files under dir every RetentionInterval: files older than
+// maxAge are removed, then the oldest files are removed until their total size is at most maxTotalSize; 0 disable
+// the corresponding limit. Log files are the files renamed by the rotation (path.20060102-150405.000[-n]) and the
+// files matching the template of an open FileSink, other files (configuration, hidden files, ...) are left alone.
+// Files being written by the package (outputs, rotating files, file sinks) are never removed nor counted.
+func ManageDir(dir string, maxTotalSize int64, maxAge time.Duration) *DirRetention {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	d := &DirRetention{dir: dir, maxTotalSize: maxTotalSize, maxAge: maxAge,
+		stop: make(chan struct{}), done: make(chan struct{})}
+	go d._run()
+	return d
+}
+
+func (d *DirRetention) _run() {
+	defer close(d.done)
+	t := time.NewTicker(RetentionInterval)
+	defer t.Stop()
+	for {
+		if err := d.Prune(); err != nil {
+			_internalf("retention of %s: %v", d.dir, err)
+		}
+		select {
+		case <-d.stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Prune apply the retention limits now and return the first removal error
+func (d *DirRetention) Prune() (err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	type logFile struct {
+		path string
+		size int64
+		mod  time.Time
+	}
+	var files []logFile
+	var total int64
+	patterns := _logPatterns()
+	walkErr := filepath.WalkDir(d.dir, func(path string, de fs.DirEntry, err error) error {
+		if err != nil || !de.Type().IsRegular() {
+			return nil
+		}
+		if !patterns.match(path) || _isActive(path) {
+			return nil
+		}
+		fi, err := de.Info()
+		if err != nil {
+			return nil
+		}
+		total += fi.Size()
+		files = append(files, logFile{path, fi.Size(), fi.ModTime()})
+		return nil
+	})
+	if walkErr != nil {
+		return walkErr
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].mod.Before(files[j].mod) })
+
+	now := time.Now()
+	for _, f := range files {
+		expired := d.maxAge > 0 && now.Sub(f.mod) > d.maxAge
+		if !expired && (d.maxTotalSize <= 0 || total <= d.maxTotalSize) {
+			continue
+		}
+		if rerr := os.Remove(f.path); rerr != nil {
+			if err == nil {
+				err = rerr
+			}
+			continue
+		}
+		total -= f.size
+		d.removed++
+	}
+	return
+}
+
+// Removed return the number of files removed by the manager
+func (d *DirRetention) Removed() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.removed
+}
+
+// Close stop the retention manager
+func (d *DirRetention) Close() error {
+	select {
+	case <-d.stop:
+	default:
+		close(d.stop)
+	}
+	<-d.done
+	return nil
+}
+
+// _rotatedSuffix match the suffix given by RotatingFile to the rotated files
+var _rotatedSuffix = regexp.MustCompile(`\.\d{8}-\d{6}\.\d{3}(-\d+)?$`)
+
+var (
+	_sinkTemplatesMu sync.Mutex
+	_sinkTemplates   = map[string]int{} // absolute templates of the open file sinks
+)
+
+// _setSinkTemplate record that a file sink writing to the files of the template is (open true) or no longer
+// (open false) open, the files of the open sinks are pruned by the retention managers
+func _setSinkTemplate(template string, open bool) {
+	if abs, err := filepath.Abs(template); err == nil {
+		template = abs
+	}
+	_sinkTemplatesMu.Lock()
+	defer _sinkTemplatesMu.Unlock()
+	if open {
+		_sinkTemplates[template]++
+	} else if _sinkTemplates[template]--; _sinkTemplates[template] <= 0 {
+		delete(_sinkTemplates, template)
+	}
+}
+
+// _logFilePatterns match the paths of the log files written by the package
+type _logFilePatterns []*regexp.Regexp
+
+// _logPatterns return the patterns of the log files: the rotated files and the files of the open file sinks
+func _logPatterns() _logFilePatterns {
+	_sinkTemplatesMu.Lock()
+	defer _sinkTemplatesMu.Unlock()
+	patterns := _logFilePatterns{_rotatedSuffix}
+	for template := range _sinkTemplates {
+		patterns = append(patterns, _templatePattern(template))
+	}
+	return patterns
+}
+
+// match report whether the file at the absolute path is a log file, hidden files never are
+func (p _logFilePatterns) match(path string) bool {
+	if strings.HasPrefix(filepath.Base(path), ".") {
+		return false
+	}
+	for _, re := range p {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// _templatePattern return the pattern matching the paths built from a file sink template
+func _templatePattern(template string) *regexp.Regexp {
+	placeholders := strings.NewReplacer(
+		regexp.QuoteMeta("{scope}"), `[^/\\]+`,
+		regexp.QuoteMeta("{level}"), `[a-z]+`,
+		regexp.QuoteMeta("{date}"), `\d{4}-\d{2}-\d{2}`)
+	return regexp.MustCompile("^" + placeholders.Replace(regexp.QuoteMeta(template)) + "$")
+}
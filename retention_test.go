@@ -0,0 +1,99 @@
+package elogging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManageDir(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, size int, age time.Duration) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mod := time.Now().Add(-age)
+		os.Chtimes(path, mod, mod)
+		return path
+	}
+	config := write("app.yaml", 10, 96*time.Hour)
+	hidden := write(".app.log.20240101-000000.000", 10, 96*time.Hour)
+	expired := write("app.log.20240101-000000.000", 10, 48*time.Hour)
+	oldest := write("app.log.20240102-000000.000", 100, 3*time.Hour)
+	newer := write("app.log.20240103-000000.000", 100, 2*time.Hour)
+	active, err := OpenRotatingFile(filepath.Join(dir, "app.log"), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer active.Close()
+	os.Chtimes(active.Path(), time.Now().Add(-72*time.Hour), time.Now().Add(-72*time.Hour))
+
+	d := ManageDir(dir, 150, 24*time.Hour)
+	d.Close()
+	for _, path := range []string{expired, oldest} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", path)
+		}
+	}
+	for _, path := range []string{newer, active.Path(), config, hidden} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be kept: %v", path, err)
+		}
+	}
+	if d.Removed() != 2 {
+		t.Errorf("expected 2 removed files, got %d", d.Removed())
+	}
+}
+
+func TestManageDirPatterns(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, size int) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mod := time.Now().Add(-time.Hour)
+		os.Chtimes(path, mod, mod)
+		return path
+	}
+	sink := NewFileSink(filepath.Join(dir, "{level}-{date}.log"), nil, "")
+	old := write("error-2024-05-01.log", 100)
+	other := write("notes.txt", 100)
+	rec := &Record{Time: time.Now(), Scope: "s", Tag: "INFO", Msg: "m", level: lInfo}
+	if err := sink.WriteRecord(rec); err != nil {
+		t.Fatal(err)
+	}
+	big := make([]byte, 1000)
+	for i := 0; i < 4; i++ {
+		sink.WriteRecord(&Record{Time: time.Now(), Scope: "s", Tag: "INFO", Msg: string(big), level: lInfo})
+	}
+
+	// the active sink file alone is over the limit, the inactive files fit
+	d := ManageDir(dir, 150, 0)
+	d.Close()
+	if _, err := os.Stat(old); err != nil {
+		t.Errorf("expected %s to be kept, the active file is not counted: %v", old, err)
+	}
+	if d.Removed() != 0 {
+		t.Errorf("unexpected removals %d", d.Removed())
+	}
+
+	d = ManageDir(dir, 50, 0)
+	d.Close()
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed", old)
+	}
+	if _, err := os.Stat(other); err != nil || d.Removed() != 1 {
+		t.Errorf("expected only the sink file removed, %d removed: %v", d.Removed(), err)
+	}
+
+	sink.Close()
+	again := write("error-2024-05-02.log", 100)
+	d = ManageDir(dir, 50, 0)
+	d.Close()
+	if _, err := os.Stat(again); err != nil {
+		t.Errorf("expected %s to be kept once the sink is closed: %v", again, err)
+	}
+}
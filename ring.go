@@ -0,0 +1,77 @@
+package elogging
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// ringBuffer keep the most recent records of all the Elogs
+type ringBuffer struct {
+	mu      sync.Mutex
+	records []*Record
+	next    int
+	full    bool
+}
+
+// EnableRingBuffer keep the last n records emitted by any Elog of the registry in memory, see the package EnableRingBuffer
+func (r *Registry) EnableRingBuffer(n int) {
+	if n <= 0 {
+		r._ring = nil
+		return
+	}
+	r._ring = &ringBuffer{records: make([]*Record, n)}
+}
+
+// EnableRingBuffer keep the last n records emitted by any Elog in memory (a flight recorder) for Query,
+// the admin handler and DumpState, n <= 0 disable it and drop the retained records
+func EnableRingBuffer(n int) {
+	_defaultRegistry.EnableRingBuffer(n)
+}
+
+func (r *ringBuffer) add(rec *Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot return the retained records, oldest first
+func (r *ringBuffer) snapshot() []*Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]*Record(nil), r.records[:r.next]...)
+	}
+	return append(append([]*Record(nil), r.records[r.next:]...), r.records[:r.next]...)
+}
+
+// Query return the records retained by the ring buffer of the registry, see the package Query
+func (r *Registry) Query(scope, minLevel string, since time.Time, substr string) (records []Record) {
+	ring := r._ring
+	if ring == nil {
+		return nil
+	}
+	scope = r.ResolveScope(scope)
+	min := lTrace
+	if minLevel != "" {
+		min = _value(_valid(minLevel))
+	}
+	for _, rec := range ring.snapshot() {
+		if (scope == "" || _matchScope(scope, rec.Scope)) && _passes(rec.level, min) &&
+			!rec.Time.Before(since) && strings.Contains(rec.Msg, substr) {
+			records = append(records, *rec)
+		}
+	}
+	return
+}
+
+// Query return the retained records (see EnableRingBuffer), oldest first, matching all the given criteria:
+// scope (a scope, pattern or alias, empty for all), minLevel (the record is at or above it, empty for all),
+// since (emitted at or after, zero for all) and substr (contained in the message, empty for all)
+func Query(scope, minLevel string, since time.Time, substr string) []Record {
+	return _defaultRegistry.Query(scope, minLevel, since, substr)
+}
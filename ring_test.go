@@ -0,0 +1,35 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRingBufferQuery(t *testing.T) {
+	EnableRingBuffer(3)
+	defer EnableRingBuffer(0)
+
+	elog := NewElog("TestRing.db", "trace", &bytes.Buffer{})
+	defer elog.Clear()
+	start := time.Now()
+	elog.Info("evicted from the ring")
+	elog.Error("connection timeout")
+	elog.Trace("query timeout details")
+	elog.Warn("slow query")
+
+	if records := Query("", "", time.Time{}, ""); len(records) != 3 || records[0].Msg != "connection timeout" {
+		t.Errorf("unexpected retained records %+v", records)
+	}
+	records := Query("TestRing.*", "warning", start, "timeout")
+	if len(records) != 1 || records[0].Msg != "connection timeout" || records[0].Level() != "Error" {
+		t.Errorf("unexpected query result %+v", records)
+	}
+
+	b := &bytes.Buffer{}
+	DumpState(b)
+	if !strings.Contains(b.String(), "recent records (3)") || !strings.Contains(b.String(), "(WARN) slow query") {
+		t.Errorf("expected recent records in state dump %q", b.String())
+	}
+}
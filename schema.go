@@ -0,0 +1,146 @@
+package elogging
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// SchemaType is the type of a field value allowed by a schema
+type SchemaType string
+
+const (
+	SchemaString   SchemaType = "string"
+	SchemaInt      SchemaType = "int" // signed and unsigned integers
+	SchemaFloat    SchemaType = "float"
+	SchemaBool     SchemaType = "bool"
+	SchemaDuration SchemaType = "duration"
+	SchemaTime     SchemaType = "time"
+	SchemaError    SchemaType = "error"
+	SchemaAny      SchemaType = "any" // any value type
+)
+
+// Schema is the set of fields allowed in the records of a scope with the type of their value
+type Schema struct {
+	Fields map[string]SchemaType
+	// Warn log the violations as warning records of the Elog instead of reporting them on stderr
+	Warn bool
+}
+
+// SchemaViolationsMax is the maximum number of distinct violations (scope, key and problem) reported by a registry,
+// the following ones are not reported, e.g. unknown keys made of request ids
+var SchemaViolationsMax = 1000
+
+// _violationSet is the set of the schema violations already reported
+type _violationSet struct {
+	mu   sync.Mutex
+	seen map[string]bool
+	full bool
+}
+
+// add record a violation, report whether it is a new one to report and whether the set just got full
+func (s *_violationSet) add(v string) (report, full bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.full || s.seen[v] {
+		return false, false
+	}
+	if len(s.seen) >= SchemaViolationsMax {
+		s.full = true
+		return false, true
+	}
+	if s.seen == nil {
+		s.seen = map[string]bool{}
+	}
+	s.seen[v] = true
+	return true, false
+}
+
+// reset forget the violations reported
+func (s *_violationSet) reset() {
+	s.mu.Lock()
+	s.seen, s.full = nil, false
+	s.mu.Unlock()
+}
+
+// _schemaType return the schema type of the field value
+func (f *FieldT) _schemaType() SchemaType {
+	switch f.kind {
+	case kindString:
+		return SchemaString
+	case kindInt, kindUint:
+		return SchemaInt
+	case kindFloat:
+		return SchemaFloat
+	case kindBool:
+		return SchemaBool
+	case kindDuration:
+		return SchemaDuration
+	case kindTime:
+		return SchemaTime
+	case kindError:
+		return SchemaError
+	}
+	return SchemaAny
+}
+
+// SetFieldSchema validate the fields of the records of the Elogs of the registry whose scope matches the pattern
+// (a scope, a path.Match pattern or an alias), a nil schema remove the validation of the pattern
+func (r *Registry) SetFieldSchema(scope string, schema *Schema) {
+	scope = r.ResolveScope(scope)
+	if schema == nil {
+		delete(r._schemas, scope)
+		return
+	}
+	r._schemas[scope] = schema
+}
+
+// SetFieldSchema validate the fields of the records of the Elogs whose scope matches the pattern (a scope,
+// a path.Match pattern or an alias) against the schema: unknown keys and values of another type are violations,
+// reported once per scope, key and problem (up to SchemaViolationsMax), as warning records of the Elog when its level
+// allows them with Schema.Warn. Records are written whatever the violations.
+// A nil schema remove the validation of the pattern.
+func SetFieldSchema(scope string, schema *Schema) {
+	_defaultRegistry.SetFieldSchema(scope, schema)
+}
+
+// _checkSchema report the violations of the fields of the record to the schemas of the registry,
+// calldepth is the same as for _record
+func (e *Elog) _checkSchema(calldepth int, rec *Record) {
+	r := e._reg
+	patterns := make([]string, 0, len(r._schemas))
+	for pattern := range r._schemas {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		schema := r._schemas[pattern]
+		if !_matchScope(pattern, rec.Scope) {
+			continue
+		}
+		for i := range rec.Fields {
+			f := &rec.Fields[i]
+			var problem string
+			if typ, ok := schema.Fields[f.Key]; !ok {
+				problem = "unknown field"
+			} else if actual := f._schemaType(); typ != SchemaAny && actual != typ {
+				problem = fmt.Sprintf("%s value for %s field", actual, typ)
+			} else {
+				continue
+			}
+			if schema.Warn && !e._enabled(lWarn) {
+				continue
+			}
+			report, full := r._schemaSeen.add(rec.Scope + "\x00" + f.Key + "\x00" + problem)
+			switch {
+			case full:
+				_internalf("field schema violations no longer reported, %d distinct ones reported", SchemaViolationsMax)
+			case !report:
+			case schema.Warn:
+				e._output(calldepth+1, lWarn, _valid(lWarn.String()), fmt.Sprintf("field schema violation: %s %q", problem, f.Key))
+			default:
+				_internalf("field schema violation in scope %s: %s %q", rec.Scope, problem, f.Key)
+			}
+		}
+	}
+}
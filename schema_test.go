@@ -0,0 +1,83 @@
+package elogging
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFieldSchema(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("billing.invoices", "info", b)
+	elog.SetFlags(0)
+	r.SetFieldSchema("billing.*", &Schema{Fields: map[string]SchemaType{
+		"amount": SchemaInt,
+		"user":   SchemaString,
+		"extra":  SchemaAny,
+	}, Warn: true})
+
+	elog.InfoKV("paid", Int("amount", 3), String("user", "bob"), Bool("extra", true))
+	elog.InfoKV("paid", String("amount", "3"), String("User", "bob"))
+	elog.InfoKV("paid", String("amount", "4"))
+
+	out := b.String()
+	if strings.Count(out, "field schema violation") != 2 ||
+		!strings.Contains(out, `(WARN) field schema violation: string value for int field "amount"`) ||
+		!strings.Contains(out, `(WARN) field schema violation: unknown field "User"`) {
+		t.Errorf("unexpected output %q", out)
+	}
+	if strings.Count(out, "(INFO) paid") != 3 {
+		t.Error("expected the records written whatever the violations")
+	}
+}
+
+func TestFieldSchemaOrder(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("billing.invoices", "info", b)
+	elog.SetFlags(0)
+	// "*" sorts first and reports on stderr, then the violations are not reported again in the output
+	r.SetFieldSchema("billing.*", &Schema{Fields: map[string]SchemaType{}, Warn: true})
+	r.SetFieldSchema("*", &Schema{Fields: map[string]SchemaType{}})
+	for i := 0; i < 10; i++ {
+		elog.InfoKV("paid", Int("a", 1), Int("b", 2))
+	}
+	if strings.Contains(b.String(), "violation") {
+		t.Errorf("unexpected output %q", b.String())
+	}
+}
+
+func TestFieldSchemaWarnLevel(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("billing.invoices", "error", b)
+	elog.SetFlags(0)
+	r.SetFieldSchema("billing.*", &Schema{Fields: map[string]SchemaType{}, Warn: true})
+	elog.ErrorKV("failed", Int("amount", 3))
+	if strings.Contains(b.String(), "violation") {
+		t.Errorf("violation written below the level of the Elog %q", b.String())
+	}
+	elog.SetLevel("warning")
+	elog.ErrorKV("failed", Int("amount", 3))
+	if !strings.Contains(b.String(), `(WARN) field schema violation: unknown field "amount"`) {
+		t.Errorf("violation not reported once the level allows it %q", b.String())
+	}
+}
+
+func TestFieldSchemaViolationsMax(t *testing.T) {
+	defer func(max int) { SchemaViolationsMax = max }(SchemaViolationsMax)
+	SchemaViolationsMax = 3
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("billing.invoices", "info", b)
+	elog.SetFlags(0)
+	r.SetFieldSchema("billing.*", &Schema{Fields: map[string]SchemaType{}, Warn: true})
+	for i := 0; i < 10; i++ {
+		elog.InfoKV("paid", Int("request."+strconv.Itoa(i), i))
+	}
+	if n := strings.Count(b.String(), "violation"); n != 3 || len(r._schemaSeen.seen) != 3 {
+		t.Errorf("expected 3 violations reported and kept, got %d and %d", n, len(r._schemaSeen.seen))
+	}
+}
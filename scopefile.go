@@ -0,0 +1,69 @@
+package elogging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// _scopeFileRotate is the rotation size of the scope files when the directory does not give one
+const _scopeFileRotate = 100 << 20
+
+// SetScopeFileDir route each scope of the registry to its own file in dir, see the package SetScopeFileDir
+func (r *Registry) SetScopeFileDir(dir string) error {
+	if dir == "" {
+		if r._allow("", "scope_file_dir") {
+			r._audit("", "scope_file_dir", r._scopeDir.path, "")
+			r._scopeDir = outputSpec{}
+		}
+		return nil
+	}
+	o, err := _parseOutput("file:" + dir)
+	if err != nil {
+		return err
+	}
+	if o.rotate == 0 {
+		o.rotate = _scopeFileRotate
+	}
+	if err := os.MkdirAll(o.path, 0755); err != nil {
+		return fmt.Errorf("elogging: scope file directory: %w", err)
+	}
+	if !r._allow("", "scope_file_dir") {
+		return nil
+	}
+	r._audit("", "scope_file_dir", r._scopeDir.path, o.path)
+	r._scopeDir = o
+	def := r._defaultOut
+	if def == nil {
+		def = os.Stdout
+	}
+	for e := range r._registered() {
+		if e._getOutput() != def {
+			continue
+		}
+		out, err := r._scopeFile(e._scope())
+		if err != nil {
+			return err
+		}
+		e.ModifyParams("", "", out)
+	}
+	return nil
+}
+
+// SetScopeFileDir route each scope to its own file, <dir>/<scope>.log, rotated once it grows over 100MB or the size
+// given as dir?rotate=size, for services where operators expect one file per subsystem:
+//  elogging.SetScopeFileDir("/var/log/app?rotate=50MB")
+// the Elogs writing to the default output (existing ones included) write to the file of their scope instead, the
+// Elogs given an output keep it. The Elogs of a scope share its file (see NewFileElog), path separators in scopes are
+// replaced by underscores. An empty dir stop the routing of the new Elogs.
+func SetScopeFileDir(dir string) error {
+	return _defaultRegistry.SetScopeFileDir(dir)
+}
+
+// _scopeFile return the output of a scope routed to the scope file directory
+func (r *Registry) _scopeFile(scope string) (io.Writer, error) {
+	o := r._scopeDir
+	o.path = filepath.Join(o.path, _pathSafe(scope)+".log")
+	return r._openFile(o)
+}
@@ -0,0 +1,41 @@
+package elogging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetScopeFileDir(t *testing.T) {
+	r := NewRegistry()
+	dir := filepath.Join(t.TempDir(), "logs")
+	early := r.NewElog("TestSetScopeFileDir.early", "info", nil)
+	own := &bytes.Buffer{}
+	kept := r.NewElog("TestSetScopeFileDir.kept", "info", own)
+	if err := r.SetScopeFileDir(dir + "?rotate=1MB"); err != nil {
+		t.Fatal(err)
+	}
+	db := r.NewElog("TestSetScopeFileDir/db", "info", nil)
+	early.Info("early record")
+	db.Info("db record")
+	kept.Info("kept record")
+	r.ClearAll()
+
+	for file, expected := range map[string]string{
+		"TestSetScopeFileDir.early.log": "early record",
+		"TestSetScopeFileDir_db.log":    "db record",
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil || !strings.Contains(string(b), expected) {
+			t.Errorf("expected %q in %s, got %q (%v)", expected, file, b, err)
+		}
+	}
+	if !strings.Contains(own.String(), "kept record") {
+		t.Errorf("expected the Elog given an output to keep it, got %q", own.String())
+	}
+	if r._scopeDir.rotate != 1<<20 {
+		t.Errorf("expected the rotation size of the directory, got %d", r._scopeDir.rotate)
+	}
+}
@@ -0,0 +1,83 @@
+package elogging
+
+import "strings"
+
+// SeverityMap map the levels to the severity numbers of a backend, backends disagree on the meaning of
+// the levels below info so each one has its own table, change the package tables or give a copy to a single sink
+type SeverityMap struct {
+	Fatal   int
+	Error   int
+	Warning int
+	Info    int
+	Verbose int
+	Trace   int
+	Print   int // records of the Print family
+}
+
+var (
+	// SyslogSeverity is the syslog (RFC 5424) severity of the levels: crit, err, warning, info, debug
+	SyslogSeverity = SeverityMap{Fatal: 2, Error: 3, Warning: 4, Info: 6, Verbose: 7, Trace: 7, Print: 6}
+	// JournaldPriority is the journald PRIORITY of the levels, journald uses the syslog severities
+	JournaldPriority = SeverityMap{Fatal: 2, Error: 3, Warning: 4, Info: 6, Verbose: 7, Trace: 7, Print: 6}
+	// GELFLevel is the Graylog GELF level of the levels, verbose records are notices (5) so they stay
+	// searchable apart from the trace records (debug)
+	GELFLevel = SeverityMap{Fatal: 2, Error: 3, Warning: 4, Info: 6, Verbose: 5, Trace: 7, Print: 6}
+	// OTLPSeverity is the OpenTelemetry SeverityNumber of the levels: FATAL, ERROR, WARN, INFO, DEBUG, TRACE
+	OTLPSeverity = SeverityMap{Fatal: 21, Error: 17, Warning: 13, Info: 9, Verbose: 5, Trace: 1, Print: 9}
+	// SIEMSeverity is the 0-10 severity of the levels used by the CEF and LEEF encoders
+	SIEMSeverity = SeverityMap{Fatal: 10, Error: 7, Warning: 5, Info: 3, Verbose: 2, Trace: 1, Print: 3}
+)
+
+// Of return the severity of a level name (print and fatal included), the info severity for an unknown level
+func (m *SeverityMap) Of(level string) int {
+	switch strings.ToLower(level) {
+	case "print", "printf", "println":
+		return m.Print
+	case "fatal", "panic":
+		return m.Fatal
+	}
+	return m._of(_value(level))
+}
+
+// Severity return the severity of the record
+func (m *SeverityMap) Severity(rec *Record) int {
+	return m._of(rec.level)
+}
+
+// _severity return the table of a sink, its default when not set
+func _severity(m, def *SeverityMap) *SeverityMap {
+	if m == nil {
+		return def
+	}
+	return m
+}
+
+func (m *SeverityMap) _of(level llevel) int {
+	switch level {
+	case lFatal:
+		return m.Fatal
+	case lError:
+		return m.Error
+	case lWarn:
+		return m.Warning
+	case lVerbose:
+		return m.Verbose
+	case lTrace:
+		return m.Trace
+	case lPrint:
+		return m.Print
+	}
+	return m.Info
+}
+
+// SeverityEncoder wrap an encoder adding the severity of each record from a table as a field, e.g. for a JSON sink
+// read by a backend expecting numeric levels:
+//  NewWriterSink(conn, SeverityEncoder(FlagsEncoder(ELJSONLog), "severity", &OTLPSeverity), "")
+// the table is read when encoding, so a sink given a package table follows its changes
+func SeverityEncoder(enc Encoder, key string, m *SeverityMap) Encoder {
+	return EncoderFunc(func(buf []byte, rec *Record) []byte {
+		r := *rec
+		r.Fields = append(rec.Fields[:len(rec.Fields):len(rec.Fields)], Int(key, m.Severity(rec)))
+		return enc.Encode(buf, &r)
+	})
+}
@@ -0,0 +1,43 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSeverityMap(t *testing.T) {
+	for _, c := range []struct {
+		m        *SeverityMap
+		level    string
+		expected int
+	}{
+		{&SyslogSeverity, "error", 3},
+		{&SyslogSeverity, "verbose", 7},
+		{&GELFLevel, "verbose", 5},
+		{&OTLPSeverity, "trace", 1},
+		{&OTLPSeverity, "Println", 9},
+		{&OTLPSeverity, "fatal", 21},
+		{&JournaldPriority, "unknown", 6},
+	} {
+		if s := c.m.Of(c.level); s != c.expected {
+			t.Errorf("%s: expected %d, got %d", c.level, c.expected, s)
+		}
+	}
+}
+
+func TestSeverityEncoder(t *testing.T) {
+	b, cef := &bytes.Buffer{}, &bytes.Buffer{}
+	elog := NewEphemeralElog("TestSeverityEncoder", "trace", &bytes.Buffer{})
+	elog.AddSink(NewWriterSink(b, SeverityEncoder(FlagsEncoder(ELJSONLog), "severity", &OTLPSeverity), ""))
+	custom := SIEMSeverity
+	custom.Verbose = 4
+	elog.AddSink(NewWriterSink(cef, &CEFEncoder{Vendor: "acme", Product: "gw", Version: "1", Severity: &custom}, ""))
+	elog.Verbose("details")
+	if !strings.Contains(b.String(), `"msg":"details","severity":5}`) {
+		t.Errorf("unexpected output %q", b.String())
+	}
+	if !strings.Contains(cef.String(), "|details|4|") {
+		t.Errorf("unexpected output %q", cef.String())
+	}
+}
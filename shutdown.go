@@ -0,0 +1,162 @@
+package elogging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ShutdownPhase order the closers run by Shutdown, the downstream destinations are closed last so the records
+// flushed by the earlier phases reach them
+type ShutdownPhase int
+
+const (
+	PhaseQueues ShutdownPhase = iota // asynchronous queues and buffers, flushed first
+	PhaseHooks                       // hooks forwarding records elsewhere
+	PhaseSinks                       // sinks and outputs, closed last
+)
+
+func (p ShutdownPhase) String() string {
+	switch p {
+	case PhaseQueues:
+		return "queues"
+	case PhaseHooks:
+		return "hooks"
+	case PhaseSinks:
+		return "sinks"
+	}
+	return fmt.Sprintf("phase %d", int(p))
+}
+
+// _closer is a closer registered for Shutdown
+type _closer struct {
+	phase ShutdownPhase
+	name  string
+	fn    func(ctx context.Context) error
+}
+
+// _closerSet is the list of the closers of a registry
+type _closerSet struct {
+	mu      sync.Mutex
+	closers []_closer
+}
+
+// ShutdownError report the closers which failed or were not run by Shutdown
+type ShutdownError struct {
+	Errs []error
+}
+
+func (e *ShutdownError) Error() string {
+	return string(_appendErrorLines([]byte("elogging: shutdown: "), e.Errs))
+}
+
+// Unwrap return the errors of the closers, walked by errors.Is and errors.As from Go 1.20
+func (e *ShutdownError) Unwrap() []error {
+	return e.Errs
+}
+
+// Is report whether one of the errors of the closers matches target, for errors.Is before Go 1.20
+func (e *ShutdownError) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As find the first error of the closers matching target, for errors.As before Go 1.20
+func (e *ShutdownError) As(target interface{}) bool {
+	for _, err := range e.Errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterCloser register a closer run by Shutdown in the given phase, see the package RegisterCloser
+func (r *Registry) RegisterCloser(phase ShutdownPhase, name string, fn func(ctx context.Context) error) {
+	r._closers.mu.Lock()
+	r._closers.closers = append(r._closers.closers, _closer{phase: phase, name: name, fn: fn})
+	r._closers.mu.Unlock()
+}
+
+// RegisterCloser register a closer of a sink, hook or queue run by Shutdown: the closers run one at a time by
+// phase (queues, then hooks, then sinks; a higher phase runs later) and in registration order within a phase,
+// each one given the Shutdown context. The name identifies the closer in the errors reported by Shutdown.
+func RegisterCloser(phase ShutdownPhase, name string, fn func(ctx context.Context) error) {
+	_defaultRegistry.RegisterCloser(phase, name, fn)
+}
+
+// Shutdown stop the background work of the registry and run its closers, see the package Shutdown
+func (r *Registry) Shutdown(ctx context.Context) error {
+	var errs []error
+periodic:
+	for _, p := range r._periodic._stopAll() {
+		select {
+		case <-p.done:
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("periodic tasks: %w", ctx.Err()))
+			break periodic
+		}
+	}
+	for e := range r._registered() {
+		e.FlushRepeated()
+	}
+	r._closers.mu.Lock()
+	closers := r._closers.closers
+	r._closers.closers = nil
+	r._closers.mu.Unlock()
+	sort.SliceStable(closers, func(i, j int) bool { return closers[i].phase < closers[j].phase })
+
+	for i, c := range closers {
+		if ctx.Err() != nil {
+			names := make([]string, 0, len(closers)-i)
+			for _, skipped := range closers[i:] {
+				names = append(names, skipped.name)
+			}
+			errs = append(errs, fmt.Errorf("closers not run: %s: %w", strings.Join(names, ", "), ctx.Err()))
+			break
+		}
+		if err := _runCloser(ctx, c); err != nil {
+			errs = append(errs, fmt.Errorf("%s closer %s: %w", c.phase, c.name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return &ShutdownError{Errs: errs}
+	}
+	return nil
+}
+
+// _runCloser run a closer until it returns or ctx is done, a closer ignoring its context is left running
+func _runCloser(ctx context.Context, c _closer) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				done <- fmt.Errorf("panic: %v", err)
+			}
+		}()
+		done <- c.fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown stop the background work of the package before the process exits so the final records are not lost:
+// the periodic tasks (see Every) are stopped and their calls in progress waited for, the summaries of the repeated
+// records are written (see ELSuppressRepeated), then the registered closers
+// (see RegisterCloser) run by phase: asynchronous queues are flushed first, then hooks, then sinks are closed.
+// Closers not run or not finished when ctx is done and the failed ones are reported in a *ShutdownError.
+// The closers run once, a later Shutdown only runs the closers registered since.
+func Shutdown(ctx context.Context) error {
+	return _defaultRegistry.Shutdown(ctx)
+}
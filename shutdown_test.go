@@ -0,0 +1,88 @@
+package elogging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	r := NewRegistry()
+	elog := r.NewElog("TestShutdown", "info", io.Discard)
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	p := elog.Every(time.Millisecond, "info", func(*Elog) {
+		select {
+		case started <- struct{}{}:
+			<-release
+		default:
+		}
+	})
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline while a call is in progress, got %v", err)
+	}
+	close(release)
+	<-p.Done()
+	if err := r.Shutdown(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShutdownClosers(t *testing.T) {
+	r := NewRegistry()
+	var order []string
+	closer := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+	full := errors.New("disk full")
+	r.RegisterCloser(PhaseSinks, "file", closer("file", &os.PathError{Op: "close", Path: "app.log", Err: full}))
+	r.RegisterCloser(PhaseHooks, "audit", closer("audit", nil))
+	r.RegisterCloser(PhaseQueues, "async", closer("async", nil))
+	r.RegisterCloser(PhaseSinks, "remote", closer("remote", nil))
+
+	err := r.Shutdown(context.Background())
+	if got := strings.Join(order, ","); got != "async,audit,file,remote" {
+		t.Errorf("closers order %s", got)
+	}
+	var se *ShutdownError
+	if !errors.As(err, &se) || len(se.Errs) != 1 || !strings.Contains(err.Error(), "sinks closer file: close app.log: disk full") {
+		t.Errorf("unexpected error %v", err)
+	}
+	var pe *os.PathError
+	if !se.Is(full) || se.Is(context.Canceled) || !se.As(&pe) || pe.Path != "app.log" {
+		t.Errorf("closer errors not matched by Is and As: %v", err)
+	}
+	order = nil
+	if err := r.Shutdown(context.Background()); err != nil || len(order) != 0 {
+		t.Errorf("closers run again: %v %v", order, err)
+	}
+}
+
+func TestShutdownClosersDeadline(t *testing.T) {
+	r := NewRegistry()
+	block := make(chan struct{})
+	defer close(block)
+	r.RegisterCloser(PhaseQueues, "stuck", func(context.Context) error {
+		<-block
+		return nil
+	})
+	r.RegisterCloser(PhaseSinks, "file", func(context.Context) error {
+		t.Error("closer run after the deadline")
+		return nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := r.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "closers not run: file") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package elogging
+
+import "sync/atomic"
+
+// WithSilence run fn with the output of the Elogs of the registry disabled, see the package WithSilence
+func (r *Registry) WithSilence(fn func()) {
+	atomic.AddInt32(&r._silence, 1)
+	defer atomic.AddInt32(&r._silence, -1)
+	fn()
+}
+
+// WithSilence run fn with all the output disabled, e.g. for a benchmark harness excluding the logging cost:
+//  elogging.WithSilence(func() { result = testing.Benchmark(benchmarkHandler) })
+// the records are dropped before they are built, so neither their formatting nor the hooks, sinks, stats, repeated
+// records suppression or debugging run, only Fatal and Panic records are still written. The state of the package is
+// not changed: LogsOn, LogsOff and the levels set before, during (from other goroutines) or after fn apply once it
+// returns, even when it panics, and calls of WithSilence can be nested or concurrent.
+func WithSilence(fn func()) {
+	_defaultRegistry.WithSilence(fn)
+}
@@ -0,0 +1,31 @@
+package elogging
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithSilence(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestWithSilence", "trace", b)
+	elog.SetFlags(0)
+	hooks := 0
+	elog.AddHook(func(*Record) { hooks++ })
+	r.WithSilence(func() {
+		elog.Error("silenced")
+		elog.Print("silenced")
+		r.WithSilence(func() { elog.Trace("silenced") })
+		elog.InfoKV("silenced", String("k", "v"))
+		r.LogsOff()
+	})
+	if b.Len() != 0 || hooks != 0 || elog.Stats().Records != 0 {
+		t.Errorf("expected no output, hook nor stats, got %q, %d hooks, %d records", b.String(), hooks, elog.Stats().Records)
+	}
+	elog.Error("off")
+	r.LogsOn()
+	elog.Error("after")
+	if expected := "TestWithSilence (ERROR) after\n"; b.String() != expected || hooks != 1 {
+		t.Errorf("expected %q with 1 hook, got %q with %d hooks", expected, b.String(), hooks)
+	}
+}
@@ -0,0 +1,92 @@
+package elogging
+
+import (
+	"io"
+)
+
+// Sink is an additional destination for the records of an Elog.
+//
+// WriteRecord is called after the record was written to the Elog output, the record follows the same
+// contract as for hooks: it must not be kept nor modified once WriteRecord returns (keep rec.Clone() instead).
+type Sink interface {
+	WriteRecord(rec *Record) error
+}
+
+// AddSink add a sink receiving every record emitted by the Elog, the first log destination sink added (see
+// BootstrapReceiver) also receives the records held by the bootstrap buffer (see EnableBootstrapBuffer)
+func (e *Elog) AddSink(s Sink) {
+	add := func() {
+		e._conf.Lock()
+		e._sinks = append(e._sinks, s)
+		e._conf.Unlock()
+	}
+	b := e._reg._getBootstrap()
+	if r, ok := s.(BootstrapReceiver); b == nil || !ok || !r.ReceivesBootstrap() {
+		add()
+		return
+	}
+	if records, dropped, done := b.release(e._reg, add); !done {
+		if err := _deliver(s, records, dropped); err != nil {
+			_internalf("%v", err)
+		}
+	}
+}
+
+// RemoveSink remove a sink previously added to the Elog
+func (e *Elog) RemoveSink(s Sink) {
+	e._conf.Lock()
+	defer e._conf.Unlock()
+	for i, k := range e._sinks {
+		if k == s {
+			e._sinks = append(e._sinks[:i:i], e._sinks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Sinks retrieve the sinks of the Elog
+func (e *Elog) Sinks() []Sink {
+	return append([]Sink(nil), e._getSinks()...)
+}
+
+// WriterSink is a sink rendering records at or above a minimum level with an encoder and writing them to an io.Writer
+type WriterSink struct {
+	out      io.Writer
+	enc      Encoder
+	minLevel llevel
+}
+
+// NewWriterSink create a sink writing the records at or above minLevel (empty for all) to out, rendered by enc
+// (nil for the default text format). Print records count as info records, Fatal records are always written.
+func NewWriterSink(out io.Writer, enc Encoder, minLevel string) *WriterSink {
+	if enc == nil {
+		enc = FlagsEncoder(DefaultFlags())
+	}
+	s := &WriterSink{out: out, enc: enc, minLevel: lTrace}
+	if minLevel != "" {
+		s.minLevel = _value(_valid(minLevel))
+	}
+	return s
+}
+
+// _passes report whether a record with the given level is at or above the minimum level
+func _passes(level, minLevel llevel) bool {
+	if level == lPrint {
+		level = lInfo
+	}
+	return level <= minLevel
+}
+
+// ReceivesBootstrap report that the sink receives the records of the bootstrap buffer, see BootstrapReceiver
+func (s *WriterSink) ReceivesBootstrap() bool {
+	return true
+}
+
+// WriteRecord render and write the record when its level is at or above the sink minimum level
+func (s *WriterSink) WriteRecord(rec *Record) error {
+	if !_passes(rec.level, s.minLevel) {
+		return nil
+	}
+	buf := s.enc.Encode(make([]byte, 0, 128), rec)
+	return _lockedWrite(s.out, buf)
+}
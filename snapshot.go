@@ -0,0 +1,211 @@
+package elogging
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// RegistrySnapshot is the configuration of a registry at a point in time, see Snapshot, Diff and Restore
+type RegistrySnapshot struct {
+	Time            time.Time
+	LogsActive      bool
+	LogsOffFloor    string // level still emitted while the logs are off, see LogsOffExcept
+	GlobalLevel     string
+	GlobalLevelMode string
+	DefaultLevel    string
+	DefaultFlags    int
+	DefaultOutput   string
+	Overrides       map[string]string // scope level overrides
+	Logs            []ElogSnapshot    // sorted by scope and id
+
+	reg        *Registry
+	defaultOut io.Writer
+	overrides  []scopeOverride
+}
+
+// ElogSnapshot is the configuration of an Elog in a RegistrySnapshot
+type ElogSnapshot struct {
+	ID     string
+	Scope  string
+	Level  string
+	Flags  int
+	Output string
+
+	elog *Elog
+	out  io.Writer
+}
+
+// Snapshot capture the configuration of the registry and of its Elogs
+func (r *Registry) Snapshot() RegistrySnapshot {
+	s := RegistrySnapshot{
+		Time:            time.Now(),
+		LogsActive:      r._active(),
+		LogsOffFloor:    r._floor().String(),
+		GlobalLevel:     r._getGlobalLevel().String(),
+		GlobalLevelMode: r._getGlobalMode().String(),
+		DefaultLevel:    r._defaultLevel.String(),
+		DefaultFlags:    r._defaultFlags,
+		DefaultOutput:   _describeOutput(r._defaultOut),
+		Overrides:       r.ScopeLevelOverrides(),
+		reg:             r,
+		defaultOut:      r._defaultOut,
+		overrides:       append([]scopeOverride(nil), r._overrides...),
+	}
+	for _, e := range r.ListScopedLogs() {
+		s.Logs = append(s.Logs, ElogSnapshot{ID: e._id, Scope: e._scope(), Level: e._scopeLevel().String(), Flags: e._getFlags(),
+			Output: _describeOutput(e._getOutput()), elog: e, out: e._getOutput()})
+	}
+	sort.SliceStable(s.Logs, func(i, j int) bool { return s.Logs[i].Scope < s.Logs[j].Scope })
+	return s
+}
+
+// Snapshot capture the package configuration: logs on/off, global and default settings, level overrides
+// and the scope, level, flags and output of every registered Elog, to compare it later with Diff or to
+// revert bulk changes with Restore (e.g. at the end of a test)
+func Snapshot() RegistrySnapshot {
+	return _defaultRegistry.Snapshot()
+}
+
+// Restore bring the registry back to the configuration of the snapshot, see the package Restore
+func (r *Registry) Restore(s RegistrySnapshot) error {
+	if s.reg != r {
+		return fmt.Errorf("elogging: restore a snapshot of another registry")
+	}
+	if !r._allow("", "snapshot") {
+		return fmt.Errorf("elogging: restore a frozen configuration")
+	}
+	r._audit("", "snapshot", "", "restored from "+s.Time.Format(time.RFC3339))
+	r._setActive(s.LogsActive, _value(_valid(s.LogsOffFloor)))
+	r._setGlobalLevel(_value(_valid(s.GlobalLevel)))
+	mode, _ := ParseGlobalLevelMode(s.GlobalLevelMode)
+	r._setGlobalMode(mode)
+	r._defaultLevel = _value(_valid(s.DefaultLevel))
+	r._defaultFlags = s.DefaultFlags
+	r._defaultOut = s.defaultOut
+	r._overrides = append([]scopeOverride(nil), s.overrides...)
+	logs := r._registered()
+	for _, l := range s.Logs {
+		if _, ok := logs[l.elog]; !ok {
+			continue
+		}
+		l.elog._setScope(l.Scope)
+		l.elog._storeLevel(_value(_valid(l.Level)))
+		l.elog._storeFlags(l.Flags)
+		l.elog._setOutput(l.out)
+	}
+	r._applyOverrides()
+	return nil
+}
+
+// Restore bring the package back to the configuration of a snapshot taken with Snapshot, the Elogs
+// created since are left as is and the Elogs cleared since are not recreated
+func Restore(s RegistrySnapshot) error {
+	return _defaultRegistry.Restore(s)
+}
+
+// Change is a configuration difference between two snapshots
+type Change struct {
+	Scope   string // scope of the Elog, empty for a registry setting
+	ID      string // id of the Elog, empty for a registry setting
+	Setting string // setting name: logs_active, global_level, level, flags, output, elog, ...
+	From    string // empty when added
+	To      string // empty when removed
+}
+
+func (c Change) String() string {
+	what := c.Setting
+	if c.ID != "" {
+		what = fmt.Sprintf("%s[%.8s] %s", c.Scope, c.ID, c.Setting)
+	}
+	switch {
+	case c.From == "":
+		return fmt.Sprintf("%s: added %s", what, c.To)
+	case c.To == "":
+		return fmt.Sprintf("%s: removed %s", what, c.From)
+	}
+	return fmt.Sprintf("%s: %s -> %s", what, c.From, c.To)
+}
+
+// Diff describe what changed from snapshot a to snapshot b: registry settings, level overrides,
+// Elogs added or removed and the level, flags and output of the Elogs present in both
+func Diff(a, b RegistrySnapshot) []Change {
+	var changes []Change
+	add := func(scope, id, setting, from, to string) {
+		if from != to {
+			changes = append(changes, Change{Scope: scope, ID: id, Setting: setting, From: from, To: to})
+		}
+	}
+	add("", "", "logs_active", fmt.Sprint(a.LogsActive), fmt.Sprint(b.LogsActive))
+	add("", "", "logs_off_floor", a.LogsOffFloor, b.LogsOffFloor)
+	add("", "", "global_level", a.GlobalLevel, b.GlobalLevel)
+	add("", "", "global_level_mode", a.GlobalLevelMode, b.GlobalLevelMode)
+	add("", "", "default_level", a.DefaultLevel, b.DefaultLevel)
+	add("", "", "default_flags", fmt.Sprintf("%#x", a.DefaultFlags), fmt.Sprintf("%#x", b.DefaultFlags))
+	add("", "", "default_output", _outputID(a.DefaultOutput, a.defaultOut, b.defaultOut), b.DefaultOutput)
+	scopes := map[string]bool{}
+	for scope := range a.Overrides {
+		scopes[scope] = true
+	}
+	for scope := range b.Overrides {
+		scopes[scope] = true
+	}
+	for _, scope := range _sortedKeys(scopes) {
+		add("", "", "level_override "+scope, a.Overrides[scope], b.Overrides[scope])
+	}
+
+	before := map[string]ElogSnapshot{}
+	for _, l := range a.Logs {
+		before[l.ID] = l
+	}
+	for _, l := range b.Logs {
+		p, ok := before[l.ID]
+		if !ok {
+			add(l.Scope, l.ID, "elog", "", l.Level)
+			continue
+		}
+		delete(before, l.ID)
+		add(l.Scope, l.ID, "scope", p.Scope, l.Scope)
+		add(l.Scope, l.ID, "level", p.Level, l.Level)
+		add(l.Scope, l.ID, "flags", fmt.Sprintf("%#x", p.Flags), fmt.Sprintf("%#x", l.Flags))
+		add(l.Scope, l.ID, "output", _outputID(p.Output, p.out, l.out), l.Output)
+	}
+	for _, l := range a.Logs {
+		if _, ok := before[l.ID]; ok {
+			add(l.Scope, l.ID, "elog", l.Level, "")
+		}
+	}
+	return changes
+}
+
+// _outputID return the description of the output from, made distinct from the description of the output to
+// when both outputs are different writers with the same description
+func _outputID(desc string, from, to io.Writer) string {
+	if _sameWriter(from, to) {
+		return _describeOutput(to)
+	}
+	if desc == _describeOutput(to) {
+		return fmt.Sprintf("%s (another writer)", desc)
+	}
+	return desc
+}
+
+// _sameWriter report whether a and b are the same writer
+func _sameWriter(a, b io.Writer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	return ta == tb && ta.Comparable() && a == b
+}
+
+func _sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
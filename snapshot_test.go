@@ -0,0 +1,58 @@
+package elogging
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotDiffRestore(t *testing.T) {
+	r := NewRegistry()
+	db := r.NewElog("TestSnapshot.db", "info", io.Discard)
+	net := r.NewElog("TestSnapshot.net", "info", io.Discard)
+	s := r.Snapshot()
+	if len(s.Logs) != 2 || s.Logs[0].Scope != "TestSnapshot.db" || s.GlobalLevel != "Disabled" {
+		t.Fatalf("unexpected snapshot %+v", s)
+	}
+	if changes := Diff(s, r.Snapshot()); len(changes) != 0 {
+		t.Errorf("unexpected changes %v", changes)
+	}
+
+	r.SetGlobalLogLevel("trace")
+	r.SetScopeLevelOverride("TestSnapshot.*", "error")
+	db.SetLevel("verbose")
+	db.ModifyParams("", "", &bytes.Buffer{})
+	net.Clear()
+	added := r.NewElog("TestSnapshot.new", "info", io.Discard)
+
+	var got []string
+	for _, c := range Diff(s, r.Snapshot()) {
+		got = append(got, c.String())
+	}
+	for _, expected := range []string{
+		"global_level: Disabled -> Trace",
+		"level_override TestSnapshot.*: added Error",
+		"TestSnapshot.db[" + db._id[:8] + "] level: Info -> Verbose",
+		"TestSnapshot.db[" + db._id[:8] + "] output: io.discard -> *bytes.Buffer",
+		"TestSnapshot.new[" + added._id[:8] + "] elog: added Info",
+		"TestSnapshot.net[" + net._id[:8] + "] elog: removed Info",
+	} {
+		if !strings.Contains(strings.Join(got, "\n"), expected) {
+			t.Errorf("missing %q in %q", expected, got)
+		}
+	}
+
+	if err := r.Restore(s); err != nil {
+		t.Fatal(err)
+	}
+	if changes := Diff(s, r.Snapshot()); len(changes) != 2 || changes[0].Setting != "elog" || changes[1].Setting != "elog" {
+		t.Errorf("unexpected changes after restore %v", changes)
+	}
+	if db._out != io.Discard || db._enabled(lVerbose) {
+		t.Error("Elog not restored")
+	}
+	if err := NewRegistry().Restore(s); err == nil {
+		t.Error("expected an error restoring a snapshot of another registry")
+	}
+}
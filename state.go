@@ -0,0 +1,264 @@
+package elogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+)
+
+// scopeState is the persisted runtime state of a scope
+type scopeState struct {
+	Scope string `json:"scope"`
+	Level string `json:"level"`
+	Flags int    `json:"flags"`
+}
+
+// overrideState is a persisted entry of the level override table
+type overrideState struct {
+	Scope string `json:"scope"`
+	Level string `json:"level"`
+}
+
+// savedState is the persisted runtime state of the package
+type savedState struct {
+	GlobalLevel     string          `json:"global_level"`
+	GlobalLevelMode string          `json:"global_level_mode,omitempty"`
+	Scopes          []scopeState    `json:"scopes"`
+	Overrides       []overrideState `json:"level_overrides"` // in table order, nil in the files written before
+}
+
+// SaveState store the global level, the level and flags of every scope and the level override table in the file at
+// path, so runtime adjustments can be restored with LoadState after a restart
+func SaveState(path string) error {
+	return _defaultRegistry.SaveState(path)
+}
+
+// SaveState store the global level, the level and flags of every scope and the level override table of the registry
+// in the file at path
+func (r *Registry) SaveState(path string) error {
+	state := savedState{GlobalLevel: r._getGlobalLevel().String(), GlobalLevelMode: r._getGlobalMode().String(),
+		Overrides: []overrideState{}}
+	for _, o := range r._overrides {
+		state.Overrides = append(state.Overrides, overrideState{Scope: o.scope, Level: o.level.String()})
+	}
+	seen := map[string]bool{}
+	for _, e := range r.ListScopedLogs() {
+		if seen[e._scope()] {
+			continue
+		}
+		seen[e._scope()] = true
+		state.Scopes = append(state.Scopes, scopeState{Scope: e._scope(), Level: e.GetLevel(), Flags: e.GetFlags()})
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("elogging: save state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("elogging: save state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("elogging: save state: %w", err)
+	}
+	return nil
+}
+
+// LoadState restore the global level and mode, the level and flags of the existing Elogs and the level override
+// table from a file written by SaveState. The whole file is validated before anything is changed, the scopes with no
+// existing Elog are ignored and the override table is replaced by the saved one (kept as is with a file written
+// before the table was saved).
+func LoadState(path string) error {
+	return _defaultRegistry.LoadState(path)
+}
+
+// LoadState restore the global level and mode, the scope levels and the flags of the existing Elogs of the registry
+// from a file written by SaveState, see the package LoadState
+func (r *Registry) LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("elogging: load state: %w", err)
+	}
+	state := savedState{}
+	if err = json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("elogging: load state: %w", err)
+	}
+	if state.GlobalLevel != "" {
+		if _, err := _parseLevel(state.GlobalLevel); err != nil {
+			return fmt.Errorf("elogging: load state: unknown global level %q", state.GlobalLevel)
+		}
+	}
+	var mode GlobalLevelMode
+	if state.GlobalLevelMode != "" {
+		if mode, err = ParseGlobalLevelMode(state.GlobalLevelMode); err != nil {
+			return fmt.Errorf("elogging: load state: %w", err)
+		}
+	}
+	for _, sc := range state.Scopes {
+		if sc.Scope == "" {
+			return fmt.Errorf("elogging: load state: scope without name")
+		}
+		if _, err := _parseLevel(sc.Level); err != nil {
+			return fmt.Errorf("elogging: load state: unknown level %q of scope %s", sc.Level, sc.Scope)
+		}
+	}
+	for _, o := range state.Overrides {
+		if o.Scope == "" {
+			return fmt.Errorf("elogging: load state: level override without scope")
+		}
+		if _, err := _parseLevel(o.Level); err != nil {
+			return fmt.Errorf("elogging: load state: unknown level %q of the override of %s", o.Level, o.Scope)
+		}
+	}
+
+	if state.GlobalLevel != "" {
+		r.SetGlobalLogLevel(state.GlobalLevel)
+	}
+	if state.GlobalLevelMode != "" {
+		r.SetGlobalLevelMode(mode)
+	}
+	for _, sc := range state.Scopes {
+		for k, scope := range r._registered() {
+			if scope == sc.Scope {
+				k._setLevel(_value(_valid(sc.Level)))
+				k.SetFlags(sc.Flags)
+			}
+		}
+	}
+	if state.Overrides != nil {
+		for scope := range r.ScopeLevelOverrides() {
+			r.RemoveScopeLevelOverride(scope)
+		}
+		for _, o := range state.Overrides {
+			r.SetScopeLevelOverride(o.Scope, o.Level)
+		}
+	}
+	return nil
+}
+
+// DumpState write a human readable description of the package state and of every registered Elog to w,
+// including the time since each Elog last emitted a record so silent scopes stand out,
+// followed by the most recent records when the ring buffer is enabled
+func DumpState(w io.Writer) error {
+	return _defaultRegistry.DumpState(w)
+}
+
+// DumpState write a human readable description of the registry state and of its Elogs to w, see the package DumpState
+func (r *Registry) DumpState(w io.Writer) error {
+	now := time.Now()
+	var out bytes.Buffer // the dump is written at once so it is not interleaved with records sharing w
+	tw := tabwriter.NewWriter(&out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "logs active: %v, global level: %s, default level: %s, default flags: %#x, registered: %d\n",
+		r._active(), r._getGlobalLevel(), r._defaultLevel, r._defaultFlags, r.Size())
+	fmt.Fprintln(tw, "ID\tSCOPE\tLEVEL\tFLAGS\tLAST ACTIVE\tIDLE")
+	for _, e := range r.ListScopedLogs() {
+		last := e.LastActive()
+		fmt.Fprintf(tw, "%.8s\t%s\t%s\t%#x\t%s\t%s\n", e._id, e._scope(), e._scopeLevel(), e._getFlags(),
+			last.UTC().Format(time.RFC3339), now.Sub(last).Truncate(time.Second))
+	}
+	tw.Flush()
+	if recent := r.Query("", "", time.Time{}, ""); len(recent) > 0 {
+		if len(recent) > DumpStateRecords {
+			recent = recent[len(recent)-DumpStateRecords:]
+		}
+		fmt.Fprintf(&out, "recent records (%d):\n", len(recent))
+		for i := range recent {
+			out.Write(_formatText(nil, log.LUTC|log.Ldate|log.Lmicroseconds|log.Lshortfile, &recent[i]))
+		}
+	}
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// DumpStateRecords is the maximum number of recent records (see EnableRingBuffer) written by DumpState
+var DumpStateRecords = 20
+
+// elogState is the exported state of an Elog
+type elogState struct {
+	ID     string `json:"id"`
+	Scope  string `json:"scope"`
+	Level  string `json:"level"`
+	Flags  int    `json:"flags"`
+	Format string `json:"format"`
+	Output string `json:"output"`
+	Stats  Stats  `json:"stats"`
+}
+
+// registryState is the exported state of the package
+type registryState struct {
+	LogsActive    bool              `json:"logs_active"`
+	GlobalLevel   string            `json:"global_level"`
+	GlobalMode    string            `json:"global_level_mode"`
+	DefaultLevel  string            `json:"default_level"`
+	DefaultFlags  int               `json:"default_flags"`
+	DefaultFormat string            `json:"default_format"`
+	DefaultOutput string            `json:"default_output"`
+	Size          int               `json:"size"`
+	Limit         int               `json:"limit"`
+	Evictions     int               `json:"evictions"`
+	Overrides     map[string]string `json:"level_overrides,omitempty"`
+	StdLog        elogState         `json:"std_log"`
+	Logs          []elogState       `json:"logs"`
+}
+
+func (e *Elog) _state() elogState {
+	return elogState{
+		ID:     e._id,
+		Scope:  e._scope(),
+		Level:  e.GetLevel(),
+		Flags:  e._getFlags(),
+		Format: _formatName(e._getFlags()),
+		Output: _describeOutput(e._getOutput()),
+		Stats:  e.Stats(),
+	}
+}
+
+// StateJSON return the package state as JSON: defaults, registry counters and for every registered Elog
+// its scope, id, level, flags, format, output description and stats
+func StateJSON() ([]byte, error) {
+	return _defaultRegistry.StateJSON()
+}
+
+// StateJSON return the registry state as JSON, see the package StateJSON
+func (r *Registry) StateJSON() ([]byte, error) {
+	defaultOut := r._defaultOut
+	if defaultOut == nil {
+		defaultOut = os.Stdout
+	}
+	r._logsMu.RLock()
+	limit := r._limit
+	r._logsMu.RUnlock()
+	state := registryState{
+		LogsActive:    r._active(),
+		GlobalLevel:   r._getGlobalLevel().String(),
+		GlobalMode:    r._getGlobalMode().String(),
+		DefaultLevel:  r._defaultLevel.String(),
+		DefaultFlags:  r._defaultFlags,
+		DefaultFormat: _formatName(r._defaultFlags),
+		DefaultOutput: _describeOutput(defaultOut),
+		Size:          r.Size(),
+		Limit:         limit,
+		Evictions:     r.Evictions(),
+		Overrides:     r.ScopeLevelOverrides(),
+		StdLog:        r._stdLog._state(),
+		Logs:          []elogState{},
+	}
+	for _, e := range r.ListScopedLogs() {
+		state.Logs = append(state.Logs, e._state())
+	}
+	return json.Marshal(state)
+}
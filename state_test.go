@@ -0,0 +1,145 @@
+package elogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	r := NewRegistry()
+	elog := r.NewElog("TestSaveLoadState", "info", nil)
+	elog.SetLevel("trace")
+	elog.SetFlags(elog.GetFlags() | ELJSONLog)
+	if err := r.SaveState(path); err != nil {
+		t.Fatal(err)
+	}
+	elog.SetLevel("error")
+	elog.SetFlags(0)
+	elog.Clear()
+	elog = r.NewElog("TestSaveLoadState", "info", nil)
+	other := r.NewElog("TestSaveLoadState", "error", nil)
+	if err := r.LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+	if elog._level() != lTrace || elog.GetFlags()&ELJSONLog == 0 || other._level() != lTrace {
+		t.Errorf("state not restored: level %s flags %#x", elog._level(), elog.GetFlags())
+	}
+	if len(r.ScopeLevelOverrides()) != 0 {
+		t.Errorf("unexpected overrides %v", r.ScopeLevelOverrides())
+	}
+	if later := r.NewElog("TestSaveLoadState", "error", nil); later._level() != lError {
+		t.Errorf("later Elog pinned to %s", later._level())
+	}
+	elog.SetLevel("info")
+	if elog._level() != lInfo {
+		t.Error("restored level not changeable with SetLevel")
+	}
+}
+
+func TestSaveLoadStateOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	r := NewRegistry()
+	elog := r.NewElog("TestSaveLoadStateOverrides", "info", nil)
+	r.SetScopeLevelOverride("TestSaveLoadStateOverrides", "trace")
+	if err := r.SaveState(path); err != nil {
+		t.Fatal(err)
+	}
+	r.RemoveScopeLevelOverride("TestSaveLoadStateOverrides")
+	r.SetScopeLevelOverride("db", "error")
+	if err := r.LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+	if o := r.ScopeLevelOverrides(); len(o) != 1 || o["TestSaveLoadStateOverrides"] != "Trace" {
+		t.Errorf("unexpected overrides %v", o)
+	}
+	if elog._level() != lTrace || elog.GetLevel() != "Info" {
+		t.Errorf("unexpected levels %s %s", elog._level(), elog.GetLevel())
+	}
+
+	// a file without the override table keeps the current one
+	if err := os.WriteFile(path, []byte(`{"scopes":[{"scope":"TestSaveLoadStateOverrides","level":"error"}]}`),
+		0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+	if o := r.ScopeLevelOverrides(); len(o) != 1 || elog.GetLevel() != "Error" {
+		t.Errorf("unexpected overrides %v, level %s", o, elog.GetLevel())
+	}
+}
+
+func TestLoadStateInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	r := NewRegistry()
+	elog := r.NewElog("TestLoadStateInvalid", "info", nil)
+	for _, state := range []string{
+		`{"global_level":"loud","scopes":[{"scope":"TestLoadStateInvalid","level":"trace"}]}`,
+		`{"global_level":"trace","global_level_mode":"sideways","scopes":[{"scope":"TestLoadStateInvalid","level":"trace"}]}`,
+		`{"global_level":"trace","scopes":[{"scope":"TestLoadStateInvalid","level":"trace"},{"scope":"db","level":"chatty"}]}`,
+		`{"global_level":"trace","level_overrides":[{"scope":"TestLoadStateInvalid","level":"trace"},{"scope":"","level":"info"}]}`,
+		`{"global_level":"trace","level_overrides":[{"scope":"TestLoadStateInvalid","level":"loud"}]}`,
+	} {
+		if err := os.WriteFile(path, []byte(state), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.LoadState(path); err == nil {
+			t.Errorf("expected an error loading %s", state)
+		}
+		if elog._level() != lInfo || r._getGlobalLevel() != lDisabled || len(r.ScopeLevelOverrides()) != 0 {
+			t.Errorf("state partially applied from %s", state)
+		}
+	}
+}
+
+func TestDumpState(t *testing.T) {
+	elog := NewElog("TestDumpState", "info", &bytes.Buffer{})
+	defer elog.Clear()
+	before := elog.LastActive()
+	time.Sleep(time.Millisecond)
+	elog.Info("activity")
+	if !elog.LastActive().After(before) {
+		t.Error("expected last activity to be updated")
+	}
+
+	b := &bytes.Buffer{}
+	if err := DumpState(b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "TestDumpState") || !strings.Contains(b.String(), "LAST ACTIVE") {
+		t.Errorf("unexpected state dump %q", b.String())
+	}
+}
+
+func TestStateJSON(t *testing.T) {
+	elog := NewElog("TestStateJSON", "info", &bytes.Buffer{})
+	defer elog.Clear()
+	elog.Error("one")
+	elog.Info("two")
+	elog.Trace("filtered")
+
+	data, err := StateJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := registryState{}
+	if err = json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range state.Logs {
+		if l.Scope != "TestStateJSON" {
+			continue
+		}
+		if l.Stats.Records != 2 || l.Stats.ByLevel["Error"] != 1 || l.Output != "*bytes.Buffer" || l.Format != "text" {
+			t.Errorf("unexpected elog state %+v", l)
+		}
+		return
+	}
+	t.Errorf("scope missing from state %s", data)
+}
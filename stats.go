@@ -0,0 +1,142 @@
+package elogging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Stats are the counters of an Elog
+type Stats struct {
+	Records     uint64            `json:"records"`      // emitted records
+	ByLevel     map[string]uint64 `json:"by_level"`     // emitted records per level (Print and Fatal included)
+	WriteErrors uint64            `json:"write_errors"` // failed writes to the output
+	LastActive  time.Time         `json:"last_active"`  // time of the last emitted record
+}
+
+// Stats retrieve the counters of the Elog
+func (e *Elog) Stats() Stats {
+	st := Stats{ByLevel: map[string]uint64{}, WriteErrors: e._stats.errors(), LastActive: e.LastActive()}
+	for i, n := range e._stats.levels() {
+		if n == 0 {
+			continue
+		}
+		st.Records += n
+		st.ByLevel[(llevel(i) + lFatal).String()] = n
+	}
+	return st
+}
+
+// _maxStatShards is the maximum number of shards of the counters of an Elog, a power of two
+const _maxStatShards = 16
+
+// statShard is a set of counters padded to its own cache lines
+type statShard struct {
+	counts [_numLevels]uint64
+	errors uint64
+	last   int64 // time of the last record counted by the shard, unix nanoseconds
+	_      [128 - (_numLevels+2)*8%128]byte
+}
+
+// elogCounters are the counters of an Elog, sharded so goroutines logging concurrently on the same Elog
+// update distinct cache lines and don't serialize on the counters. The shards are allocated on their own so their
+// 64-bit counters are 64-bit aligned on 32-bit platforms too.
+type elogCounters struct {
+	shards []statShard
+	mask   uint64
+}
+
+// _newCounters return the counters of an Elog, one shard per processor up to _maxStatShards
+func _newCounters() *elogCounters {
+	n := 1
+	for n < runtime.GOMAXPROCS(0) && n < _maxStatShards {
+		n *= 2
+	}
+	return &elogCounters{shards: make([]statShard, n), mask: uint64(n - 1)}
+}
+
+// _statShard return the shard of the calling goroutine among mask+1: goroutines run on distinct stacks so the
+// address of a local variable spreads them over the shards without any shared state
+func _statShard(mask uint64) int {
+	var x byte
+	return int(uint64(uintptr(unsafe.Pointer(&x))>>11) * 0x9e3779b97f4a7c15 >> 32 & mask)
+}
+
+func (c *elogCounters) count(level llevel) {
+	atomic.AddUint64(&c.shards[_statShard(c.mask)].counts[level-lFatal], 1)
+}
+
+func (c *elogCounters) writeError() {
+	atomic.AddUint64(&c.shards[_statShard(c.mask)].errors, 1)
+}
+
+// touch record the time of the last emitted record
+func (c *elogCounters) touch(t time.Time) {
+	atomic.StoreInt64(&c.shards[_statShard(c.mask)].last, t.UnixNano())
+}
+
+// lastActive return the time of the last emitted record, the latest of the shards
+func (c *elogCounters) lastActive() time.Time {
+	var last int64
+	for i := range c.shards {
+		if n := atomic.LoadInt64(&c.shards[i].last); n > last {
+			last = n
+		}
+	}
+	return time.Unix(0, last)
+}
+
+// levels return the number of records per level
+func (c *elogCounters) levels() (counts [_numLevels]uint64) {
+	for i := range c.shards {
+		for l := range counts {
+			counts[l] += atomic.LoadUint64(&c.shards[i].counts[l])
+		}
+	}
+	return counts
+}
+
+// errors return the number of failed writes
+func (c *elogCounters) errors() (n uint64) {
+	for i := range c.shards {
+		n += atomic.LoadUint64(&c.shards[i].errors)
+	}
+	return n
+}
+
+// _formatName return the name of the format selected by the flags
+func _formatName(flags int) string {
+	switch {
+	case flags&ELJSONLog != 0:
+		return "json"
+	case flags&ELColorLog != 0:
+		return "color"
+	}
+	return "text"
+}
+
+// _describeOutput return a short description of an output
+func _describeOutput(w io.Writer) string {
+	switch o := w.(type) {
+	case nil:
+		return "none"
+	case *os.File:
+		switch o {
+		case os.Stdout:
+			return "stdout"
+		case os.Stderr:
+			return "stderr"
+		}
+		return "file:" + o.Name()
+	case *_outputFile:
+		return "file:" + o.Name()
+	case *RotatingFile:
+		return "file:" + o.Path() + "?rotate=" + strconv.FormatInt(o.maxSize, 10)
+	}
+	return fmt.Sprintf("%T", w)
+}
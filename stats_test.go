@@ -0,0 +1,47 @@
+package elogging
+
+import (
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestStatsConcurrent(t *testing.T) {
+	elog := NewEphemeralElog("TestStatsConcurrent", "info", io.Discard)
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				elog.Info("msg")
+				elog.Error("msg")
+			}
+		}()
+	}
+	wg.Wait()
+	st := elog.Stats()
+	if st.Records != 6400 || st.ByLevel["Info"] != 3200 || st.ByLevel["Error"] != 3200 || st.WriteErrors != 0 {
+		t.Errorf("unexpected stats %+v", st)
+	}
+	if unsafe.Sizeof(statShard{})%64 != 0 {
+		t.Errorf("shard size %d is not a multiple of the cache line size", unsafe.Sizeof(statShard{}))
+	}
+	if n := len(elog._stats.shards); n > _maxStatShards || n&(n-1) != 0 || (n < runtime.GOMAXPROCS(0) && n < _maxStatShards) {
+		t.Errorf("unexpected shard count %d", n)
+	}
+	if i := _statShard(elog._stats.mask); i < 0 || i >= len(elog._stats.shards) {
+		t.Errorf("shard %d out of range", i)
+	}
+}
+
+func BenchmarkStatsParallel(b *testing.B) {
+	elog := NewEphemeralElog("BenchmarkStatsParallel", "info", io.Discard)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			elog._stats.count(lInfo)
+		}
+	})
+}
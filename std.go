@@ -0,0 +1,139 @@
+package elogging
+
+import (
+	"fmt"
+	"os"
+)
+
+// StdLog return the default log of the registry, it writes to stderr like the golang log package default logger
+// and is not listed with the Elogs of the registry
+func (r *Registry) StdLog() *Elog {
+	return r._stdLog
+}
+
+// StdLog return the package default log, it can be configured like any other Elog
+// (flags, format, output, level) and is used by the package level logging functions
+func StdLog() *Elog {
+	return _defaultRegistry._stdLog
+}
+
+// Print print prefixed (Print) log lines to the default log ignoring the leveled logging mechanism
+func Print(args ...interface{}) {
+	if !_defaultRegistry._stdLog._printEnabled() {
+		_defaultRegistry._stdLog._debugDropped(lPrint)
+		return
+	}
+	_defaultRegistry._stdLog._output(2, lPrint, "Print", fmt.Sprint(_defaultRegistry._renderArgs(args)...))
+}
+
+// Printf print prefixed (Printf) log lines to the default log ignoring the leveled logging mechanism
+func Printf(format string, args ...interface{}) {
+	if !_defaultRegistry._stdLog._printEnabled() {
+		_defaultRegistry._stdLog._debugDropped(lPrint)
+		return
+	}
+	_defaultRegistry._stdLog._output(2, lPrint, "Printf", fmt.Sprintf(format, _defaultRegistry._renderArgs(args)...))
+}
+
+// Println print prefixed (Println) log lines to the default log ignoring the leveled logging mechanism
+func Println(args ...interface{}) {
+	if !_defaultRegistry._stdLog._printEnabled() {
+		_defaultRegistry._stdLog._debugDropped(lPrint)
+		return
+	}
+	_defaultRegistry._stdLog._output(2, lPrint, "Println", fmt.Sprintln(_defaultRegistry._renderArgs(args)...))
+}
+
+// Fatal print a prefixed (Fatal) log line to the default log and exit the program with status 1
+func Fatal(args ...interface{}) {
+	_defaultRegistry._stdLog._output(2, lFatal, "Fatal", fmt.Sprint(_defaultRegistry._renderArgs(args)...))
+	os.Exit(1)
+}
+
+// Fatalf print a prefixed (Fatal) formatted log line to the default log and exit the program with status 1
+func Fatalf(format string, args ...interface{}) {
+	_defaultRegistry._stdLog._output(2, lFatal, "Fatal", fmt.Sprintf(format, _defaultRegistry._renderArgs(args)...))
+	os.Exit(1)
+}
+
+// Fatalln print a prefixed (Fatal) log line to the default log and exit the program with status 1
+func Fatalln(args ...interface{}) {
+	_defaultRegistry._stdLog._output(2, lFatal, "Fatal", fmt.Sprintln(_defaultRegistry._renderArgs(args)...))
+	os.Exit(1)
+}
+
+// Panic print a prefixed (Panic) log line to the default log and panic with the message
+func Panic(args ...interface{}) {
+	s := fmt.Sprint(_defaultRegistry._renderArgs(args)...)
+	_defaultRegistry._stdLog._output(2, lFatal, "Panic", s)
+	panic(s)
+}
+
+// Panicf print a prefixed (Panic) formatted log line to the default log and panic with the message
+func Panicf(format string, args ...interface{}) {
+	s := fmt.Sprintf(format, _defaultRegistry._renderArgs(args)...)
+	_defaultRegistry._stdLog._output(2, lFatal, "Panic", s)
+	panic(s)
+}
+
+// Panicln print a prefixed (Panic) log line to the default log and panic with the message
+func Panicln(args ...interface{}) {
+	s := fmt.Sprintln(_defaultRegistry._renderArgs(args)...)
+	_defaultRegistry._stdLog._output(2, lFatal, "Panic", s)
+	panic(s)
+}
+
+// Errorf print prefixed (Error) formatted log lines to the default log with level Error
+func Errorf(format string, args ...interface{}) {
+	_defaultRegistry._stdLog._logf(2, lError, format, args...)
+}
+
+// Warnf print prefixed (Warning) formatted log lines to the default log with level Warning
+func Warnf(format string, args ...interface{}) {
+	_defaultRegistry._stdLog._logf(2, lWarn, format, args...)
+}
+
+// Infof print prefixed (Info) formatted log lines to the default log with level Info
+func Infof(format string, args ...interface{}) {
+	_defaultRegistry._stdLog._logf(2, lInfo, format, args...)
+}
+
+// Verbosef print prefixed (Verbose) formatted log lines to the default log with level Verbose
+func Verbosef(format string, args ...interface{}) {
+	_defaultRegistry._stdLog._logf(2, lVerbose, format, args...)
+}
+
+// Tracef print prefixed (Trace) formatted log lines to the default log with level Trace
+func Tracef(format string, args ...interface{}) {
+	_defaultRegistry._stdLog._logf(2, lTrace, format, args...)
+}
+
+// Error print prefixed (Error) log lines to the default log with level Error
+func Error(args ...interface{}) {
+	_defaultRegistry._stdLog._log(2, lError, args...)
+}
+
+// Warn print prefixed (Warning) log lines to the default log with level Warning
+func Warn(args ...interface{}) {
+	_defaultRegistry._stdLog._log(2, lWarn, args...)
+}
+
+// Info print prefixed (Info) log lines to the default log with level Info
+func Info(args ...interface{}) {
+	_defaultRegistry._stdLog._log(2, lInfo, args...)
+}
+
+// Verbose print prefixed (Verbose) log lines to the default log with level Verbose
+func Verbose(args ...interface{}) {
+	_defaultRegistry._stdLog._log(2, lVerbose, args...)
+}
+
+// Trace print prefixed (Trace) log lines to the default log with level Trace
+func Trace(args ...interface{}) {
+	_defaultRegistry._stdLog._log(2, lTrace, args...)
+}
+
+// SetStdLogLevel change the level of the package default log
+func SetStdLogLevel(level string) {
+	_defaultRegistry._stdLog.SetLevel(level)
+}
@@ -0,0 +1,49 @@
+package elogging
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestStdLogLeveled(t *testing.T) {
+	b := &bytes.Buffer{}
+	defer _defaultRegistry._stdLog.ModifyParams("", "", _defaultRegistry._stdLog._out)
+	_defaultRegistry._stdLog.ModifyParams("", "info", b)
+	flags := _defaultRegistry._stdLog.GetFlags()
+	defer _defaultRegistry._stdLog.SetFlags(flags)
+	_defaultRegistry._stdLog.SetFlags(log.Lshortfile)
+
+	Infof("info %d", 1)
+	Verbosef("verbose %d", 2)
+	Print("print")
+	out := b.String()
+	if !strings.Contains(out, "std_test.go:") || !strings.Contains(out, "(INFO) info 1") {
+		t.Errorf("unexpected std log output %q", out)
+	}
+	if strings.Contains(out, "verbose 2") {
+		t.Error("unexpected verbose message")
+	}
+	if !strings.Contains(out, "(Print) print") {
+		t.Error("expected print message")
+	}
+}
+
+func TestStdLogStructured(t *testing.T) {
+	b := &bytes.Buffer{}
+	std := StdLog()
+	defer std.ModifyParams("", "", std._out)
+	std.ModifyParams("", "info", b)
+	defer SetDefaultFlags(DefaultFlags())
+	flags := std.GetFlags()
+	defer std.SetFlags(flags)
+
+	if err := SetGlobalFormat("json"); err != nil {
+		t.Fatal(err)
+	}
+	Warnf("structured %s", "warning")
+	if !strings.HasPrefix(b.String(), "{") || !strings.Contains(b.String(), `"msg":"structured warning"`) {
+		t.Errorf("expected json output from the default log, got %q", b.String())
+	}
+}
@@ -0,0 +1,131 @@
+package elogging
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// SuppressionPolicy set the summary written after a run of repeated records of an Elog with the ELSuppressRepeated
+// flag, e.g. with the default policy, when a different record ends the run:
+//  previous message repeated 41 times total: "disk full on /var" (first seen 2024-05-01T12:00:00.000Z)
+// and when the run is flushed (FlushRepeated, Shutdown, Clear or the flag turned off) while still going on:
+//  last message repeated 41 times: "disk full on /var" (first seen 2024-05-01T12:00:00.000Z)
+type SuppressionPolicy struct {
+	MessageLen int    // runes of the repeated message quoted in the summary, 0 for 80, < 0 to leave the message out
+	TimeLayout string // layout of the first seen time in the summary, "" for RFC3339 with milliseconds, "-" to leave it out
+	Fields     bool   // add the count and the first seen time as fields (repeated, first_seen) for structured outputs
+}
+
+const (
+	_defaultSuppressLen    = 80
+	_defaultSuppressLayout = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// SetSuppressionPolicy set the summary of the repeated records of the Elogs of the registry,
+// see the package SetSuppressionPolicy
+func (r *Registry) SetSuppressionPolicy(p SuppressionPolicy) {
+	if !r._allow("", "suppression_policy") {
+		return
+	}
+	r._audit("", "suppression_policy", fmt.Sprintf("%+v", r._suppression), fmt.Sprintf("%+v", p))
+	r._suppression = p
+}
+
+// SetSuppressionPolicy set the summary written after a run of records repeated consecutively by an Elog with the
+// ELSuppressRepeated flag: the summary has the level, scope and caller of the repeated record and quotes (a truncated
+// form of) its message with the time it was first seen, so a grep for the message finds the summary too.
+// The records are compared once rendered, the lazy values (see Lazy) of a suppressed record are still computed.
+func SetSuppressionPolicy(p SuppressionPolicy) {
+	_defaultRegistry.SetSuppressionPolicy(p)
+}
+
+// _repeatRun is the run of repeated records of an Elog
+type _repeatRun struct {
+	mu    sync.Mutex
+	key   string // message and fields of the last record written
+	last  Record // the last record written, without its fields
+	count int    // records suppressed since
+}
+
+// _repeatKey return the message and the rendered fields of a record, identical for repeated records
+func _repeatKey(rec *Record) string {
+	buf := append([]byte(nil), rec.Msg...)
+	for i := range rec.Fields {
+		buf = append(append(append(buf, 0), rec.Fields[i].Key...), '=')
+		buf = _appendValueRaw(buf, &rec.Fields[i])
+	}
+	return string(buf)
+}
+
+// check suppress a record repeating the last record written, or return the closing summary of the run it ends (nil
+// when there was no repeat) so the count of a run is never lost: the summary is written before the record
+func (r *_repeatRun) check(rec *Record, p SuppressionPolicy) (summary *Record, repeated bool) {
+	key := _repeatKey(rec)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key == r.key && rec.level == r.last.level && rec.Scope == r.last.Scope {
+		r.count++
+		return nil, true
+	}
+	if r.count > 0 {
+		summary = r._summary(p, rec.Time, true)
+	}
+	r.key, r.count = key, 0
+	r.last = Record{Time: rec.Time, Scope: rec.Scope, Tag: rec.Tag, File: rec.File, Line: rec.Line, Func: rec.Func,
+		Msg: rec.Msg, Labels: rec.Labels, level: rec.level, bare: rec.bare}
+	return summary, false
+}
+
+// flush end the current run, returning its summary (nil when there was no repeat)
+func (r *_repeatRun) flush(p SuppressionPolicy) (summary *Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.count > 0 {
+		summary = r._summary(p, time.Now(), false)
+	}
+	r.key, r.count = "", 0
+	return summary
+}
+
+// _summary build the summary record of the run, closing when a different record ended it
+func (r *_repeatRun) _summary(p SuppressionPolicy, now time.Time, closing bool) *Record {
+	rec := r.last
+	rec.Time = now
+	msg := []byte("last message repeated " + strconv.Itoa(r.count) + " times")
+	if closing {
+		msg = []byte("previous message repeated " + strconv.Itoa(r.count) + " times total")
+	}
+	if n := p.MessageLen; n >= 0 {
+		if n == 0 {
+			n = _defaultSuppressLen
+		}
+		m := r.last.Msg
+		if utf8.RuneCountInString(m) > n {
+			m = string([]rune(m)[:n]) + "…"
+		}
+		msg = strconv.AppendQuote(append(msg, ": "...), m)
+	}
+	if layout := p.TimeLayout; layout != "-" {
+		if layout == "" {
+			layout = _defaultSuppressLayout
+		}
+		msg = append(append(append(msg, " (first seen "...), r.last.Time.Format(layout)...), ')')
+	}
+	rec.Msg = string(msg)
+	if p.Fields {
+		rec.Fields = []FieldT{Int("repeated", r.count), Time("first_seen", r.last.Time)}
+	}
+	return &rec
+}
+
+// FlushRepeated write the summary of the current run of repeated records of the Elog (see ELSuppressRepeated),
+// usually written when a different record ends the run; Shutdown flush the runs of all the Elogs, Clear and SetFlags
+// turning the flag off the run of the Elog
+func (e *Elog) FlushRepeated() {
+	if summary := e._repeat.flush(e._reg._suppression); summary != nil {
+		e._writeRecord(2, *summary)
+	}
+}
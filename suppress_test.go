@@ -0,0 +1,48 @@
+package elogging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSuppressRepeated(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestSuppressRepeated", "info", b)
+	elog.SetFlags(ELSuppressRepeated)
+	first := time.Now()
+	for i := 0; i < 4; i++ {
+		elog.Error("disk full on /var")
+	}
+	elog.Info("cleaned up")
+	elog.Info("cleaned up")
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", lines)
+	}
+	summary := `TestSuppressRepeated (ERROR) previous message repeated 3 times total: "disk full on /var" (first seen ` +
+		first.Format("2006-01-02T")
+	if !strings.HasPrefix(lines[1], summary) || lines[2] != "TestSuppressRepeated (INFO) cleaned up" {
+		t.Errorf("unexpected lines %q", lines)
+	}
+
+	b.Reset()
+	r.SetSuppressionPolicy(SuppressionPolicy{MessageLen: 7, TimeLayout: "-"})
+	r.Shutdown(context.Background())
+	if expected := "TestSuppressRepeated (INFO) last message repeated 1 times: \"cleaned…\"\n"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	elog.Warn("retrying")
+	elog.Warn("retrying")
+	elog.SetFlags(0)
+	elog.Warn("retrying")
+	if expected := "TestSuppressRepeated (WARN) retrying\nTestSuppressRepeated (WARN) last message repeated 1 times: \"retryin…\"\n" +
+		"TestSuppressRepeated (WARN) retrying\n"; b.String() != expected {
+		t.Errorf("expected the run flushed when the flag is turned off, got %q", b.String())
+	}
+}
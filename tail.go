@@ -0,0 +1,92 @@
+package elogging
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// TailBufferSize is the number of records a Tail keeps for its reader before dropping records
+var TailBufferSize = 256
+
+// Tail is an io.ReadCloser streaming the records of an Elog, see TailReader
+type Tail struct {
+	e        *Elog
+	minLevel llevel
+	ch       chan []byte
+	pending  []byte
+	mu       sync.Mutex
+	closed   bool
+	dropped  uint64
+	lost     uint64 // dropped records not yet reported to the reader
+}
+
+// TailReader return a reader streaming the records of the Elog at or above level (rendered with the Elog format)
+// from now on. A reader not keeping up loses records: when its buffer (TailBufferSize records) is full new records
+// are dropped and a line reporting the number of dropped records is inserted once there is room again.
+// Close the reader to stop streaming.
+func (e *Elog) TailReader(level string) *Tail {
+	t := &Tail{e: e, minLevel: _value(_valid(level)), ch: make(chan []byte, TailBufferSize)}
+	e.AddSink(t)
+	return t
+}
+
+// WriteRecord queue the rendered record for the reader, dropping it when the reader is too slow
+func (t *Tail) WriteRecord(rec *Record) error {
+	if !_passes(rec.level, t.minLevel) {
+		return nil
+	}
+	buf := t.e._format(rec)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	if t.lost > 0 {
+		select {
+		case t.ch <- []byte(fmt.Sprintf("elogging: tail dropped %d records\n", t.lost)):
+			t.lost = 0
+		default:
+		}
+	}
+	select {
+	case t.ch <- buf:
+	default:
+		t.dropped++
+		t.lost++
+	}
+	return nil
+}
+
+// Read read the streamed records, blocking until a record is available, io.EOF is returned once closed
+func (t *Tail) Read(p []byte) (int, error) {
+	if len(t.pending) == 0 {
+		buf, ok := <-t.ch
+		if !ok {
+			return 0, io.EOF
+		}
+		t.pending = buf
+	}
+	n := copy(p, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
+}
+
+// Dropped return the number of records dropped because the reader was too slow
+func (t *Tail) Dropped() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.dropped
+}
+
+// Close stop streaming, the records already queued can still be read
+func (t *Tail) Close() error {
+	t.e.RemoveSink(t)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.closed {
+		t.closed = true
+		close(t.ch)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package elogging
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTailReader(t *testing.T) {
+	defer func(size int) { TailBufferSize = size }(TailBufferSize)
+	TailBufferSize = 2
+
+	elog := NewElog("TestTailReader", "trace", &bytes.Buffer{})
+	defer elog.Clear()
+	elog.SetFlags(0)
+	tail := elog.TailReader("warning")
+	elog.Info("not tailed")
+	elog.Error("first")
+	elog.Warn("second")
+	elog.Error("dropped")
+	if tail.Dropped() != 1 {
+		t.Errorf("expected 1 dropped record, got %d", tail.Dropped())
+	}
+	buf := make([]byte, 64)
+	n, _ := tail.Read(buf)
+	if string(buf[:n]) != "TestTailReader (ERROR) first\n" {
+		t.Errorf("unexpected tailed record %q", buf[:n])
+	}
+	elog.Error("third")
+	tail.Close()
+	elog.Error("after close")
+
+	rest, err := io.ReadAll(tail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "TestTailReader (WARN) second\nelogging: tail dropped 1 records\n"
+	if !strings.HasPrefix(string(rest), expected) || strings.Contains(string(rest), "after close") {
+		t.Errorf("unexpected tail content %q", rest)
+	}
+}
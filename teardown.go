@@ -0,0 +1,99 @@
+package elogging
+
+import (
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// ClearAll close and unregister every Elog of the registry, see the package ClearAll
+func (r *Registry) ClearAll() {
+	for e := range r._registered() {
+		for _, s := range e._getSinks() {
+			if c, ok := s.(io.Closer); ok {
+				c.Close()
+			}
+		}
+		e.Clear()
+	}
+	for _, c := range r._opened {
+		c.Close()
+		w, ok := c.(io.Writer)
+		if !ok {
+			continue
+		}
+		if r._defaultOut == w {
+			r._defaultOut = nil
+		}
+		if std := r._stdLog; std != nil && std._getOutput() == w {
+			std._setOutput(os.Stderr)
+		}
+	}
+	r._opened = nil
+	r._files.mu.Lock()
+	r._files.files = nil
+	r._files.mu.Unlock()
+}
+
+// ClearAll close and unregister every registered Elog: sinks implementing io.Closer are closed, each Elog is
+// cleared (see Clear) and the outputs opened by the package itself (configuration, flags) are closed, the default
+// output and the output of the package default log are reset to stdout and stderr when they were one of them.
+// Outputs given by the caller are left open.
+func ClearAll() {
+	_defaultRegistry.ClearAll()
+}
+
+// Reset restore the registry defaults, see the package Reset, registered Elogs are kept and a frozen registry is left as is
+func (r *Registry) Reset() {
+	if !r._allow("", "reset") {
+		return
+	}
+	r._defaultFlags = _initialFlags
+	r._defaultOut = nil
+	r._scopeDir = outputSpec{}
+	r._defaultLevel = lInfo
+	r._setGlobalLevel(lDisabled)
+	r._setGlobalMode(GlobalFloor)
+	r._setActive(true, lDisabled)
+
+	r._warn, r._warned = 0, false
+	r._limit, r._limitHit, r._evictions = 0, false, 0
+	r._defaultFields = nil
+	r._scopeNaming = nil
+	r._duplicatePolicy = DuplicateAllow
+	r._aliases = map[string]string{}
+
+	r._ring = nil
+	r._setBootstrap(nil)
+	r._traceFilter = nil
+	r._schemas = map[string]*Schema{}
+	r._schemaSeen.reset()
+	r._keyCase, r._keyCollision = KeyCaseAsIs, KeysKeepAll
+	r._bytesMode, r._bytesMax = BytesHex, _defaultBytesMax
+	r._utf8Repair = false
+	r._version.Store((*versionField)(nil))
+	r._traceExtractor, r._traceSampling = nil, false
+	r._tenantExtractor = nil
+	r._misuse = nil
+	r._suppression = SuppressionPolicy{}
+	r._closers.mu.Lock()
+	r._closers.closers = nil
+	r._closers.mu.Unlock()
+	r._debug = nil
+	r._auditLog = nil
+	r._overrides = nil
+	r._applyOverrides()
+	r._stdLog = r._newElog("", "", os.Stderr)
+}
+
+// Reset restore the package defaults: default flags, output and level, global level, logs on, the package default log,
+// the registry settings (limit, warning threshold, duplicate scope policy, aliases), the ring buffer, level overrides, trim prefixes, the version field and atomic writers.
+// Registered Elogs are kept, use ClearAll first for a pristine package.
+func Reset() {
+	_defaultRegistry.Reset()
+	_trimPrefixes = nil
+	_atomicMu.Lock()
+	_atomicWriters = map[io.Writer]bool{}
+	atomic.StoreInt32(&_atomicCount, 0)
+	_atomicMu.Unlock()
+}
@@ -0,0 +1,45 @@
+package elogging
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestClearAllAndReset(t *testing.T) {
+	saved := _defaultRegistry._logs
+	_defaultRegistry._logs = map[*Elog]string{}
+	defer func() { _defaultRegistry._logs = saved }()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := Configure("json,level=trace,out=" + path); err != nil {
+		t.Fatal(err)
+	}
+	a := NewElogDefaults("TestClearAll.a")
+	out := a._out.(*_outputFile)
+	tail := a.TailReader("trace")
+	NewElogDefaults("TestClearAll.b")
+	AliasScope("tca", "TestClearAll.a")
+
+	ClearAll()
+	if RegistrySize() != 0 {
+		t.Errorf("expected an empty registry, got %v", ListScopedLogs())
+	}
+	if _, err := tail.Read(make([]byte, 1)); err == nil {
+		t.Error("expected the tail sink to be closed")
+	}
+	if _, err := out.Write(nil); err == nil {
+		t.Error("expected the configured output to be closed")
+	}
+	if abs, _ := filepath.Abs(path); _isActive(abs) {
+		t.Error("expected the configured output to be no longer active")
+	}
+	if _defaultRegistry._defaultOut != nil {
+		t.Error("expected the default output on the closed file to be reset")
+	}
+
+	Reset()
+	if DefaultFlags() != _initialFlags || DefaultLevel() != "Info" || _defaultRegistry._getGlobalLevel() != lDisabled || _defaultRegistry._defaultOut != nil ||
+		ResolveScope("tca") != "tca" {
+		t.Error("package defaults not restored")
+	}
+}
@@ -0,0 +1,50 @@
+package elogging
+
+import "time"
+
+// _appendRFC3339 append t in RFC3339 with digits (0 to 9) fractional second digits, with 9 digits the trailing
+// zeros are trimmed like time.RFC3339Nano; hand-rolled to stay allocation free and locale independent
+func _appendRFC3339(buf []byte, t time.Time, digits int) []byte {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	_itoa(&buf, year, 4)
+	buf = append(buf, '-')
+	_itoa(&buf, int(month), 2)
+	buf = append(buf, '-')
+	_itoa(&buf, day, 2)
+	buf = append(buf, 'T')
+	_itoa(&buf, hour, 2)
+	buf = append(buf, ':')
+	_itoa(&buf, min, 2)
+	buf = append(buf, ':')
+	_itoa(&buf, sec, 2)
+	if digits > 0 {
+		ns := t.Nanosecond()
+		for i := digits; i < 9; i++ {
+			ns /= 10
+		}
+		if digits == 9 {
+			for ns != 0 && ns%10 == 0 {
+				ns /= 10
+				digits--
+			}
+		}
+		if ns != 0 || digits < 9 {
+			buf = append(buf, '.')
+			_itoa(&buf, ns, digits)
+		}
+	}
+	_, offset := t.Zone()
+	if offset == 0 {
+		return append(buf, 'Z')
+	}
+	sign := byte('+')
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	buf = append(buf, sign)
+	_itoa(&buf, offset/3600, 2)
+	buf = append(buf, ':')
+	_itoa(&buf, offset%3600/60, 2)
+	return buf
+}
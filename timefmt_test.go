@@ -0,0 +1,72 @@
+package elogging
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestAppendRFC3339(t *testing.T) {
+	zone := time.FixedZone("", -(3*3600 + 30*60))
+	for _, tm := range []time.Time{
+		time.Date(2024, 5, 1, 13, 4, 5, 0, time.UTC),
+		time.Date(2024, 5, 1, 13, 4, 5, 120000000, time.UTC),
+		time.Date(1999, 12, 31, 23, 59, 59, 999999999, zone),
+	} {
+		if got, expected := string(_appendRFC3339(nil, tm, 9)), tm.Format(time.RFC3339Nano); got != expected {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+		if got, expected := string(_appendRFC3339(nil, tm, 6)), tm.Format("2006-01-02T15:04:05.000000Z07:00"); got != expected {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+		if got, expected := string(_appendRFC3339(nil, tm, 0)), tm.Format(time.RFC3339); got != expected {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+	}
+	buf := make([]byte, 0, 64)
+	if n := testing.AllocsPerRun(100, func() { _appendRFC3339(buf[:0], time.Now(), 9) }); n != 0 {
+		t.Errorf("expected no allocation, got %v", n)
+	}
+}
+
+func TestTimeFlags(t *testing.T) {
+	rec := &Record{Time: time.Date(2024, 5, 1, 13, 4, 5, 123456789, time.UTC), Scope: "s", Tag: "INFO", Msg: "m"}
+	for flags, expected := range map[int]string{
+		log.Ldate | log.Lmicroseconds | ELTimeRFC3339: "2024-05-01T13:04:05.123456Z s (INFO) m\n",
+		log.Ltime | ELTimeRFC3339:                     "2024-05-01T13:04:05Z s (INFO) m\n",
+		log.Ltime | ELTimeEpoch:                       "1714568645123 s (INFO) m\n",
+		log.Ltime | ELTimeEpoch | ELJSONLog:           `{"time":1714568645123,"scope":"s","level":"INFO","msg":"m"}` + "\n",
+		log.Ltime | ELTimeNanos:                       "13:04:05.123456789 s (INFO) m\n",
+		log.Ltime | ELTimeMillis:                      "13:04:05.123 s (INFO) m\n",
+		ELTimeMillis:                                  "13:04:05.123 s (INFO) m\n",
+		log.Ltime | ELTimeNanos | ELTimeRFC3339:       "2024-05-01T13:04:05.123456789Z s (INFO) m\n",
+		log.Ltime | ELTimeNanos | ELTimeEpoch:         "1714568645123456789 s (INFO) m\n",
+		log.Ltime | ELTimeMillis | ELJSONLog:          `{"time":"2024-05-01T13:04:05.123Z","scope":"s","level":"INFO","msg":"m"}` + "\n",
+	} {
+		if got := string(_encodeFlags(nil, flags|log.Lmsgprefix, rec)); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	}
+	if !bytes.Contains(_encodeFlags(nil, log.Ltime|ELJSONLog, rec), []byte(`"time":"2024-05-01T13:04:05.123456789Z"`)) {
+		t.Error("unexpected json time")
+	}
+}
+
+func TestTimeNanosParse(t *testing.T) {
+	tm := time.Date(2024, 5, 1, 13, 4, 5, 123456789, time.UTC)
+	line := _encodeFlags(nil, log.LstdFlags|log.LUTC|ELTimeNanos, &Record{Time: tm, Scope: "s", Tag: "INFO", Msg: "m"})
+	rec, err := ParseLine(string(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Time.Equal(tm) {
+		t.Errorf("expected %v, got %v", tm, rec.Time)
+	}
+	if got, _ := _replayTime("1714568645123456789"); !got.Equal(tm) {
+		t.Errorf("expected %v, got %v", tm, got)
+	}
+	if got, _ := _replayTime("1714568645123"); !got.Equal(tm.Truncate(time.Millisecond)) {
+		t.Errorf("expected %v, got %v", tm.Truncate(time.Millisecond), got)
+	}
+}
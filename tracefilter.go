@@ -0,0 +1,63 @@
+package elogging
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// SetTraceFilter restrict the trace records of the Elogs of the registry to call sites matching a pattern,
+// see the package SetTraceFilter
+func (r *Registry) SetTraceFilter(patterns ...string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("elogging: invalid trace filter %q: %w", p, err)
+		}
+	}
+	r._traceFilter = append([]string(nil), patterns...)
+	return nil
+}
+
+// SetTraceFilter additionally gate the trace records by their call site: a trace record enabled by the levels is
+// only emitted when its file or its function matches one of the patterns (path.Match syntax matched against the
+// trailing path elements), e.g. "*/transport/*.go", "transport.(*Conn).*" or "*.go" for all; no pattern remove the filter
+func SetTraceFilter(patterns ...string) error {
+	return _defaultRegistry.SetTraceFilter(patterns...)
+}
+
+// _traceAllowed report whether a record at level emitted from the call site at calldepth passes the trace filter
+func (e *Elog) _traceAllowed(calldepth int, level llevel) bool {
+	filter := e._reg._traceFilter
+	if level != lTrace || len(filter) == 0 {
+		return true
+	}
+	pc, file, _, ok := runtime.Caller(calldepth)
+	if !ok {
+		return false
+	}
+	var fn string
+	if f := runtime.FuncForPC(pc); f != nil {
+		fn = f.Name()
+	}
+	for _, p := range filter {
+		if _matchTail(p, file) || _matchTail(p, fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// _matchTail report whether the pattern matches s or a trailing part of s starting after a '/'
+func _matchTail(pattern, s string) bool {
+	for {
+		if ok, _ := path.Match(pattern, s); ok {
+			return true
+		}
+		i := strings.IndexByte(s, '/')
+		if i < 0 {
+			return false
+		}
+		s = s[i+1:]
+	}
+}
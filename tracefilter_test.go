@@ -0,0 +1,31 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTraceFilter(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestTraceFilter", "trace", b)
+	if err := r.SetTraceFilter("["); err == nil {
+		t.Error("expected an invalid pattern error")
+	}
+
+	r.SetTraceFilter("*/transport/*.go")
+	elog.Trace("filtered out")
+	elog.Verbose("not filtered")
+	r.SetTraceFilter("*/tracefilter_test.go")
+	elog.Trace("file match")
+	r.SetTraceFilter("elogging.TestTraceFilter")
+	elog.TraceKV("function match")
+	r.SetTraceFilter()
+	elog.Tracef("no filter")
+
+	out := b.String()
+	if strings.Contains(out, "filtered out") || strings.Count(out, "\n") != 4 {
+		t.Errorf("unexpected filtered output %q", out)
+	}
+}
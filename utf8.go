@@ -0,0 +1,41 @@
+package elogging
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// SetUTF8Repair enable (or disable) the repair of invalid UTF-8 in the records of the Elogs of the registry
+func (r *Registry) SetUTF8Repair(on bool) {
+	r._utf8Repair = on
+}
+
+// SetUTF8Repair enable (or disable) the validation of the messages and of the string fields (keys and values)
+// of the records before they are written, invalid UTF-8 sequences are replaced with U+FFFD since some sinks
+// (JSON collectors, journald) reject or mangle such records
+func SetUTF8Repair(on bool) {
+	_defaultRegistry.SetUTF8Repair(on)
+}
+
+// _repairUTF8 replace the invalid UTF-8 sequences of the message and of the string fields of the record
+func _repairUTF8(rec *Record) {
+	if !utf8.ValidString(rec.Msg) {
+		rec.Msg = strings.ToValidUTF8(rec.Msg, "\ufffd")
+	}
+	var fields []FieldT
+	for i := range rec.Fields {
+		f := rec.Fields[i]
+		if utf8.ValidString(f.Key) && (f.kind != kindString || utf8.ValidString(f.str)) {
+			continue
+		}
+		if fields == nil {
+			fields = append([]FieldT(nil), rec.Fields...)
+		}
+		f.Key = strings.ToValidUTF8(f.Key, "\ufffd")
+		f.str = strings.ToValidUTF8(f.str, "\ufffd")
+		fields[i] = f
+	}
+	if fields != nil {
+		rec.Fields = fields
+	}
+}
@@ -0,0 +1,25 @@
+package elogging
+
+import (
+	"bytes"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestUTF8Repair(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestUTF8Repair", "info", b)
+	elog.SetFlags(ELJSONLog)
+	r.SetUTF8Repair(true)
+
+	fields := []FieldT{String("name\xff", "bad\xc3"), Int("n", 1)}
+	elog.InfoKV("invalid \xfe message", fields...)
+	if !utf8.Valid(b.Bytes()) || !bytes.Contains(b.Bytes(), []byte("invalid \ufffd message")) ||
+		!bytes.Contains(b.Bytes(), []byte(`"name`+"\ufffd"+`":"bad`+"\ufffd"+`"`)) {
+		t.Errorf("unexpected output %q", b.String())
+	}
+	if fields[0].Key != "name\xff" {
+		t.Error("the caller fields must not be modified")
+	}
+}
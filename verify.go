@@ -0,0 +1,83 @@
+package elogging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// SinkVerifier is implemented by sinks able to check their destination without writing a probe record
+type SinkVerifier interface {
+	VerifySink() error
+}
+
+// VerifySinks check every sink of the Elogs of the registry, see the package VerifySinks
+func (r *Registry) VerifySinks() (errs []error) {
+	seen := map[Sink]bool{}
+	for _, e := range r.ListScopedLogs() {
+		for _, s := range e._getSinks() {
+			if reflect.TypeOf(s).Comparable() {
+				if seen[s] {
+					continue
+				}
+				seen[s] = true
+			}
+			if err := _verifySink(e, s); err != nil {
+				errs = append(errs, fmt.Errorf("elogging: sink %T of %s: %w", s, e._scope(), err))
+			}
+		}
+	}
+	return
+}
+
+// VerifySinks check the destination of every sink of the registered Elogs and return the failures, to be run at
+// startup so broken log destinations are caught early. Sinks implementing SinkVerifier verify themselves
+// (file sinks check their directory is writable), the others are sent a probe record.
+func VerifySinks() []error {
+	return _defaultRegistry.VerifySinks()
+}
+
+// _verifySink verify the sink or write it a probe record
+func _verifySink(e *Elog, s Sink) error {
+	if v, ok := s.(SinkVerifier); ok {
+		return v.VerifySink()
+	}
+	return s.WriteRecord(_probeRecord(e._scope()))
+}
+
+// _probeRecord return the record written to the sinks to verify them
+func _probeRecord(scope string) *Record {
+	return &Record{Time: time.Now(), Scope: scope, Tag: "PROBE", Msg: "elogging sink probe", level: lInfo}
+}
+
+// VerifySink write a probe record to the output of the sink, whatever its minimum level
+func (s *WriterSink) VerifySink() error {
+	buf := s.enc.Encode(nil, _probeRecord(""))
+	return _lockedWrite(s.out, buf)
+}
+
+// VerifySink check the directory of the files of the sink can be created and written to
+func (s *FileSink) VerifySink() error {
+	dir := filepath.Dir(s.Path(_probeRecord("probe")))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".elogging-probe*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// VerifySink report whether the tail is still open, no probe record is sent to the reader
+func (t *Tail) VerifySink() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return os.ErrClosed
+	}
+	return nil
+}
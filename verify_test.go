@@ -0,0 +1,38 @@
+package elogging
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("unreachable")
+}
+
+func TestVerifySinks(t *testing.T) {
+	r := NewRegistry()
+	elog := r.NewElog("TestVerifySinks", "info", &bytes.Buffer{})
+	b := &bytes.Buffer{}
+	elog.AddSink(NewWriterSink(b, nil, "error"))
+	elog.AddSink(NewFileSink(filepath.Join(t.TempDir(), "{scope}", "{level}.log"), nil, ""))
+	if errs := r.VerifySinks(); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if !strings.Contains(b.String(), "elogging sink probe") {
+		t.Errorf("expected a probe record, got %q", b.String())
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(file, nil, 0644)
+	elog.AddSink(NewWriterSink(failingWriter{}, nil, ""))
+	elog.AddSink(NewFileSink(filepath.Join(file, "{level}.log"), nil, ""))
+	if errs := r.VerifySinks(); len(errs) != 2 {
+		t.Errorf("expected 2 errors, got %v", errs)
+	}
+}
@@ -0,0 +1,89 @@
+package elogging
+
+// FormatVersion is the version of the elogging JSON record layout, bumped when keys are added, renamed or moved,
+// written with every JSON record once SetVersionField is called
+const FormatVersion = "1"
+
+// versionField is the key and value of the version field of the records
+type versionField struct {
+	key   string
+	value string
+}
+
+// _getVersionField return the version field of the registry, nil when there is none
+func (r *Registry) _getVersionField() *versionField {
+	v, _ := r._version.Load().(*versionField)
+	return v
+}
+
+// SetVersionField write a version field in every record of the Elogs of the registry, see the package
+// SetVersionField
+func (r *Registry) SetVersionField(key, value string) {
+	if !r._allow("", "version_field") {
+		return
+	}
+	if value == "" {
+		value = FormatVersion
+	}
+	var v *versionField
+	if key != "" {
+		v = &versionField{key: key, value: value}
+	}
+	from, to := "", ""
+	if old := r._getVersionField(); old != nil {
+		from = old.key + "=" + old.value
+	}
+	if v != nil {
+		to = v.key + "=" + v.value
+	}
+	r._audit("", "version_field", from, to)
+	r._version.Store(v)
+}
+
+// SetVersionField write a version field in every record so consumers can tell the format apart across releases,
+// e.g. SetVersionField("schema", "") or SetVersionField("elog.version", "billing-3"): first in JSON records and as
+// the first key=value field of text records (not in the records written as by the golang log package).
+// An empty value write FormatVersion, an empty key remove the field.
+// Replay does not turn the field into a record field while it is set.
+func SetVersionField(key, value string) {
+	_defaultRegistry.SetVersionField(key, value)
+}
+
+// VersionField return the key and value of the version field of the registry, see the package VersionField
+func (r *Registry) VersionField() (key, value string) {
+	if v := r._getVersionField(); v != nil {
+		return v.key, v.value
+	}
+	return "", ""
+}
+
+// VersionField return the key and value set by SetVersionField, an empty key when there is no version field
+func VersionField() (key, value string) {
+	return _defaultRegistry.VersionField()
+}
+
+// _appendVersionField append the version field of the record and a comma, nothing when it has no version field
+func _appendVersionField(buf []byte, rec *Record) []byte {
+	if rec.version == nil {
+		return buf
+	}
+	buf = _appendJSONString(buf, rec.version.key)
+	buf = append(buf, ':')
+	buf = _appendJSONString(buf, rec.version.value)
+	return append(buf, ',')
+}
+
+// _takeVersionField move the first field of the record with the key of the version field to the version field of
+// the record
+func _takeVersionField(rec *Record, v *versionField) {
+	if v == nil {
+		return
+	}
+	for i := range rec.Fields {
+		if f := &rec.Fields[i]; f.Key == v.key && f.kind == kindString {
+			rec.version = &versionField{key: f.Key, value: f.str}
+			rec.Fields = append(rec.Fields[:i:i], rec.Fields[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestVersionField(t *testing.T) {
+	defer SetVersionField("", "")
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestVersionField", "info", b)
+	elog.SetFlags(ELJSONLog)
+	SetVersionField("schema", "")
+	elog.InfoKV("first", String("k", "v"))
+	SetVersionField("elog.version", "billing-3")
+	elog.Info("second")
+	SetVersionField("", "")
+	elog.Info("third")
+	elog.SetFlags(0)
+	SetVersionField("schema", "")
+	elog.Info("text")
+
+	lines := strings.Split(b.String(), "\n")
+	for i, expected := range []string{
+		`{"schema":"1","scope":"TestVersionField","level":"INFO","msg":"first","k":"v"}`,
+		`{"elog.version":"billing-3","scope":"TestVersionField","level":"INFO","msg":"second"}`,
+		`{"scope":"TestVersionField","level":"INFO","msg":"third"}`,
+	} {
+		if lines[i] != expected {
+			t.Errorf("expected %s, got %s", expected, lines[i])
+		}
+	}
+	if lines[3] != "TestVersionField (INFO) text schema=1" {
+		t.Errorf("expected the version field in the text record, got %q", lines[3])
+	}
+	if key, value := VersionField(); key != "schema" || value != FormatVersion {
+		t.Errorf("unexpected version field %s=%s", key, value)
+	}
+}
+
+func TestVersionFieldReplay(t *testing.T) {
+	defer SetVersionField("", "")
+	SetVersionField("schema", "")
+	written := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestVersionFieldReplay", "info", written)
+	elog.SetFlags(ELJSONLog)
+	elog.InfoKV("login", String("user", "bob"))
+
+	replayed := &bytes.Buffer{}
+	if _, err := Replay(bytes.NewReader(written.Bytes()), FormatJSON, NewWriterSink(replayed, FlagsEncoder(ELJSONLog), "")); err != nil {
+		t.Fatal(err)
+	}
+	if replayed.String() != written.String() {
+		t.Errorf("expected %q, got %q", written.String(), replayed.String())
+	}
+}
+
+func TestVersionFieldRegistry(t *testing.T) {
+	r := NewRegistry()
+	b := &bytes.Buffer{}
+	elog := r.NewElog("TestVersionFieldRegistry", "info", b)
+	elog.SetFlags(0)
+	r.SetVersionField("schema", "")
+	token := r.Freeze()
+	r.SetVersionField("schema", "2")
+	if key, value := r.VersionField(); key != "schema" || value != FormatVersion {
+		t.Errorf("frozen version field changed to %s=%s", key, value)
+	}
+	if key, _ := VersionField(); key != "" {
+		t.Errorf("unexpected version field %s in the default registry", key)
+	}
+	elog.InfoKV("login", String("user", "bob"))
+	if expected := "TestVersionFieldRegistry (INFO) login schema=1 user=bob\n"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			elog.Info("concurrent")
+		}
+	}()
+	token.Do(func() { r.SetVersionField("", "") })
+	<-done
+	if key, _ := r.VersionField(); key != "" {
+		t.Errorf("version field %s not removed", key)
+	}
+}
@@ -0,0 +1,206 @@
+package elogging
+
+import "fmt"
+
+// ElogView is a read-only view of an Elog: it logs through the Elog but can not reconfigure it
+// (no SetLevel, SetFlags, ModifyParams, hooks, sinks or Clear), see Elog.ReadOnly
+type ElogView struct {
+	e *Elog
+}
+
+// ReadOnly return a view of the Elog exposing its logging methods only, to inject into plugins or
+// request handlers which must log through the Elog without being able to reconfigure it
+func (e *Elog) ReadOnly() ElogView {
+	return ElogView{e: e}
+}
+
+// Scope retrieve the scope of the Elog
+func (v ElogView) Scope() string {
+	return v.e._scope()
+}
+
+// GetLevel retrieve the current level of the Elog
+func (v ElogView) GetLevel() string {
+	return v.e._scopeLevel().String()
+}
+
+// Explain describe whether a record at level would be emitted, see Elog.Explain
+func (v ElogView) Explain(level string) string {
+	return v.e.Explain(level)
+}
+
+// Print see Elog.Print
+func (v ElogView) Print(args ...interface{}) {
+	if v.e._dropCleared("Print") {
+		return
+	}
+	if !v.e._printEnabled() {
+		v.e._debugDropped(lPrint)
+		return
+	}
+	v.e._output(2, lPrint, "Print", fmt.Sprint(v.e._reg._renderArgs(args)...))
+}
+
+// Printf see Elog.Printf
+func (v ElogView) Printf(format string, args ...interface{}) {
+	if v.e._dropCleared("Printf") {
+		return
+	}
+	if !v.e._printEnabled() {
+		v.e._debugDropped(lPrint)
+		return
+	}
+	v.e._output(2, lPrint, "Printf", fmt.Sprintf(format, v.e._reg._renderArgs(args)...))
+}
+
+// Println see Elog.Println
+func (v ElogView) Println(args ...interface{}) {
+	if v.e._dropCleared("Println") {
+		return
+	}
+	if !v.e._printEnabled() {
+		v.e._debugDropped(lPrint)
+		return
+	}
+	v.e._output(2, lPrint, "Println", fmt.Sprintln(v.e._reg._renderArgs(args)...))
+}
+
+// Error print prefixed (Error) log lines with level Error
+func (v ElogView) Error(args ...interface{}) {
+	v.e._log(2, lError, args...)
+}
+
+// Errorf print prefixed (Error) formatted log lines with level Error
+func (v ElogView) Errorf(format string, args ...interface{}) {
+	v.e._logf(2, lError, format, args...)
+}
+
+// ErrorKV print prefixed (Error) log lines with level Error and the given fields
+func (v ElogView) ErrorKV(msg string, fields ...FieldT) {
+	v.e._logKV(2, lError, msg, fields)
+}
+
+// ErrorKVf print prefixed (Error) log lines with level Error and the key/value fields of args, see Elog.ErrorKVf
+func (v ElogView) ErrorKVf(msg string, args ...interface{}) {
+	v.e._logPairs(2, lError, "ErrorKVf", msg, args)
+}
+
+// Warn print prefixed (Warning) log lines with level Warning
+func (v ElogView) Warn(args ...interface{}) {
+	v.e._log(2, lWarn, args...)
+}
+
+// Warnf print prefixed (Warning) formatted log lines with level Warning
+func (v ElogView) Warnf(format string, args ...interface{}) {
+	v.e._logf(2, lWarn, format, args...)
+}
+
+// WarnKV print prefixed (Warning) log lines with level Warning and the given fields
+func (v ElogView) WarnKV(msg string, fields ...FieldT) {
+	v.e._logKV(2, lWarn, msg, fields)
+}
+
+// WarnKVf print prefixed (Warning) log lines with level Warning and the key/value fields of args, see Elog.WarnKVf
+func (v ElogView) WarnKVf(msg string, args ...interface{}) {
+	v.e._logPairs(2, lWarn, "WarnKVf", msg, args)
+}
+
+// Info print prefixed (Info) log lines with level Info
+func (v ElogView) Info(args ...interface{}) {
+	v.e._log(2, lInfo, args...)
+}
+
+// Infof print prefixed (Info) formatted log lines with level Info
+func (v ElogView) Infof(format string, args ...interface{}) {
+	v.e._logf(2, lInfo, format, args...)
+}
+
+// InfoKV print prefixed (Info) log lines with level Info and the given fields
+func (v ElogView) InfoKV(msg string, fields ...FieldT) {
+	v.e._logKV(2, lInfo, msg, fields)
+}
+
+// InfoKVf print prefixed (Info) log lines with level Info and the key/value fields of args, see Elog.InfoKVf
+func (v ElogView) InfoKVf(msg string, args ...interface{}) {
+	v.e._logPairs(2, lInfo, "InfoKVf", msg, args)
+}
+
+// Verbose print prefixed (Verbose) log lines with level Verbose
+func (v ElogView) Verbose(args ...interface{}) {
+	v.e._log(2, lVerbose, args...)
+}
+
+// Verbosef print prefixed (Verbose) formatted log lines with level Verbose
+func (v ElogView) Verbosef(format string, args ...interface{}) {
+	v.e._logf(2, lVerbose, format, args...)
+}
+
+// VerboseKV print prefixed (Verbose) log lines with level Verbose and the given fields
+func (v ElogView) VerboseKV(msg string, fields ...FieldT) {
+	v.e._logKV(2, lVerbose, msg, fields)
+}
+
+// VerboseKVf print prefixed (Verbose) log lines with level Verbose and the key/value fields of args, see Elog.VerboseKVf
+func (v ElogView) VerboseKVf(msg string, args ...interface{}) {
+	v.e._logPairs(2, lVerbose, "VerboseKVf", msg, args)
+}
+
+// Trace print prefixed (Trace) log lines with level Trace
+func (v ElogView) Trace(args ...interface{}) {
+	v.e._log(2, lTrace, args...)
+}
+
+// Tracef print prefixed (Trace) formatted log lines with level Trace
+func (v ElogView) Tracef(format string, args ...interface{}) {
+	v.e._logf(2, lTrace, format, args...)
+}
+
+// TraceKV print prefixed (Trace) log lines with level Trace and the given fields
+func (v ElogView) TraceKV(msg string, fields ...FieldT) {
+	v.e._logKV(2, lTrace, msg, fields)
+}
+
+// TraceKVf print prefixed (Trace) log lines with level Trace and the key/value fields of args, see Elog.TraceKVf
+func (v ElogView) TraceKVf(msg string, args ...interface{}) {
+	v.e._logPairs(2, lTrace, "TraceKVf", msg, args)
+}
+
+// Cond see Elog.Cond
+func (v ElogView) Cond(cond bool, trueLevel, falseLevel string, args ...interface{}) {
+	if level := _condLevel(cond, trueLevel, falseLevel); level != lDisabled {
+		v.e._log(2, level, args...)
+	}
+}
+
+// Condf see Elog.Condf
+func (v ElogView) Condf(cond bool, trueLevel, falseLevel string, format string, args ...interface{}) {
+	if level := _condLevel(cond, trueLevel, falseLevel); level != lDisabled {
+		v.e._logf(2, level, format, args...)
+	}
+}
+
+// CheckErr see Elog.CheckErr
+func (v ElogView) CheckErr(err error, msg string) bool {
+	if err == nil {
+		return false
+	}
+	v.e._log(2, lError, msg, ": ", err)
+	return true
+}
+
+// WrapErr see Elog.WrapErr
+func (v ElogView) WrapErr(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	v.e._log(2, lError, msg, ": ", err)
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// LogIfError see Elog.LogIfError
+func (v ElogView) LogIfError(errp *error, msg string) {
+	if errp == nil || *errp == nil {
+		return
+	}
+	v.e._log(2, lError, msg, ": ", *errp)
+}
@@ -0,0 +1,37 @@
+package elogging
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	b := &bytes.Buffer{}
+	elog := NewEphemeralElog("TestReadOnly", "info", b)
+	elog.SetFlags(log.Lshortfile | log.Lmsgprefix)
+	v := elog.ReadOnly()
+	v.Info("info")
+	v.Tracef("%s", "hidden")
+	v.WarnKV("warn", Int("n", 1))
+	v.CheckErr(errors.New("boom"), "failed")
+	if got := b.String(); got != "view_test.go:17: TestReadOnly (INFO) info\n"+
+		"view_test.go:19: TestReadOnly (WARN) warn n=1\n"+
+		"view_test.go:20: TestReadOnly (ERROR) failed: boom\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+	if v.Scope() != "TestReadOnly" || v.GetLevel() != "Info" {
+		t.Error("unexpected view getters")
+	}
+	for _, m := range []string{"SetLevel", "SetFlags", "ModifyParams", "Clear", "AddSink", "AddHook"} {
+		if _, ok := reflect.TypeOf(v).MethodByName(m); ok {
+			t.Errorf("view exposes %s", m)
+		}
+	}
+	if !strings.Contains(v.Explain("info"), "emitted") {
+		t.Error("unexpected explanation")
+	}
+}
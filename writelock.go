@@ -0,0 +1,77 @@
+package elogging
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	_writerLocks   sync.Map // locks serializing the writes, by writer identity, see _writerLock
+	_atomicCount   int32    // number of atomic writers, the lookup is skipped when there is none
+	_atomicMu      sync.RWMutex
+	_atomicWriters = map[io.Writer]bool{}
+)
+
+// SetAtomicWriter declare (on true) or no longer (on false) that the Write method of w is atomic and safe
+// for concurrent use, such as a file opened with O_APPEND or a journald or syslog datagram socket.
+//
+// Writes of records are serialized by a lock shared by every Elog and sink writing to the same writer, so concurrent
+// loggers never interleave their records even when w is not safe for concurrent use. Records written to an atomic
+// writer skip this lock. The writer type must be comparable (a pointer, as most writers are).
+func SetAtomicWriter(w io.Writer, on bool) error {
+	if w == nil || !reflect.TypeOf(w).Comparable() {
+		return fmt.Errorf("elogging: writer %T can not be declared atomic", w)
+	}
+	_atomicMu.Lock()
+	defer _atomicMu.Unlock()
+	if on && !_atomicWriters[w] {
+		_atomicWriters[w] = true
+		atomic.AddInt32(&_atomicCount, 1)
+	} else if !on && _atomicWriters[w] {
+		delete(_atomicWriters, w)
+		atomic.AddInt32(&_atomicCount, -1)
+	}
+	return nil
+}
+
+// _isAtomicWriter report whether w was declared atomic with SetAtomicWriter
+func _isAtomicWriter(w io.Writer) bool {
+	if atomic.LoadInt32(&_atomicCount) == 0 || !reflect.TypeOf(w).Comparable() {
+		return false
+	}
+	_atomicMu.RLock()
+	defer _atomicMu.RUnlock()
+	return _atomicWriters[w]
+}
+
+// _writerLock return the lock serializing the writes to w, a writer is mapped to its own lock by its identity so all
+// the Elogs and sinks sharing a writer (in any registry) use the same lock and a writer logging to another writer
+// in its Write method does not wait on its own lock
+func _writerLock(w io.Writer) *sync.Mutex {
+	v := reflect.ValueOf(w)
+	var id uintptr
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		id = v.Pointer()
+	default: // writers of a non pointer type are identified by their type
+		id = reflect.ValueOf(v.Type()).Pointer()
+	}
+	if mu, ok := _writerLocks.Load(id); ok {
+		return mu.(*sync.Mutex)
+	}
+	mu, _ := _writerLocks.LoadOrStore(id, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
+
+// _lockedWrite write a whole rendered record to w, serialized with the other writes to w unless w is atomic
+func _lockedWrite(w io.Writer, buf []byte) error {
+	if w != nil && !_isAtomicWriter(w) {
+		mu := _writerLock(w)
+		mu.Lock()
+		defer mu.Unlock()
+	}
+	return _writeRecord(w, buf)
+}
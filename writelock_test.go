@@ -0,0 +1,113 @@
+package elogging
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// racyWriter detect concurrent calls to Write
+type racyWriter struct {
+	bytes.Buffer
+	busy       int32
+	overlapped bool
+}
+
+func (w *racyWriter) Write(p []byte) (int, error) {
+	if w.busy++; w.busy > 1 {
+		w.overlapped = true
+	}
+	defer func() { w.busy-- }()
+	return w.Buffer.Write(p)
+}
+
+func TestSharedWriterLock(t *testing.T) {
+	w := &racyWriter{}
+	sink := NewWriterSink(w, nil, "")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		elog := NewElog("TestSharedWriterLock", "info", w)
+		defer elog.Clear()
+		elog.AddSink(sink)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				elog.Info("msg")
+			}
+		}()
+	}
+	wg.Wait()
+	if w.overlapped {
+		t.Error("concurrent writes to a shared writer")
+	}
+	if n := strings.Count(w.String(), "\n"); n != 800 {
+		t.Errorf("expected 800 records, got %d", n)
+	}
+	if _writerLock(w) != _writerLock(w) || _writerLock(valueWriter{}) != _writerLock(valueWriter{b: []byte("x")}) {
+		t.Error("unstable writer lock")
+	}
+}
+
+type valueWriter struct{ b []byte }
+
+func (valueWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+func TestSetAtomicWriter(t *testing.T) {
+	defer Reset()
+	w := &bytes.Buffer{}
+	if _isAtomicWriter(w) {
+		t.Error("writer atomic by default")
+	}
+	if err := SetAtomicWriter(w, true); err != nil || !_isAtomicWriter(w) {
+		t.Errorf("writer not atomic: %v", err)
+	}
+	if _isAtomicWriter(valueWriter{}) {
+		t.Error("non comparable writer atomic")
+	}
+	if err := SetAtomicWriter(valueWriter{}, true); err == nil {
+		t.Error("expected an error for a non comparable writer")
+	}
+	SetAtomicWriter(w, false)
+	if _isAtomicWriter(w) || _atomicCount != 0 {
+		t.Error("writer still atomic")
+	}
+}
+
+// _loggingWriter log a record to other writers in its Write method
+type _loggingWriter struct {
+	bytes.Buffer
+	inner []*Elog
+}
+
+func (w *_loggingWriter) Write(p []byte) (int, error) {
+	for _, e := range w.inner {
+		e.Info("written")
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestWriterLockPerWriter(t *testing.T) {
+	r := NewRegistry()
+	// with locks shared by unrelated writers, one of the inner writers would likely share the lock of the outer one
+	w := &_loggingWriter{}
+	for i := 0; i < 512; i++ {
+		w.inner = append(w.inner, r.NewElog("inner", "info", &bytes.Buffer{}))
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.NewElog("outer", "info", w).Info("msg")
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock writing to a writer logging to another writer")
+	}
+	a, b := &bytes.Buffer{}, &bytes.Buffer{}
+	if _writerLock(a) == _writerLock(b) {
+		t.Error("unrelated writers share a lock")
+	}
+}